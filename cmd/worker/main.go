@@ -10,8 +10,11 @@ import (
 	"git-repository-visualizer/internal/auth"
 	"git-repository-visualizer/internal/config"
 	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/git"
+	"git-repository-visualizer/internal/language"
 	"git-repository-visualizer/internal/queue"
 	"git-repository-visualizer/internal/redis"
+	"git-repository-visualizer/internal/storage"
 	"git-repository-visualizer/internal/worker"
 
 	"github.com/joho/godotenv"
@@ -52,8 +55,21 @@ func main() {
 	authRegistry := auth.NewRegistry()
 	authRegistry.InitializeProviders(cfg.Auth)
 
+	// Initialize git host registry for cloning
+	gitServices := git.NewRegistry()
+	if err := gitServices.InitializeServices(cfg.Git); err != nil {
+		log.Fatalf("Failed to initialize git services: %v", err)
+	}
+
 	// Create job handler
-	handler := worker.NewJobHandler(db, cfg.Worker.StoragePath, authRegistry)
+	processOpts := git.ProcessOptions{
+		Blame:          cfg.Worker.BlameOwnership,
+		DiffWorkers:    cfg.Worker.DiffWorkers,
+		Languages:      language.NewDetector(cfg.Language),
+		GrowthInterval: cfg.Worker.GrowthInterval,
+	}
+	clones := storage.NewManager(db, cfg.Worker.StoragePath, int64(cfg.Worker.StorageQuotaMB)*1024*1024)
+	handler := worker.NewJobHandler(db, clones, authRegistry, gitServices, processOpts)
 
 	// Create consumer
 	consumer := queue.NewConsumer(
@@ -45,6 +45,10 @@ func (p *gitHubProvider) Exchange(ctx context.Context, code string) (*oauth2.Tok
 	return p.config.Exchange(ctx, code)
 }
 
+func (p *gitHubProvider) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return p.config.TokenSource(ctx, token)
+}
+
 func (p *gitHubProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*Profile, error) {
 	client := p.config.Client(ctx, token)
 	resp, err := client.Get("https://api.github.com/user")
@@ -43,6 +43,10 @@ func (p *googleProvider) Exchange(ctx context.Context, code string) (*oauth2.Tok
 	return p.config.Exchange(ctx, code)
 }
 
+func (p *googleProvider) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return p.config.TokenSource(ctx, token)
+}
+
 func (p *googleProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*Profile, error) {
 	client := p.config.Client(ctx, token)
 	resp, err := client.Get("https://www.googleapis.com/oauth2/v3/userinfo")
@@ -28,6 +28,8 @@ type Provider interface {
 	FetchProfile(ctx context.Context, token *oauth2.Token) (*Profile, error)
 	// FetchRepositories returns a list of repositories for the authenticated user (optional)
 	FetchRepositories(ctx context.Context, token *oauth2.Token) ([]RemoteRepo, error)
+	// TokenSource returns a source that refreshes the token once it has expired
+	TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource
 }
 
 // RemoteRepo represents a repository found on a git hosting provider
@@ -24,6 +24,9 @@ func (m *mockProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*
 func (m *mockProvider) FetchRepositories(ctx context.Context, token *oauth2.Token) ([]RemoteRepo, error) {
 	return []RemoteRepo{{ID: "repo1", Name: "Repo 1"}}, nil
 }
+func (m *mockProvider) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(token)
+}
 
 func TestRegistry(t *testing.T) {
 	r := NewRegistry()
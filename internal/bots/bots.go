@@ -0,0 +1,128 @@
+package bots
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MaxPatternLength bounds the length of a configured bot pattern
+const MaxPatternLength = 255
+
+// knownBots are the account names of common automation, matched against the author name
+// and the local part of the email, e.g. "Renovate <renovate@whitesourcesoftware.com>"
+var knownBots = map[string]bool{
+	"dependabot":           true,
+	"dependabot-preview":   true,
+	"renovate":             true,
+	"renovate-bot":         true,
+	"greenkeeper":          true,
+	"snyk-bot":             true,
+	"github-actions":       true,
+	"pre-commit-ci":        true,
+	"imgbot":               true,
+	"allcontributors":      true,
+	"semantic-release-bot": true,
+	"mergify":              true,
+	"codecov":              true,
+	"deepsource-autofix":   true,
+	"whitesource-bolt":     true,
+}
+
+// knownEmails are bot addresses whose name and local part are not telling
+var knownEmails = map[string]bool{
+	"action@github.com": true, // github-actions
+}
+
+var (
+	// botSuffix matches GitHub App accounts ("dependabot[bot]") and names ending in a separate
+	// "bot" word ("Renovate Bot", "release_bot"), but not names like "Abbot"
+	botSuffix = regexp.MustCompile(`(?i)(\[bot\]|(^|[\s._-])bot)$`)
+
+	// noReply matches the local part of no-reply addresses. Note that GitHub's private addresses
+	// (<id>+<login>@users.noreply.github.com) belong to people and only match through their login.
+	noReply = regexp.MustCompile(`(?i)^(no-?reply|do-?not-?reply)$`)
+)
+
+// Detector tells automated accounts apart from people, using the built-in heuristics and the
+// patterns configured for a repository
+type Detector struct {
+	patterns []*regexp.Regexp
+}
+
+// NewDetector creates a detector matching the given patterns on top of the built-in heuristics.
+// Patterns are case-insensitive globs matched against the whole email or name, where "*" stands
+// for any run of characters, e.g. "ci-*@example.com" or "Jenkins". Empty patterns are ignored.
+func NewDetector(patterns []string) *Detector {
+	d := &Detector{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		glob := strings.ReplaceAll(regexp.QuoteMeta(p), `\*`, `.*`)
+		d.patterns = append(d.patterns, regexp.MustCompile(`(?i)^`+glob+`$`))
+	}
+	return d
+}
+
+// Default returns a detector using the built-in heuristics only
+func Default() *Detector {
+	return &Detector{}
+}
+
+// Patterns returns the configured patterns as the expressions they match with, sorted. Patterns
+// differing only in case or repeated are returned once, as they match the same identities.
+func (d *Detector) Patterns() []string {
+	if d == nil {
+		return nil
+	}
+	seen := make(map[string]bool, len(d.patterns))
+	patterns := make([]string, 0, len(d.patterns))
+	for _, p := range d.patterns {
+		expr := strings.ToLower(p.String())
+		if !seen[expr] {
+			seen[expr] = true
+			patterns = append(patterns, expr)
+		}
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// IsBot reports whether the identity belongs to an automated account. A nil detector applies
+// the built-in heuristics.
+func (d *Detector) IsBot(name, email string) bool {
+	name = strings.TrimSpace(name)
+	email = strings.TrimSpace(email)
+
+	local := email
+	if i := strings.LastIndex(local, "@"); i >= 0 {
+		local = local[:i]
+	}
+	// GitHub's private addresses prefix the login with the account ID
+	login := local
+	if i := strings.LastIndex(login, "+"); i >= 0 {
+		login = login[i+1:]
+	}
+
+	if botSuffix.MatchString(name) || botSuffix.MatchString(login) {
+		return true
+	}
+	if noReply.MatchString(local) {
+		return true
+	}
+	if knownBots[strings.ToLower(name)] || knownBots[strings.ToLower(login)] || knownEmails[strings.ToLower(email)] {
+		return true
+	}
+
+	if d == nil {
+		return false
+	}
+	for _, p := range d.patterns {
+		if p.MatchString(email) || p.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package bots
+
+import "testing"
+
+func TestIsBot(t *testing.T) {
+	d := Default()
+
+	tests := []struct {
+		name     string
+		email    string
+		expected bool
+	}{
+		{"dependabot[bot]", "49699333+dependabot[bot]@users.noreply.github.com", true},
+		{"github-actions[bot]", "41898282+github-actions[bot]@users.noreply.github.com", true},
+		{"github-actions", "action@github.com", true},
+		{"Renovate Bot", "bot@renovateapp.com", true},
+		{"renovate", "renovate@whitesourcesoftware.com", true},
+		{"semantic-release-bot", "semantic-release-bot@martynus.net", true},
+		{"Release Automation", "noreply@example.com", true},
+		{"Deploy", "do-not-reply@example.com", true},
+		{"Jane Doe", "1234+janedoe@users.noreply.github.com", false},
+		{"Jane Abbot", "jane.abbot@example.com", false},
+		{"Botond Kiss", "botond@example.com", false},
+		{"John", "john@example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := d.IsBot(tt.name, tt.email); got != tt.expected {
+			t.Errorf("%s <%s>: expected %v, got %v", tt.name, tt.email, tt.expected, got)
+		}
+	}
+}
+
+func TestIsBotWithPatterns(t *testing.T) {
+	d := NewDetector([]string{"ci-*@example.com", "Jenkins", " "})
+
+	tests := []struct {
+		name     string
+		email    string
+		expected bool
+	}{
+		{"CI", "ci-deploy@example.com", true},
+		{"CI", "CI-Release@Example.com", true},
+		{"jenkins", "builds@example.com", true},
+		{"Jenkins Admin", "admin@example.com", false},
+		{"Chris", "chris@example.com", false},
+		{"dependabot[bot]", "support@github.com", true},
+	}
+
+	for _, tt := range tests {
+		if got := d.IsBot(tt.name, tt.email); got != tt.expected {
+			t.Errorf("%s <%s>: expected %v, got %v", tt.name, tt.email, tt.expected, got)
+		}
+	}
+
+	var none *Detector
+	if !none.IsBot("renovate[bot]", "") {
+		t.Error("nil detector should apply the built-in heuristics")
+	}
+}
+
+func TestPatterns(t *testing.T) {
+	d := NewDetector([]string{"Jenkins", "ci-*@example.com", "jenkins", " "})
+	reordered := NewDetector([]string{"ci-*@example.com", "JENKINS"})
+
+	patterns := d.Patterns()
+	if len(patterns) != 2 {
+		t.Fatalf("expected 2 patterns, got %v", patterns)
+	}
+	other := reordered.Patterns()
+	if len(other) != len(patterns) || other[0] != patterns[0] || other[1] != patterns[1] {
+		t.Errorf("expected the same patterns regardless of order and case, got %v and %v", patterns, other)
+	}
+
+	if len(Default().Patterns()) != 0 {
+		t.Error("expected no patterns for the default detector")
+	}
+	var none *Detector
+	if none.Patterns() != nil {
+		t.Error("expected no patterns for a nil detector")
+	}
+}
@@ -7,13 +7,15 @@ type ProviderConfig struct {
 }
 
 type AuthConfig struct {
-	JWTSecret string
-	Providers map[string]ProviderConfig
+	JWTSecret   string
+	Providers   map[string]ProviderConfig
+	AdminEmails []string // Users allowed to call the /admin endpoints
 }
 
 func loadAuthConfig() AuthConfig {
 	return AuthConfig{
-		JWTSecret: getEnv("JWT_SECRET", "super-secret-key-change-it"),
+		JWTSecret:   getEnv("JWT_SECRET", "super-secret-key-change-it"),
+		AdminEmails: getEnvList("ADMIN_EMAILS"),
 		Providers: map[string]ProviderConfig{
 			"google": {
 				ClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
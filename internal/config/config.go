@@ -3,6 +3,7 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -14,6 +15,8 @@ type Config struct {
 	Worker   WorkerConfig
 	HTTP     HTTPConfig
 	Auth     AuthConfig
+	Git      GitConfig
+	Language LanguageConfig
 }
 
 // Load reads all configuration from environment variables and returns the Config
@@ -25,6 +28,8 @@ func Load() *Config {
 		Worker:   loadWorkerConfig(),
 		HTTP:     loadHTTPConfig(),
 		Auth:     loadAuthConfig(),
+		Git:      loadGitConfig(),
+		Language: loadLanguageConfig(),
 	}
 }
 
@@ -61,3 +66,28 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	}
 	return value
 }
+
+// getEnvMap parses a comma-separated list of key=value pairs, e.g. "a=1,b=2"
+func getEnvMap(key string) map[string]string {
+	result := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if !ok || k == "" || v == "" {
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// getEnvList parses a comma-separated list, e.g. "a,b", dropping empty entries
+func getEnvList(key string) []string {
+	var result []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,12 @@
+package config
+
+type GitConfig struct {
+	// Hosts maps self-hosted domains to a service type, e.g. "git.example.com" -> "gitlab"
+	Hosts map[string]string
+}
+
+func loadGitConfig() GitConfig {
+	return GitConfig{
+		Hosts: getEnvMap("GIT_HOSTS"),
+	}
+}
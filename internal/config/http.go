@@ -1,13 +1,15 @@
 package config
 
 type HTTPConfig struct {
-	LIMIT  int
-	OFFSET int
+	LIMIT       int
+	OFFSET      int
+	MaxBundleMB int // Largest git bundle accepted for upload
 }
 
 func loadHTTPConfig() HTTPConfig {
 	return HTTPConfig{
-		LIMIT:  getEnvInt("HTTP_LIMIT", 10),
-		OFFSET: getEnvInt("HTTP_OFFSET", 0),
+		LIMIT:       getEnvInt("HTTP_LIMIT", 10),
+		OFFSET:      getEnvInt("HTTP_OFFSET", 0),
+		MaxBundleMB: getEnvInt("HTTP_MAX_BUNDLE_MB", 1024),
 	}
 }
\ No newline at end of file
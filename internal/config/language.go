@@ -0,0 +1,15 @@
+package config
+
+type LanguageConfig struct {
+	// Extensions maps additional file extensions to a language, e.g. ".vue" -> "Vue"
+	Extensions map[string]string
+	// Filenames maps additional exact file names to a language, e.g. "Tiltfile" -> "Starlark"
+	Filenames map[string]string
+}
+
+func loadLanguageConfig() LanguageConfig {
+	return LanguageConfig{
+		Extensions: getEnvMap("LANGUAGE_EXTENSIONS"),
+		Filenames:  getEnvMap("LANGUAGE_FILENAMES"),
+	}
+}
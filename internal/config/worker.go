@@ -5,15 +5,23 @@ import (
 )
 
 type WorkerConfig struct {
-	Concurrency  int
-	StoragePath  string
-	PollInterval time.Duration
+	Concurrency    int
+	StoragePath    string // Clones, and the bundles uploaded through the API, which must share it
+	StorageQuotaMB int    // Total size of the clones under StoragePath before the least recently used are evicted (0 = unlimited)
+	PollInterval   time.Duration
+	BlameOwnership bool   // Blame every file at HEAD to record line ownership (slow on large repositories)
+	DiffWorkers    int    // Goroutines computing commit diffs per indexing job
+	GrowthInterval string // Growth snapshot sampling: "week", "month" or "off"
 }
 
 func loadWorkerConfig() WorkerConfig {
 	return WorkerConfig{
-		Concurrency:  getEnvInt("WORKER_CONCURRENCY", 5),
-		StoragePath:  getEnv("GIT_STORAGE_PATH", "/var/lib/git-analytics/repos"),
-		PollInterval: getEnvDuration("POLL_INTERVAL", 6*time.Hour),
+		Concurrency:    getEnvInt("WORKER_CONCURRENCY", 5),
+		StoragePath:    getEnv("GIT_STORAGE_PATH", "/var/lib/git-analytics/repos"),
+		StorageQuotaMB: getEnvInt("GIT_STORAGE_QUOTA_MB", 0),
+		PollInterval:   getEnvDuration("POLL_INTERVAL", 6*time.Hour),
+		BlameOwnership: getEnv("WORKER_BLAME_OWNERSHIP", "false") == "true",
+		DiffWorkers:    getEnvInt("WORKER_DIFF_CONCURRENCY", 4),
+		GrowthInterval: getEnv("WORKER_GROWTH_INTERVAL", "month"),
 	}
 }
@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertIndexCheckpoint records the position of a repository's unfinished full index
+func (db *DB) UpsertIndexCheckpoint(ctx context.Context, cp *IndexCheckpoint) error {
+	query := `
+		INSERT INTO index_checkpoints (repository_id, fingerprint, last_commit, batches, commits_processed, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW())
+		ON CONFLICT (repository_id)
+		DO UPDATE SET
+			fingerprint = EXCLUDED.fingerprint,
+			last_commit = EXCLUDED.last_commit,
+			batches = EXCLUDED.batches,
+			commits_processed = EXCLUDED.commits_processed,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	err := db.pool.QueryRow(ctx, query,
+		cp.RepositoryID, cp.Fingerprint, cp.LastCommit, cp.Batches, cp.CommitsProcessed,
+	).Scan(&cp.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert index checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// GetIndexCheckpoint retrieves the checkpoint of a repository's unfinished full index
+func (db *DB) GetIndexCheckpoint(ctx context.Context, repositoryID int64) (*IndexCheckpoint, error) {
+	query := `
+		SELECT repository_id, fingerprint, last_commit, batches, commits_processed, updated_at
+		FROM index_checkpoints
+		WHERE repository_id = $1
+	`
+
+	cp := &IndexCheckpoint{}
+	err := db.pool.QueryRow(ctx, query, repositoryID).Scan(
+		&cp.RepositoryID, &cp.Fingerprint, &cp.LastCommit, &cp.Batches, &cp.CommitsProcessed, &cp.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get index checkpoint: %w", err)
+	}
+
+	return cp, nil
+}
+
+// DeleteIndexCheckpoint removes the checkpoint of a repository, once its index completed or restarts
+func (db *DB) DeleteIndexCheckpoint(ctx context.Context, repositoryID int64) error {
+	_, err := db.pool.Exec(ctx, `DELETE FROM index_checkpoints WHERE repository_id = $1`, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete index checkpoint: %w", err)
+	}
+
+	return nil
+}
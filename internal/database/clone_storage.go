@@ -0,0 +1,112 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// UpsertCloneStorage records the size of a repository's clone and marks it as just used
+func (db *DB) UpsertCloneStorage(ctx context.Context, c *CloneStorage) error {
+	query := `
+		INSERT INTO clone_storage (repository_id, path, size_bytes, last_used_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (repository_id)
+		DO UPDATE SET
+			path = EXCLUDED.path,
+			size_bytes = EXCLUDED.size_bytes,
+			last_used_at = NOW(),
+			updated_at = NOW()
+		RETURNING last_used_at, updated_at
+	`
+
+	err := db.pool.QueryRow(ctx, query, c.RepositoryID, c.Path, c.SizeBytes).Scan(&c.LastUsedAt, &c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert clone storage: %w", err)
+	}
+
+	return nil
+}
+
+// GetTotalCloneStorage returns the disk usage of all tracked clones
+func (db *DB) GetTotalCloneStorage(ctx context.Context) (int64, error) {
+	var total int64
+	err := db.pool.QueryRow(ctx, `SELECT COALESCE(SUM(size_bytes), 0) FROM clone_storage`).Scan(&total)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get total clone storage: %w", err)
+	}
+	return total, nil
+}
+
+// ListEvictableClones returns the clones that may be removed to free space, least recently used first.
+// Clones of repositories being indexed are left out.
+func (db *DB) ListEvictableClones(ctx context.Context) ([]*CloneStorage, error) {
+	query := `
+		SELECT cs.repository_id, cs.path, cs.size_bytes, cs.last_used_at, cs.updated_at
+		FROM clone_storage cs
+		JOIN repositories r ON r.id = cs.repository_id
+		WHERE r.status <> $1
+		ORDER BY cs.last_used_at ASC, cs.repository_id ASC
+	`
+
+	rows, err := db.pool.Query(ctx, query, StatusIndexing)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list evictable clones: %w", err)
+	}
+	defer rows.Close()
+
+	var clones []*CloneStorage
+	for rows.Next() {
+		c := &CloneStorage{}
+		if err := rows.Scan(&c.RepositoryID, &c.Path, &c.SizeBytes, &c.LastUsedAt, &c.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan clone storage: %w", err)
+		}
+		clones = append(clones, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return clones, nil
+}
+
+// ListCloneStorageUsage returns the disk usage of every tracked clone, largest first
+func (db *DB) ListCloneStorageUsage(ctx context.Context) ([]*CloneStorageUsage, error) {
+	query := `
+		SELECT cs.repository_id, cs.path, cs.size_bytes, cs.last_used_at, cs.updated_at,
+		       r.url, r.name, r.status
+		FROM clone_storage cs
+		JOIN repositories r ON r.id = cs.repository_id
+		ORDER BY cs.size_bytes DESC, cs.repository_id ASC
+	`
+
+	rows, err := db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clone storage usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []*CloneStorageUsage{}
+	for rows.Next() {
+		u := &CloneStorageUsage{}
+		if err := rows.Scan(&u.RepositoryID, &u.Path, &u.SizeBytes, &u.LastUsedAt, &u.UpdatedAt, &u.URL, &u.Name, &u.Status); err != nil {
+			return nil, fmt.Errorf("failed to scan clone storage usage: %w", err)
+		}
+		usage = append(usage, u)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return usage, nil
+}
+
+// DeleteCloneStorage stops tracking a repository's clone
+func (db *DB) DeleteCloneStorage(ctx context.Context, repositoryID int64) error {
+	_, err := db.pool.Exec(ctx, `DELETE FROM clone_storage WHERE repository_id = $1`, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete clone storage: %w", err)
+	}
+	return nil
+}
@@ -10,12 +10,21 @@ import (
 // UpsertCommit inserts or updates a single commit
 func (db *DB) UpsertCommit(ctx context.Context, commit *Commit) error {
 	query := `
-		INSERT INTO commits (repository_id, hash, author_email, author_name, message, committed_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO commits (repository_id, hash, author_email, author_name, message, committed_at, parent_hashes, is_merge, committer_email, committer_name, committer_at, commit_type, commit_scope,
+			signature_type, signature_status, signature_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (repository_id, hash)
 		DO UPDATE SET
 			author_name = EXCLUDED.author_name,
-			message = EXCLUDED.message
+			message = EXCLUDED.message,
+			committer_email = EXCLUDED.committer_email,
+			committer_name = EXCLUDED.committer_name,
+			committer_at = EXCLUDED.committer_at,
+			commit_type = EXCLUDED.commit_type,
+			commit_scope = EXCLUDED.commit_scope,
+			signature_type = EXCLUDED.signature_type,
+			signature_status = EXCLUDED.signature_status,
+			signature_key = EXCLUDED.signature_key
 		RETURNING id, created_at
 	`
 
@@ -26,6 +35,16 @@ func (db *DB) UpsertCommit(ctx context.Context, commit *Commit) error {
 		commit.AuthorName,
 		commit.Message,
 		commit.CommittedAt,
+		commit.ParentHashes,
+		commit.IsMerge,
+		commit.CommitterEmail,
+		commit.CommitterName,
+		commit.CommitterAt,
+		commit.Type,
+		commit.Scope,
+		commit.SignatureType,
+		commit.SignatureStatus,
+		commit.SignatureKey,
 	).Scan(&commit.ID, &commit.CreatedAt)
 
 	if err != nil {
@@ -48,18 +67,28 @@ func (db *DB) UpsertCommits(ctx context.Context, commits []*Commit) error {
 	defer tx.Rollback(ctx)
 
 	query := `
-		INSERT INTO commits (repository_id, hash, author_email, author_name, message, committed_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO commits (repository_id, hash, author_email, author_name, message, committed_at, parent_hashes, is_merge, committer_email, committer_name, committer_at, commit_type, commit_scope,
+			signature_type, signature_status, signature_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16)
 		ON CONFLICT (repository_id, hash)
 		DO UPDATE SET
 			author_email = EXCLUDED.author_email,
 			author_name = EXCLUDED.author_name,
-			message = EXCLUDED.message
+			message = EXCLUDED.message,
+			committer_email = EXCLUDED.committer_email,
+			committer_name = EXCLUDED.committer_name,
+			committer_at = EXCLUDED.committer_at,
+			commit_type = EXCLUDED.commit_type,
+			commit_scope = EXCLUDED.commit_scope,
+			signature_type = EXCLUDED.signature_type,
+			signature_status = EXCLUDED.signature_status,
+			signature_key = EXCLUDED.signature_key
 	`
 
 	batch := &pgx.Batch{}
 	for _, c := range commits {
-		batch.Queue(query, c.RepositoryID, c.Hash, c.AuthorEmail, c.AuthorName, c.Message, c.CommittedAt)
+		batch.Queue(query, c.RepositoryID, c.Hash, c.AuthorEmail, c.AuthorName, c.Message, c.CommittedAt, c.ParentHashes, c.IsMerge,
+			c.CommitterEmail, c.CommitterName, c.CommitterAt, c.Type, c.Scope, c.SignatureType, c.SignatureStatus, c.SignatureKey)
 	}
 
 	br := tx.SendBatch(ctx, batch)
@@ -133,3 +162,25 @@ func (db *DB) GetCommitsByRepository(ctx context.Context, repositoryID int64, li
 
 	return commits, nil
 }
+
+// GetCommitHashesByRepository retrieves the hashes of all indexed commits for a repository
+func (db *DB) GetCommitHashesByRepository(ctx context.Context, repositoryID int64) ([]string, error) {
+	query := `SELECT hash FROM commits WHERE repository_id = $1`
+
+	rows, err := db.pool.Query(ctx, query, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit hashes: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan commit hash: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
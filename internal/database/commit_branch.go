@@ -0,0 +1,161 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// commitBranchChunkSize is the number of commit hashes inserted per statement
+const commitBranchChunkSize = 5000
+
+// BranchMembership is the change to the recorded commits of a branch
+type BranchMembership struct {
+	Tip     string   // Commit the branch points at
+	Hashes  []string // Commits reachable from the tip
+	Rebuild bool     // Whether Hashes replace the recorded commits of the branch instead of adding to them
+}
+
+// UpdateCommitBranches records the branch membership of a repository's commits and the tip of every
+// branch. Branches missing from branches are no longer indexed and lose their membership.
+func (db *DB) UpdateCommitBranches(ctx context.Context, repositoryID int64, branches map[string]BranchMembership) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	names := make([]string, 0, len(branches))
+	rebuilt := []string{}
+	for name, m := range branches {
+		names = append(names, name)
+		if m.Rebuild {
+			rebuilt = append(rebuilt, name)
+		}
+	}
+
+	clearQuery := `
+		DELETE FROM commit_branches
+		WHERE repository_id = $1 AND (branch <> ALL($2::text[]) OR branch = ANY($3::text[]))
+	`
+	if _, err := tx.Exec(ctx, clearQuery, repositoryID, names, rebuilt); err != nil {
+		return fmt.Errorf("failed to clear commit branches: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM branch_tips WHERE repository_id = $1`, repositoryID); err != nil {
+		return fmt.Errorf("failed to clear branch tips: %w", err)
+	}
+
+	query := `
+		INSERT INTO commit_branches (repository_id, commit_hash, branch)
+		SELECT $1, hash, $3 FROM unnest($2::text[]) AS hash
+		ON CONFLICT DO NOTHING
+	`
+	tipQuery := `
+		INSERT INTO branch_tips (repository_id, branch, commit_hash)
+		VALUES ($1, $2, $3)
+	`
+
+	batch := &pgx.Batch{}
+	for branch, m := range branches {
+		for i := 0; i < len(m.Hashes); i += commitBranchChunkSize {
+			end := i + commitBranchChunkSize
+			if end > len(m.Hashes) {
+				end = len(m.Hashes)
+			}
+			batch.Queue(query, repositoryID, m.Hashes[i:end], branch)
+		}
+		batch.Queue(tipQuery, repositoryID, branch, m.Tip)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	for i := 0; i < batch.Len(); i++ {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return fmt.Errorf("failed to execute batch: %w", err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetBranchTips returns the commit each indexed branch of a repository pointed at, by branch name
+func (db *DB) GetBranchTips(ctx context.Context, repositoryID int64) (map[string]string, error) {
+	query := `SELECT branch, commit_hash FROM branch_tips WHERE repository_id = $1`
+
+	rows, err := db.pool.Query(ctx, query, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch tips: %w", err)
+	}
+	defer rows.Close()
+
+	tips := make(map[string]string)
+	for rows.Next() {
+		var branch, hash string
+		if err := rows.Scan(&branch, &hash); err != nil {
+			return nil, fmt.Errorf("failed to scan branch tip: %w", err)
+		}
+		tips[branch] = hash
+	}
+
+	return tips, nil
+}
+
+// GetBranchCommitHashes returns the hashes of the commits recorded as reachable from a branch
+func (db *DB) GetBranchCommitHashes(ctx context.Context, repositoryID int64, branch string) ([]string, error) {
+	query := `SELECT commit_hash FROM commit_branches WHERE repository_id = $1 AND branch = $2`
+
+	rows, err := db.pool.Query(ctx, query, repositoryID, branch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branch commits: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := []string{}
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, fmt.Errorf("failed to scan commit hash: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+
+	return hashes, nil
+}
+
+// GetBranchesByRepository returns the names of the indexed branches of a repository
+func (db *DB) GetBranchesByRepository(ctx context.Context, repositoryID int64) ([]string, error) {
+	query := `
+		SELECT DISTINCT branch
+		FROM commit_branches
+		WHERE repository_id = $1
+		ORDER BY branch
+	`
+
+	rows, err := db.pool.Query(ctx, query, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get branches: %w", err)
+	}
+	defer rows.Close()
+
+	branches := []string{}
+	for rows.Next() {
+		var branch string
+		if err := rows.Scan(&branch); err != nil {
+			return nil, fmt.Errorf("failed to scan branch: %w", err)
+		}
+		branches = append(branches, branch)
+	}
+
+	return branches, nil
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertCommitCoAuthors batch inserts or updates the co-authors credited by commit trailers
+func (db *DB) UpsertCommitCoAuthors(ctx context.Context, coAuthors []*CommitCoAuthor) error {
+	if len(coAuthors) == 0 {
+		return nil
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO commit_coauthors (repository_id, commit_hash, email, name, is_bot)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (repository_id, commit_hash, email)
+		DO UPDATE SET
+			name = EXCLUDED.name,
+			is_bot = EXCLUDED.is_bot
+	`
+
+	batch := &pgx.Batch{}
+	for _, ca := range coAuthors {
+		batch.Queue(query, ca.RepositoryID, ca.CommitHash, ca.Email, ca.Name, ca.IsBot)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	for range coAuthors {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return fmt.Errorf("failed to execute batch: %w", err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteCommitCoAuthorsByRepository deletes all co-author records for a repository
+func (db *DB) DeleteCommitCoAuthorsByRepository(ctx context.Context, repositoryID int64) error {
+	query := `DELETE FROM commit_coauthors WHERE repository_id = $1`
+
+	_, err := db.pool.Exec(ctx, query, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete commit co-authors: %w", err)
+	}
+
+	return nil
+}
@@ -20,19 +20,18 @@ func (db *DB) UpsertCommitFiles(ctx context.Context, commitFiles []*CommitFile)
 	defer tx.Rollback(ctx)
 
 	// Since CommitFiles are immutable events, we just INSERT.
-	// If re-indexing, we might want to delete old ones first, but for now we assume clean run or handled by repository deletion.
+	// A full re-index writes into the staging key, so rows are only ever duplicated by a retried batch.
 	// We'll use ON CONFLICT DO NOTHING just in case.
-	// We need a unique constraint on (commit_hash, file_path) or (repository_id, commit_hash, file_path)
 
 	query := `
-		INSERT INTO commit_files (repository_id, commit_hash, file_path, additions, deletions)
-		VALUES ($1, $2, $3, $4, $5)
-		ON CONFLICT (commit_hash, file_path) DO NOTHING
+		INSERT INTO commit_files (repository_id, commit_hash, file_path, old_path, change_type, additions, deletions)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (repository_id, commit_hash, file_path) DO NOTHING
 	`
 
 	batch := &pgx.Batch{}
 	for _, cf := range commitFiles {
-		batch.Queue(query, cf.RepositoryID, cf.CommitHash, cf.FilePath, cf.Additions, cf.Deletions)
+		batch.Queue(query, cf.RepositoryID, cf.CommitHash, cf.FilePath, cf.OldPath, cf.ChangeType, cf.Additions, cf.Deletions)
 	}
 
 	br := tx.SendBatch(ctx, batch)
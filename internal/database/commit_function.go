@@ -0,0 +1,53 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertCommitFunctions batch inserts the Go functions touched by commits. Like commit files they are
+// immutable, so rows written again by a retried batch are left as they are.
+func (db *DB) UpsertCommitFunctions(ctx context.Context, commitFunctions []*CommitFunction) error {
+	if len(commitFunctions) == 0 {
+		return nil
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO commit_functions (repository_id, commit_hash, file_path, function_name, additions, deletions)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (repository_id, commit_hash, file_path, function_name) DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, cf := range commitFunctions {
+		batch.Queue(query, cf.RepositoryID, cf.CommitHash, cf.FilePath, cf.Function, cf.Additions, cf.Deletions)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	for range commitFunctions {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return fmt.Errorf("failed to execute batch: %w", err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -10,13 +10,14 @@ import (
 // UpsertContributor inserts or updates a contributor
 func (db *DB) UpsertContributor(ctx context.Context, contributor *Contributor) error {
 	query := `
-		INSERT INTO contributors (repository_id, email, name, first_commit_at, last_commit_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO contributors (repository_id, email, name, first_commit_at, last_commit_at, is_bot)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		ON CONFLICT (repository_id, email)
 		DO UPDATE SET
 			name = EXCLUDED.name,
 			first_commit_at = LEAST(contributors.first_commit_at, EXCLUDED.first_commit_at),
-			last_commit_at = GREATEST(contributors.last_commit_at, EXCLUDED.last_commit_at)
+			last_commit_at = GREATEST(contributors.last_commit_at, EXCLUDED.last_commit_at),
+			is_bot = EXCLUDED.is_bot
 		RETURNING id, created_at, updated_at
 	`
 
@@ -26,6 +27,7 @@ func (db *DB) UpsertContributor(ctx context.Context, contributor *Contributor) e
 		contributor.Name,
 		contributor.FirstCommitAt,
 		contributor.LastCommitAt,
+		contributor.IsBot,
 	).Scan(&contributor.ID, &contributor.CreatedAt, &contributor.UpdatedAt)
 
 	if err != nil {
@@ -49,18 +51,19 @@ func (db *DB) UpsertContributors(ctx context.Context, contributors []*Contributo
 	defer tx.Rollback(ctx)
 
 	query := `
-		INSERT INTO contributors (repository_id, email, name, first_commit_at, last_commit_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO contributors (repository_id, email, name, first_commit_at, last_commit_at, is_bot)
+		VALUES ($1, $2, $3, $4, $5, $6)
 		ON CONFLICT (repository_id, email)
 		DO UPDATE SET
 			name = EXCLUDED.name,
 			first_commit_at = LEAST(contributors.first_commit_at, EXCLUDED.first_commit_at),
-			last_commit_at = GREATEST(contributors.last_commit_at, EXCLUDED.last_commit_at)
+			last_commit_at = GREATEST(contributors.last_commit_at, EXCLUDED.last_commit_at),
+			is_bot = EXCLUDED.is_bot
 	`
 
 	batch := &pgx.Batch{}
 	for _, c := range contributors {
-		batch.Queue(query, c.RepositoryID, c.Email, c.Name, c.FirstCommitAt, c.LastCommitAt)
+		batch.Queue(query, c.RepositoryID, c.Email, c.Name, c.FirstCommitAt, c.LastCommitAt, c.IsBot)
 	}
 
 	br := tx.SendBatch(ctx, batch)
@@ -85,16 +88,25 @@ func (db *DB) UpsertContributors(ctx context.Context, contributors []*Contributo
 	return nil
 }
 
-// GetContributorsByRepository retrieves all contributors for a repository
-func (db *DB) GetContributorsByRepository(ctx context.Context, repositoryID int64, limit, offset int) ([]*Contributor, error) {
-	query := `
-		SELECT id, repository_id, email, name, 
-		       first_commit_at, last_commit_at, created_at, updated_at
-		FROM contributors
-		WHERE repository_id = $1
+// GetContributorsByRepository retrieves all contributors for a repository. Identities aliased to the
+// same canonical one are merged; the merged contributor keeps the ID of the canonical identity's record
+// and is a bot when any of its identities is.
+func (db *DB) GetContributorsByRepository(ctx context.Context, repositoryID int64, excludeBots bool, limit, offset int) ([]*Contributor, error) {
+	var botFilter string
+	if excludeBots {
+		botFilter = " AND NOT ct.is_bot"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT (ARRAY_AGG(ct.id ORDER BY ct.email = ident.email DESC, ct.id))[1], $1::BIGINT, ident.email,
+		       (ARRAY_AGG(ident.name ORDER BY ct.email = ident.email DESC, ct.last_commit_at DESC NULLS LAST))[1] as name,
+		       MIN(ct.first_commit_at), MAX(ct.last_commit_at), BOOL_OR(ct.is_bot), MIN(ct.created_at), MAX(ct.updated_at)
+		FROM contributors ct%s
+		WHERE ct.repository_id = $1%s
+		GROUP BY ident.email
 		ORDER BY name ASC
 		LIMIT $2 OFFSET $3
-	`
+	`, IdentityJoin("ident", "ct.email", "ct.name"), botFilter)
 
 	rows, err := db.pool.Query(ctx, query, repositoryID, limit, offset)
 	if err != nil {
@@ -112,6 +124,7 @@ func (db *DB) GetContributorsByRepository(ctx context.Context, repositoryID int6
 			&c.Name,
 			&c.FirstCommitAt,
 			&c.LastCommitAt,
+			&c.IsBot,
 			&c.CreatedAt,
 			&c.UpdatedAt,
 		)
@@ -124,6 +137,171 @@ func (db *DB) GetContributorsByRepository(ctx context.Context, repositoryID int6
 	return contributors, nil
 }
 
+// ContributorFilter narrows the commits contributors are derived from
+type ContributorFilter struct {
+	Branch           string // Only count commits reachable from this branch (empty = all indexed branches)
+	IncludeCoAuthors bool   // Also credit people named in Co-authored-by trailers
+	ExcludeBots      bool   // Leave out commits and co-authors flagged as bots
+}
+
+// GetContributorsFiltered retrieves the contributors of the commits matching the filter, by canonical identity.
+// First and last commit dates only consider those commits. Co-authors without authored
+// commits have no contributor record and are returned with ID 0.
+func (db *DB) GetContributorsFiltered(ctx context.Context, repositoryID int64, filter ContributorFilter, limit, offset int) ([]*Contributor, error) {
+	args := []interface{}{repositoryID}
+	var branchFilter string
+	if filter.Branch != "" {
+		args = append(args, filter.Branch)
+		branchFilter = fmt.Sprintf(`
+				AND EXISTS (
+					SELECT 1 FROM commit_branches cb
+					WHERE cb.repository_id = c.repository_id AND cb.commit_hash = c.hash AND cb.branch = $%d
+				)`, len(args))
+	}
+
+	authorFilter, coAuthorFilter := branchFilter, branchFilter
+	if filter.ExcludeBots {
+		authorFilter += NotBotCondition("c.author_email")
+		coAuthorFilter += NotBotCondition("ca.email") + " AND NOT ca.is_bot"
+	}
+
+	credits := fmt.Sprintf(`
+			SELECT ident.email, ident.name, c.committed_at, false as is_bot
+			FROM commits c%s
+			WHERE c.repository_id = $1%s`, IdentityJoin("ident", "c.author_email", "c.author_name"), authorFilter)
+	if filter.IncludeCoAuthors {
+		credits += fmt.Sprintf(`
+			UNION ALL
+			SELECT ident.email, ident.name, c.committed_at, ca.is_bot
+			FROM commit_coauthors ca
+			JOIN commits c ON c.repository_id = ca.repository_id AND c.hash = ca.commit_hash%s
+			WHERE ca.repository_id = $1%s`, IdentityJoin("ident", "ca.email", "ca.name"), coAuthorFilter)
+	}
+
+	args = append(args, limit, offset)
+	query := fmt.Sprintf(`
+		SELECT COALESCE(ct.id, 0), $1::BIGINT, b.email, COALESCE(cti.name, b.name) as name,
+		       b.first_commit_at, b.last_commit_at, COALESCE(ct.is_bot, false) OR b.is_bot,
+		       COALESCE(ct.created_at, NOW()), COALESCE(ct.updated_at, NOW())
+		FROM (
+			SELECT email, MAX(name) as name, MIN(committed_at) as first_commit_at, MAX(committed_at) as last_commit_at,
+			       BOOL_OR(is_bot) as is_bot
+			FROM (%s
+			) credits
+			GROUP BY email
+		) b
+		LEFT JOIN contributors ct ON ct.repository_id = $1 AND ct.email = b.email%s
+		ORDER BY name ASC
+		LIMIT $%d OFFSET $%d
+	`, credits, IdentityJoin("cti", "ct.email", "ct.name"), len(args)-1, len(args))
+
+	rows, err := db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contributors: %w", err)
+	}
+	defer rows.Close()
+
+	contributors := []*Contributor{}
+	for rows.Next() {
+		c := &Contributor{}
+		err := rows.Scan(
+			&c.ID,
+			&c.RepositoryID,
+			&c.Email,
+			&c.Name,
+			&c.FirstCommitAt,
+			&c.LastCommitAt,
+			&c.IsBot,
+			&c.CreatedAt,
+			&c.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan contributor: %w", err)
+		}
+		contributors = append(contributors, c)
+	}
+
+	return contributors, nil
+}
+
+// NotBotCondition leaves out rows whose email belongs to a contributor flagged as a bot.
+// Like IdentityJoin it expects the repository ID as $1.
+func NotBotCondition(emailExpr string) string {
+	return fmt.Sprintf(`
+		AND NOT EXISTS (
+			SELECT 1 FROM contributors bot
+			WHERE bot.repository_id = $1 AND bot.email = %s AND bot.is_bot
+		)`, emailExpr)
+}
+
+// FlagBotContributors re-evaluates the bot flag of every contributor and co-author of a repository,
+// e.g. after its bot patterns changed. It returns the number of contributors flagged.
+func (db *DB) FlagBotContributors(ctx context.Context, repositoryID int64, isBot func(name, email string) bool) (int, error) {
+	bots, err := db.botEmails(ctx, `SELECT email, name FROM contributors WHERE repository_id = $1`, repositoryID, isBot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get contributors: %w", err)
+	}
+	coAuthorBots, err := db.botEmails(ctx, `SELECT DISTINCT email, name FROM commit_coauthors WHERE repository_id = $1`, repositoryID, isBot)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get co-authors: %w", err)
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE contributors
+		SET is_bot = (email = ANY($2))
+		WHERE repository_id = $1 AND is_bot <> (email = ANY($2))
+	`
+	if _, err := tx.Exec(ctx, query, repositoryID, bots); err != nil {
+		return 0, fmt.Errorf("failed to flag bot contributors: %w", err)
+	}
+
+	coAuthorQuery := `
+		UPDATE commit_coauthors
+		SET is_bot = (email = ANY($2))
+		WHERE repository_id = $1 AND is_bot <> (email = ANY($2))
+	`
+	if _, err := tx.Exec(ctx, coAuthorQuery, repositoryID, coAuthorBots); err != nil {
+		return 0, fmt.Errorf("failed to flag bot co-authors: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return len(bots), nil
+}
+
+// botEmails returns the emails of the (email, name) rows of query that isBot flags
+func (db *DB) botEmails(ctx context.Context, query string, repositoryID int64, isBot func(name, email string) bool) ([]string, error) {
+	rows, err := db.pool.Query(ctx, query, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bots := []string{}
+	for rows.Next() {
+		var email, name string
+		if err := rows.Scan(&email, &name); err != nil {
+			return nil, err
+		}
+		if isBot(name, email) {
+			bots = append(bots, email)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return bots, nil
+}
+
 // DeleteContributorsByRepository deletes all contributors for a repository
 func (db *DB) DeleteContributorsByRepository(ctx context.Context, repositoryID int64) error {
 	query := `DELETE FROM contributors WHERE repository_id = $1`
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestFlagBotContributors(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	db := NewTestDB(mock)
+	ctx := context.Background()
+
+	repoID := int64(3)
+	mock.ExpectQuery("SELECT email, name FROM contributors").
+		WithArgs(repoID).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "name"}).
+			AddRow("alice@example.com", "Alice").
+			AddRow("ci@example.com", "CI"))
+	mock.ExpectQuery("SELECT DISTINCT email, name FROM commit_coauthors").
+		WithArgs(repoID).
+		WillReturnRows(pgxmock.NewRows([]string{"email", "name"}).
+			AddRow("bob@example.com", "Bob").
+			AddRow("helper@example.com", "helper[bot]"))
+	mock.ExpectBegin()
+	mock.ExpectExec("UPDATE contributors").
+		WithArgs(repoID, []string{"ci@example.com"}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectExec("UPDATE commit_coauthors").
+		WithArgs(repoID, []string{"helper@example.com"}).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 2))
+	mock.ExpectCommit()
+
+	isBot := func(name, email string) bool {
+		return strings.HasPrefix(email, "ci@") || strings.HasSuffix(name, "[bot]")
+	}
+	flagged, err := db.FlagBotContributors(ctx, repoID, isBot)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flagged != 1 {
+		t.Errorf("expected 1 contributor flagged, got %d", flagged)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
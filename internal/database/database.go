@@ -74,3 +74,35 @@ func (db *DB) Pool() PgxIface {
 func NewTestDB(pool PgxIface) *DB {
 	return &DB{pool: pool}
 }
+
+// txPool runs the queries of a DB in a transaction; transactions begun on it are savepoints
+type txPool struct {
+	pgx.Tx
+}
+
+func (p txPool) Ping(ctx context.Context) error {
+	return p.Conn().Ping(ctx)
+}
+
+// Close leaves the transaction to InTx
+func (p txPool) Close() {}
+
+// InTx calls fn with a DB whose writes are committed together once fn succeeds, and rolled back
+// otherwise. The DB must not be used after fn returns, nor by several goroutines at once.
+func (db *DB) InTx(ctx context.Context, fn func(tx *DB) error) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(&DB{pool: txPool{tx}}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestInTx(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	db := NewTestDB(mock)
+	ctx := context.Background()
+
+	// Writes of a successful fn are committed together, nested transactions included
+	mock.ExpectBegin()
+	mock.ExpectBegin()
+	for _, table := range indexTables {
+		mock.ExpectExec("DELETE FROM " + table + " WHERE repository_id").
+			WithArgs(int64(-2)).
+			WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	}
+	mock.ExpectExec("DELETE FROM index_checkpoints").
+		WithArgs(int64(2)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 1))
+	mock.ExpectCommit()
+	mock.ExpectCommit()
+
+	err = db.InTx(ctx, func(tx *DB) error {
+		return tx.ClearStaging(ctx, 2)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A failing fn rolls everything back
+	failed := errors.New("failed")
+	mock.ExpectBegin()
+	mock.ExpectExec("DELETE FROM commit_files").
+		WithArgs(int64(2)).
+		WillReturnResult(pgxmock.NewResult("DELETE", 3))
+	mock.ExpectRollback()
+
+	err = db.InTx(ctx, func(tx *DB) error {
+		if err := tx.DeleteCommitFilesByRepository(ctx, 2); err != nil {
+			return err
+		}
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("expected fn error, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertDeployKey stores or replaces the deploy key of a repository
+func (db *DB) UpsertDeployKey(ctx context.Context, key *DeployKey) error {
+	query := `
+		INSERT INTO repository_deploy_keys (repository_id, private_key, passphrase)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (repository_id)
+		DO UPDATE SET
+			private_key = EXCLUDED.private_key,
+			passphrase = EXCLUDED.passphrase
+		RETURNING created_at, updated_at
+	`
+
+	err := db.pool.QueryRow(ctx, query, key.RepositoryID, key.PrivateKey, key.Passphrase).
+		Scan(&key.CreatedAt, &key.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert deploy key: %w", err)
+	}
+
+	return nil
+}
+
+// GetDeployKey retrieves the deploy key of a repository
+func (db *DB) GetDeployKey(ctx context.Context, repositoryID int64) (*DeployKey, error) {
+	query := `
+		SELECT repository_id, private_key, passphrase, created_at, updated_at
+		FROM repository_deploy_keys
+		WHERE repository_id = $1
+	`
+
+	key := &DeployKey{}
+	err := db.pool.QueryRow(ctx, query, repositoryID).Scan(
+		&key.RepositoryID, &key.PrivateKey, &key.Passphrase, &key.CreatedAt, &key.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get deploy key: %w", err)
+	}
+
+	return key, nil
+}
+
+// DeleteDeployKey removes the deploy key of a repository
+func (db *DB) DeleteDeployKey(ctx context.Context, repositoryID int64) error {
+	query := `DELETE FROM repository_deploy_keys WHERE repository_id = $1`
+
+	result, err := db.pool.Exec(ctx, query, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete deploy key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -21,17 +21,29 @@ func (db *DB) UpsertFiles(ctx context.Context, files []*File) error {
 
 	// We use Path + RepositoryID as unique constraint
 	query := `
-		INSERT INTO files (repository_id, path, language, lines, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		INSERT INTO files (repository_id, path, language, lines, code_lines, comment_lines, blank_lines,
+			is_binary, size_bytes, blob_hash, is_vendored, is_generated, is_documentation, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, NOW(), NOW())
 		ON CONFLICT (repository_id, path)
 		DO UPDATE SET
+			language = EXCLUDED.language,
 			lines = EXCLUDED.lines,
+			code_lines = EXCLUDED.code_lines,
+			comment_lines = EXCLUDED.comment_lines,
+			blank_lines = EXCLUDED.blank_lines,
+			is_binary = EXCLUDED.is_binary,
+			size_bytes = EXCLUDED.size_bytes,
+			blob_hash = EXCLUDED.blob_hash,
+			is_vendored = EXCLUDED.is_vendored,
+			is_generated = EXCLUDED.is_generated,
+			is_documentation = EXCLUDED.is_documentation,
 			updated_at = NOW()
 	`
 
 	batch := &pgx.Batch{}
 	for _, f := range files {
-		batch.Queue(query, f.RepositoryID, f.Path, f.Language, f.Lines)
+		batch.Queue(query, f.RepositoryID, f.Path, f.Language, f.Lines, f.CodeLines, f.CommentLines, f.BlankLines,
+			f.IsBinary, f.SizeBytes, f.BlobHash, f.IsVendored, f.IsGenerated, f.IsDocumentation)
 	}
 
 	br := tx.SendBatch(ctx, batch)
@@ -70,7 +82,9 @@ func (db *DB) DeleteFilesByRepository(ctx context.Context, repositoryID int64) e
 // GetFilesByRepository retrieves files with pagination
 func (db *DB) GetFilesByRepository(ctx context.Context, repositoryID int64, limit, offset int) ([]*File, error) {
 	query := `
-		SELECT id, repository_id, path, language, lines, created_at, updated_at
+		SELECT id, repository_id, path, language, lines, code_lines, comment_lines, blank_lines,
+		       is_binary, size_bytes, blob_hash,
+		       is_vendored, is_generated, is_documentation, created_at, updated_at
 		FROM files
 		WHERE repository_id = $1
 		ORDER BY lines DESC
@@ -92,6 +106,15 @@ func (db *DB) GetFilesByRepository(ctx context.Context, repositoryID int64, limi
 			&f.Path,
 			&f.Language,
 			&f.Lines,
+			&f.CodeLines,
+			&f.CommentLines,
+			&f.BlankLines,
+			&f.IsBinary,
+			&f.SizeBytes,
+			&f.BlobHash,
+			&f.IsVendored,
+			&f.IsGenerated,
+			&f.IsDocumentation,
 			&f.CreatedAt,
 			&f.UpdatedAt,
 		)
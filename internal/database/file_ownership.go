@@ -0,0 +1,67 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertFileOwnership batch inserts or updates blame line counts per file and author
+func (db *DB) UpsertFileOwnership(ctx context.Context, ownership []*FileOwnership) error {
+	if len(ownership) == 0 {
+		return nil
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO file_ownership (repository_id, file_path, author_email, author_name, lines)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (repository_id, file_path, author_email)
+		DO UPDATE SET
+			author_name = EXCLUDED.author_name,
+			lines = EXCLUDED.lines
+	`
+
+	batch := &pgx.Batch{}
+	for _, o := range ownership {
+		batch.Queue(query, o.RepositoryID, o.FilePath, o.AuthorEmail, o.AuthorName, o.Lines)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	for range ownership {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return fmt.Errorf("failed to execute batch: %w", err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteFileOwnershipByRepository deletes all blame ownership records for a repository
+func (db *DB) DeleteFileOwnershipByRepository(ctx context.Context, repositoryID int64) error {
+	query := `DELETE FROM file_ownership WHERE repository_id = $1`
+
+	_, err := db.pool.Exec(ctx, query, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete file ownership: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// IdentityJoin returns a CROSS JOIN LATERAL resolving the author identity in emailExpr and nameExpr
+// to its canonical identity, exposed as <alias>.email and <alias>.name. Manual aliases win over
+// .mailmap entries, and entries for a specific commit name over those for any name. Identities
+// without an alias are kept as they are. The repository ID must be bound to $1.
+func IdentityJoin(alias, emailExpr, nameExpr string) string {
+	return fmt.Sprintf(`
+		CROSS JOIN LATERAL (
+			SELECT COALESCE(MAX(m.canonical_email), %[2]s) as email, COALESCE(MAX(m.canonical_name), %[3]s) as name
+			FROM (
+				SELECT ia.canonical_email, ia.canonical_name
+				FROM identity_aliases ia
+				WHERE ia.repository_id = $1 AND ia.email = LOWER(%[2]s) AND ia.commit_name IN ('', LOWER(%[3]s))
+				ORDER BY ia.source <> '%[4]s', ia.commit_name = ''
+				LIMIT 1
+			) m
+		) %[1]s`, alias, emailExpr, nameExpr, AliasSourceManual)
+}
+
+// CanonicalEmailOf returns a scalar subquery resolving the email in emailExpr, typically a bound
+// parameter, to its canonical email. Like IdentityJoin it expects the repository ID in $1.
+func CanonicalEmailOf(emailExpr string) string {
+	return fmt.Sprintf(`(SELECT ci.email FROM (SELECT %s::TEXT) p(email)%s)`, emailExpr, IdentityJoin("ci", "p.email", "''"))
+}
+
+// ListIdentityAliases retrieves the aliases of a repository, manual ones first
+func (db *DB) ListIdentityAliases(ctx context.Context, repositoryID int64) ([]*IdentityAlias, error) {
+	query := `
+		SELECT id, repository_id, source, email, commit_name, canonical_email, canonical_name, created_at
+		FROM identity_aliases
+		WHERE repository_id = $1
+		ORDER BY source <> $2, email, commit_name
+	`
+
+	rows, err := db.pool.Query(ctx, query, repositoryID, AliasSourceManual)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list identity aliases: %w", err)
+	}
+	defer rows.Close()
+
+	aliases := []*IdentityAlias{}
+	for rows.Next() {
+		a := &IdentityAlias{}
+		if err := rows.Scan(&a.ID, &a.RepositoryID, &a.Source, &a.Email, &a.CommitName, &a.CanonicalEmail, &a.CanonicalName, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan identity alias: %w", err)
+		}
+		aliases = append(aliases, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return aliases, nil
+}
+
+// GetIdentityAlias retrieves an alias of a repository by ID
+func (db *DB) GetIdentityAlias(ctx context.Context, repositoryID, id int64) (*IdentityAlias, error) {
+	query := `
+		SELECT id, repository_id, source, email, commit_name, canonical_email, canonical_name, created_at
+		FROM identity_aliases
+		WHERE repository_id = $1 AND id = $2
+	`
+
+	a := &IdentityAlias{}
+	err := db.pool.QueryRow(ctx, query, repositoryID, id).Scan(
+		&a.ID, &a.RepositoryID, &a.Source, &a.Email, &a.CommitName, &a.CanonicalEmail, &a.CanonicalName, &a.CreatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get identity alias: %w", err)
+	}
+
+	return a, nil
+}
+
+// UpsertManualAliases stores aliases merged through the API, replacing the previous target of an email.
+// Manual aliases that targeted a merged email are moved along, so identities never resolve in more than one hop.
+func (db *DB) UpsertManualAliases(ctx context.Context, aliases []*IdentityAlias) error {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO identity_aliases (repository_id, source, email, commit_name, canonical_email, canonical_name)
+		VALUES ($1, $2, $3, '', $4, $5)
+		ON CONFLICT (repository_id, source, email, commit_name)
+		DO UPDATE SET
+			canonical_email = EXCLUDED.canonical_email,
+			canonical_name = EXCLUDED.canonical_name
+		RETURNING id, created_at
+	`
+
+	for _, a := range aliases {
+		a.Source = AliasSourceManual
+		err := tx.QueryRow(ctx, query, a.RepositoryID, a.Source, a.Email, a.CanonicalEmail, a.CanonicalName).Scan(&a.ID, &a.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to upsert identity alias: %w", err)
+		}
+	}
+
+	repointQuery := `
+		UPDATE identity_aliases
+		SET canonical_email = $3, canonical_name = $4
+		WHERE repository_id = $1 AND source = $5 AND LOWER(canonical_email) = LOWER($2)
+	`
+	for _, a := range aliases {
+		if a.CanonicalEmail == nil || strings.EqualFold(a.Email, *a.CanonicalEmail) {
+			continue
+		}
+		if _, err := tx.Exec(ctx, repointQuery, a.RepositoryID, a.Email, a.CanonicalEmail, a.CanonicalName, AliasSourceManual); err != nil {
+			return fmt.Errorf("failed to move aliases of %s: %w", a.Email, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// ReplaceMailmapAliases replaces the aliases read from the repository's .mailmap
+func (db *DB) ReplaceMailmapAliases(ctx context.Context, repositoryID int64, aliases []*IdentityAlias) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM identity_aliases WHERE repository_id = $1 AND source = $2`, repositoryID, AliasSourceMailmap); err != nil {
+		return fmt.Errorf("failed to clear mailmap aliases: %w", err)
+	}
+
+	query := `
+		INSERT INTO identity_aliases (repository_id, source, email, commit_name, canonical_email, canonical_name)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (repository_id, source, email, commit_name)
+		DO UPDATE SET
+			canonical_email = EXCLUDED.canonical_email,
+			canonical_name = EXCLUDED.canonical_name
+	`
+
+	batch := &pgx.Batch{}
+	for _, a := range aliases {
+		batch.Queue(query, repositoryID, AliasSourceMailmap, a.Email, a.CommitName, a.CanonicalEmail, a.CanonicalName)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	for range aliases {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return fmt.Errorf("failed to execute batch: %w", err)
+		}
+	}
+
+	// Must close batch reader before committing transaction
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteIdentityAlias removes an alias of a repository
+func (db *DB) DeleteIdentityAlias(ctx context.Context, repositoryID, id int64) error {
+	result, err := db.pool.Exec(ctx, `DELETE FROM identity_aliases WHERE repository_id = $1 AND id = $2`, repositoryID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete identity alias: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestUpsertManualAliasesMovesChains(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	db := NewTestDB(mock)
+	ctx := context.Background()
+
+	// x@ was merged into a@ before; merging a@ into c@ moves x@ along
+	repoID := int64(5)
+	canonical := "c@example.com"
+	name := "Carol"
+	aliases := []*IdentityAlias{
+		{RepositoryID: repoID, Email: "c@example.com", CanonicalEmail: &canonical, CanonicalName: &name},
+		{RepositoryID: repoID, Email: "a@example.com", CanonicalEmail: &canonical, CanonicalName: &name},
+	}
+
+	mock.ExpectBegin()
+	for i, a := range aliases {
+		mock.ExpectQuery("INSERT INTO identity_aliases").
+			WithArgs(repoID, AliasSourceManual, a.Email, &canonical, &name).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "created_at"}).AddRow(int64(i+1), time.Now()))
+	}
+	mock.ExpectExec("UPDATE identity_aliases").
+		WithArgs(repoID, "a@example.com", &canonical, &name, AliasSourceManual).
+		WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+	mock.ExpectCommit()
+
+	if err := db.UpsertManualAliases(ctx, aliases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
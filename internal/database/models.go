@@ -13,22 +13,90 @@ const (
 	StatusFailed     RepositoryStatus = "failed"
 )
 
+// BranchMode selects which branches of a repository are indexed
+type BranchMode string
+
+const (
+	BranchModeDefault BranchMode = "default" // Only the default branch
+	BranchModeList    BranchMode = "list"    // The branches listed in Repository.Branches
+	BranchModeAll     BranchMode = "all"     // Every branch
+)
+
+// ChangeType describes how a file was changed in a commit
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeModified ChangeType = "modified"
+	ChangeDeleted  ChangeType = "deleted"
+	ChangeRenamed  ChangeType = "renamed"
+	ChangeCopied   ChangeType = "copied"
+)
+
+// CommitType is the intent of a commit
+type CommitType string
+
+const (
+	CommitTypeFeature  CommitType = "feature"
+	CommitTypeFix      CommitType = "fix"
+	CommitTypeRefactor CommitType = "refactor"
+	CommitTypeDocs     CommitType = "docs"
+	CommitTypeTest     CommitType = "test"
+	CommitTypeChore    CommitType = "chore"
+	CommitTypeRevert   CommitType = "revert"
+	CommitTypeMerge    CommitType = "merge" // Merges another branch; its changes are classified on the merged commits
+	CommitTypeOther    CommitType = "other" // Not classified
+)
+
+// CommitTypes lists the commit types in display order
+var CommitTypes = []CommitType{
+	CommitTypeFeature, CommitTypeFix, CommitTypeRefactor, CommitTypeDocs,
+	CommitTypeTest, CommitTypeChore, CommitTypeRevert, CommitTypeMerge, CommitTypeOther,
+}
+
+// SignatureType is the kind of signature on a commit
+type SignatureType string
+
+const (
+	SignatureTypePGP   SignatureType = "pgp"
+	SignatureTypeSSH   SignatureType = "ssh"
+	SignatureTypeOther SignatureType = "other" // e.g. X.509 (gpgsm), which is never verified
+)
+
+// SignatureStatus is the outcome of checking a commit's signature against the repository's signing keys
+type SignatureStatus string
+
+const (
+	SignatureVerified   SignatureStatus = "verified"   // Valid signature by a trusted key
+	SignatureUnverified SignatureStatus = "unverified" // Signed, but not validly by a trusted key
+	SignatureUnsigned   SignatureStatus = "unsigned"
+)
+
 // Repository represents a git repository being tracked
 type Repository struct {
-	ID            int64            `json:"id"`
-	UserID        *int64           `json:"user_id,omitempty"` // Owner of the repository
-	Name          *string          `json:"name"`              // Human-readable name
-	Description   *string          `json:"description"`
-	URL           string           `json:"url"`
-	IsPrivate     bool             `json:"is_private"`
-	Provider      *string          `json:"provider,omitempty"` // 'github', 'google', etc.
-	LocalPath     *string          `json:"local_path,omitempty"`
-	DefaultBranch string           `json:"default_branch"`
-	Status        RepositoryStatus `json:"status"`
-	LastPushedAt  *time.Time       `json:"last_pushed_at,omitempty"`
-	LastIndexedAt *time.Time       `json:"last_indexed_at,omitempty"`
-	CreatedAt     time.Time        `json:"created_at"`
-	UpdatedAt     time.Time        `json:"updated_at"`
+	ID                int64            `json:"id"`
+	UserID            *int64           `json:"user_id,omitempty"` // Owner of the repository
+	Name              *string          `json:"name"`              // Human-readable name
+	Description       *string          `json:"description"`
+	URL               string           `json:"url"`
+	IsPrivate         bool             `json:"is_private"`
+	Provider          *string          `json:"provider,omitempty"` // 'github', 'google', etc.
+	LocalPath         *string          `json:"local_path,omitempty"`
+	DefaultBranch     string           `json:"default_branch"`
+	BranchMode        BranchMode       `json:"branch_mode"`
+	Branches          []string         `json:"branches"`              // Indexed branches besides the default one (BranchModeList)
+	FirstParent       bool             `json:"first_parent"`          // Only analyse the first-parent (mainline) history
+	CloneDepth        int              `json:"clone_depth"`           // Commits fetched from each branch tip (0 = full history)
+	CloneSince        *time.Time       `json:"clone_since,omitempty"` // Commits older than this are not indexed
+	SingleBranch      bool             `json:"single_branch"`         // Only clone the default branch
+	HistoryTruncated  bool             `json:"history_truncated"`     // The indexed history stops before the first commit
+	BotPatterns       []string         `json:"bot_patterns"`          // Email or name globs of bot accounts, on top of the built-in heuristics
+	Status            RepositoryStatus `json:"status"`
+	LastPushedAt      *time.Time       `json:"last_pushed_at,omitempty"`
+	LastIndexedAt     *time.Time       `json:"last_indexed_at,omitempty"`
+	LastIndexedCommit *string          `json:"last_indexed_commit,omitempty"` // HEAD of the last completed index
+	CreatedAt         time.Time        `json:"created_at"`
+	UpdatedAt         time.Time        `json:"updated_at"`
 }
 
 // User represents a registered system user
@@ -54,6 +122,26 @@ type UserIdentity struct {
 	CreatedAt        time.Time  `json:"created_at"`
 }
 
+// DeployKey is an SSH private key used to clone a single private repository
+type DeployKey struct {
+	RepositoryID int64     `json:"repository_id"`
+	PrivateKey   string    `json:"-"`
+	Passphrase   *string   `json:"-"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// SigningKey is a public key trusted to sign the commits of a repository
+type SigningKey struct {
+	ID           int64         `json:"id"`
+	RepositoryID int64         `json:"repository_id"`
+	Type         SignatureType `json:"type"`       // SignatureTypePGP or SignatureTypeSSH
+	PublicKey    string        `json:"public_key"` // ASCII-armored PGP public key or SSH key in authorized_keys format
+	Fingerprint  string        `json:"fingerprint"`
+	Name         string        `json:"name"` // User ID of a PGP key, comment of an SSH key
+	CreatedAt    time.Time     `json:"created_at"`
+}
+
 // Contributor represents a developer identity found in the git log
 type Contributor struct {
 	ID            int64      `json:"id"`
@@ -62,6 +150,7 @@ type Contributor struct {
 	Name          string     `json:"name"`
 	FirstCommitAt *time.Time `json:"first_commit_at,omitempty"`
 	LastCommitAt  *time.Time `json:"last_commit_at,omitempty"`
+	IsBot         bool       `json:"is_bot"` // Automated account, left out of stats with exclude_bots
 	CreatedAt     time.Time  `json:"created_at"`
 	UpdatedAt     time.Time  `json:"updated_at"`
 	// Note: Aggregate stats (CommitCount, LinesAdded) are removed to force
@@ -71,34 +160,171 @@ type Contributor struct {
 // File represents a file as it exists in the HEAD of the repository (Index)
 // This is used for "State" insights: "How big is this system?" "What languages?"
 type File struct {
+	ID              int64     `json:"id"`
+	RepositoryID    int64     `json:"repository_id"`
+	Path            string    `json:"path"`
+	Language        string    `json:"language"` // e.g. "Go", "TypeScript" - from file name, extension or shebang
+	Lines           int       `json:"lines"`    // Lines at HEAD (0 for binaries)
+	CodeLines       int       `json:"code_lines"`
+	CommentLines    int       `json:"comment_lines"`
+	BlankLines      int       `json:"blank_lines"`
+	IsBinary        bool      `json:"is_binary"`
+	SizeBytes       int64     `json:"size_bytes"`
+	BlobHash        string    `json:"blob_hash"`
+	IsVendored      bool      `json:"is_vendored"`      // Third-party code
+	IsGenerated     bool      `json:"is_generated"`     // Tool output such as lock files and protobuf stubs
+	IsDocumentation bool      `json:"is_documentation"` // Prose rather than code
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Tag is a git tag resolved to the commit it points at
+type Tag struct {
+	ID           int64     `json:"id"`
+	RepositoryID int64     `json:"repository_id"`
+	Name         string    `json:"name"`
+	CommitHash   string    `json:"commit_hash"`
+	IsAnnotated  bool      `json:"is_annotated"`
+	Message      *string   `json:"message,omitempty"` // Annotated tags only
+	TaggerName   *string   `json:"tagger_name,omitempty"`
+	TaggerEmail  *string   `json:"tagger_email,omitempty"`
+	TaggedAt     time.Time `json:"tagged_at"` // Tagger date, or the commit date of lightweight tags
+}
+
+// Snapshot is the state of one language in a sampled historical commit of the default branch
+type Snapshot struct {
 	ID           int64     `json:"id"`
+	RepositoryID int64     `json:"repository_id"`
+	PeriodStart  time.Time `json:"period_start"` // Start of the week or month the commit was sampled for
+	CommitHash   string    `json:"commit_hash"`
+	CommittedAt  time.Time `json:"committed_at"`
+	Language     string    `json:"language"`
+	Files        int       `json:"files"`
+	Lines        int       `json:"lines"`
+	CodeLines    int       `json:"code_lines"`
+}
+
+// IndexingProgress is the position of the running (or last) index of a repository
+type IndexingProgress struct {
+	RepositoryID     int64     `json:"repository_id"`
+	Phase            string    `json:"phase"`   // cloning, snapshot, history, branches, growth, tags or done
+	Percent          float64   `json:"percent"` // Of the current phase
+	CommitsProcessed int       `json:"commits_processed"`
+	CommitsTotal     int       `json:"commits_total"`
+	ETASeconds       *int      `json:"eta_seconds"` // Remaining time of the history phase
+	StartedAt        time.Time `json:"started_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// IndexCheckpoint is the position of an unfinished full index in its history phase.
+// The staged data up to LastCommit is kept, so a retried index continues after it.
+type IndexCheckpoint struct {
+	RepositoryID     int64     `json:"repository_id"`
+	Fingerprint      string    `json:"fingerprint"` // Branch tips and history bounds the walk started from
+	LastCommit       string    `json:"last_commit"` // Last commit of the last flushed batch
+	Batches          int       `json:"batches"`
+	CommitsProcessed int       `json:"commits_processed"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// CloneStorage is the disk usage of a repository's clone under the worker storage path
+type CloneStorage struct {
 	RepositoryID int64     `json:"repository_id"`
 	Path         string    `json:"path"`
-	Language     string    `json:"language"` // e.g. "Go", "TypeScript" - inferred from extension
-	Lines        int       `json:"lines"`    // Lines of Code at HEAD
-	CreatedAt    time.Time `json:"created_at"`
+	SizeBytes    int64     `json:"size_bytes"`
+	LastUsedAt   time.Time `json:"last_used_at"` // Last index or update that used the clone
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
+// CloneStorageUsage is a clone's disk usage with the repository it belongs to
+type CloneStorageUsage struct {
+	CloneStorage
+	URL    string           `json:"url"`
+	Name   *string          `json:"name,omitempty"`
+	Status RepositoryStatus `json:"status"`
+}
+
+// Sources of identity aliases
+const (
+	AliasSourceMailmap = "mailmap" // Read from the repository's .mailmap at every index
+	AliasSourceManual  = "manual"  // Merged through the API; takes precedence over .mailmap
+)
+
+// IdentityAlias maps an author identity to the canonical identity stats aggregate it under.
+// Emails and commit names match case-insensitively and are stored lower-cased.
+type IdentityAlias struct {
+	ID             int64     `json:"id"`
+	RepositoryID   int64     `json:"repository_id"`
+	Source         string    `json:"source"`
+	Email          string    `json:"email"`
+	CommitName     string    `json:"commit_name,omitempty"`     // Only commits with this author name match (empty = any)
+	CanonicalEmail *string   `json:"canonical_email,omitempty"` // Nil keeps the email
+	CanonicalName  *string   `json:"canonical_name,omitempty"`  // Nil keeps the name
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// FileOwnership records how many lines of a file at HEAD were last changed by an author (git blame)
+type FileOwnership struct {
+	ID           int64  `json:"id"`
+	RepositoryID int64  `json:"repository_id"`
+	FilePath     string `json:"file_path"`
+	AuthorEmail  string `json:"author_email"`
+	AuthorName   string `json:"author_name"`
+	Lines        int    `json:"lines"`
+}
+
 // Commit represents a single point in the repository timeline
 type Commit struct {
-	ID           int64     `json:"id"`
-	RepositoryID int64     `json:"repository_id"`
-	Hash         string    `json:"hash"`
-	AuthorEmail  string    `json:"author_email"` // Denormalized for easier querying
-	AuthorName   string    `json:"author_name"`
-	Message      string    `json:"message"`
-	CommittedAt  time.Time `json:"committed_at"`
-	CreatedAt    time.Time `json:"created_at"`
+	ID              int64           `json:"id"`
+	RepositoryID    int64           `json:"repository_id"`
+	Hash            string          `json:"hash"`
+	AuthorEmail     string          `json:"author_email"` // Denormalized for easier querying
+	AuthorName      string          `json:"author_name"`
+	Message         string          `json:"message"`
+	ParentHashes    []string        `json:"parent_hashes"`
+	IsMerge         bool            `json:"is_merge"` // More than one parent
+	CommittedAt     time.Time       `json:"committed_at"`
+	CommitterEmail  string          `json:"committer_email"` // Differs from the author for rebased or applied commits
+	CommitterName   string          `json:"committer_name"`
+	CommitterAt     *time.Time      `json:"committer_at,omitempty"`
+	Type            CommitType      `json:"type"`
+	Scope           *string         `json:"scope,omitempty"`          // Area named by the message, e.g. "api" in "fix(api): ..."
+	SignatureType   *SignatureType  `json:"signature_type,omitempty"` // nil when unsigned
+	SignatureStatus SignatureStatus `json:"signature_status"`
+	SignatureKey    *string         `json:"signature_key,omitempty"` // Fingerprint of the trusted key that verified it
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+// CommitCoAuthor credits a person named in a commit's Co-authored-by trailer
+type CommitCoAuthor struct {
+	ID           int64  `json:"id"`
+	RepositoryID int64  `json:"repository_id"`
+	CommitHash   string `json:"commit_hash"`
+	Email        string `json:"email"`
+	Name         string `json:"name"`
+	IsBot        bool   `json:"is_bot"` // Automated account, left out of stats with exclude_bots
 }
 
 // CommitFile records the modification of a specific file in a specific commit
 // This is the atomic unit of "Data" for insights like Churn, Hotspots, and Knowledge Map.
 type CommitFile struct {
+	ID           int64      `json:"id"`
+	CommitHash   string     `json:"commit_hash"`
+	RepositoryID int64      `json:"repository_id"`
+	FilePath     string     `json:"file_path"`          // Captured at the time of commit
+	OldPath      *string    `json:"old_path,omitempty"` // Source path for renames and copies
+	ChangeType   ChangeType `json:"change_type"`
+	Additions    int        `json:"additions"`
+	Deletions    int        `json:"deletions"`
+}
+
+// CommitFunction is a Go function or method touched by a commit
+type CommitFunction struct {
 	ID           int64  `json:"id"`
 	CommitHash   string `json:"commit_hash"`
 	RepositoryID int64  `json:"repository_id"`
 	FilePath     string `json:"file_path"` // Captured at the time of commit
+	Function     string `json:"function"`  // "Parse", "Server.Start" or "(*Server).Handle"
 	Additions    int    `json:"additions"`
 	Deletions    int    `json:"deletions"`
 }
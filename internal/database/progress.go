@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertIndexingProgress records the current position of a repository's index
+func (db *DB) UpsertIndexingProgress(ctx context.Context, p *IndexingProgress) error {
+	query := `
+		INSERT INTO indexing_progress (repository_id, phase, percent, commits_processed, commits_total, eta_seconds, started_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		ON CONFLICT (repository_id)
+		DO UPDATE SET
+			phase = EXCLUDED.phase,
+			percent = EXCLUDED.percent,
+			commits_processed = EXCLUDED.commits_processed,
+			commits_total = EXCLUDED.commits_total,
+			eta_seconds = EXCLUDED.eta_seconds,
+			started_at = EXCLUDED.started_at,
+			updated_at = NOW()
+		RETURNING updated_at
+	`
+
+	err := db.pool.QueryRow(ctx, query,
+		p.RepositoryID, p.Phase, p.Percent, p.CommitsProcessed, p.CommitsTotal, p.ETASeconds, p.StartedAt,
+	).Scan(&p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to upsert indexing progress: %w", err)
+	}
+
+	return nil
+}
+
+// GetIndexingProgress retrieves the progress of a repository's running or last index
+func (db *DB) GetIndexingProgress(ctx context.Context, repositoryID int64) (*IndexingProgress, error) {
+	query := `
+		SELECT repository_id, phase, percent, commits_processed, commits_total, eta_seconds, started_at, updated_at
+		FROM indexing_progress
+		WHERE repository_id = $1
+	`
+
+	p := &IndexingProgress{}
+	err := db.pool.QueryRow(ctx, query, repositoryID).Scan(
+		&p.RepositoryID, &p.Phase, &p.Percent, &p.CommitsProcessed, &p.CommitsTotal, &p.ETASeconds, &p.StartedAt, &p.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get indexing progress: %w", err)
+	}
+
+	return p, nil
+}
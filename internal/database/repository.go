@@ -11,8 +11,9 @@ import (
 // CreateRepository creates a new repository record
 func (db *DB) CreateRepository(ctx context.Context, repo *Repository) error {
 	query := `
-		INSERT INTO repositories (url, status, default_branch, user_id, name, description, is_private, provider)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO repositories (url, status, default_branch, user_id, name, description, is_private, provider, branch_mode, branches, first_parent,
+		                          clone_depth, clone_since, single_branch, bot_patterns)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
 		RETURNING id, created_at, updated_at
 	`
 
@@ -20,10 +21,21 @@ func (db *DB) CreateRepository(ctx context.Context, repo *Repository) error {
 	if defaultBranch == "" {
 		defaultBranch = "main"
 	}
+	if repo.BranchMode == "" {
+		repo.BranchMode = BranchModeDefault
+	}
+	if repo.Branches == nil {
+		repo.Branches = []string{}
+	}
+	if repo.BotPatterns == nil {
+		repo.BotPatterns = []string{}
+	}
 
 	err := db.pool.QueryRow(ctx, query,
 		repo.URL, repo.Status, defaultBranch, repo.UserID,
 		repo.Name, repo.Description, repo.IsPrivate, repo.Provider,
+		repo.BranchMode, repo.Branches, repo.FirstParent,
+		repo.CloneDepth, repo.CloneSince, repo.SingleBranch, repo.BotPatterns,
 	).Scan(&repo.ID, &repo.CreatedAt, &repo.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to create repository: %w", err)
@@ -85,7 +97,8 @@ func (db *DB) UpsertRepository(ctx context.Context, repo *Repository) error {
 func (db *DB) GetRepository(ctx context.Context, id int64) (*Repository, error) {
 	query := `
 		SELECT id, url, local_path, default_branch, status, last_indexed_at, created_at, updated_at, 
-		       user_id, name, description, is_private, provider
+		       user_id, name, description, is_private, provider, last_indexed_commit, branch_mode, branches, first_parent,
+		       clone_depth, clone_since, single_branch, history_truncated, bot_patterns
 		FROM repositories
 		WHERE id = $1
 	`
@@ -94,6 +107,8 @@ func (db *DB) GetRepository(ctx context.Context, id int64) (*Repository, error)
 	err := db.pool.QueryRow(ctx, query, id).Scan(
 		&repo.ID, &repo.URL, &repo.LocalPath, &repo.DefaultBranch, &repo.Status, &repo.LastIndexedAt,
 		&repo.CreatedAt, &repo.UpdatedAt, &repo.UserID, &repo.Name, &repo.Description, &repo.IsPrivate, &repo.Provider,
+		&repo.LastIndexedCommit, &repo.BranchMode, &repo.Branches, &repo.FirstParent,
+		&repo.CloneDepth, &repo.CloneSince, &repo.SingleBranch, &repo.HistoryTruncated, &repo.BotPatterns,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -109,7 +124,8 @@ func (db *DB) GetRepository(ctx context.Context, id int64) (*Repository, error)
 func (db *DB) GetRepositoryForUser(ctx context.Context, id int64, userID int64) (*Repository, error) {
 	query := `
 		SELECT id, url, local_path, default_branch, status, last_indexed_at, created_at, updated_at, 
-		       user_id, name, description, is_private, provider
+		       user_id, name, description, is_private, provider, last_indexed_commit, branch_mode, branches, first_parent,
+		       clone_depth, clone_since, single_branch, history_truncated, bot_patterns
 		FROM repositories
 		WHERE id = $1 AND user_id = $2
 	`
@@ -118,6 +134,8 @@ func (db *DB) GetRepositoryForUser(ctx context.Context, id int64, userID int64)
 	err := db.pool.QueryRow(ctx, query, id, userID).Scan(
 		&repo.ID, &repo.URL, &repo.LocalPath, &repo.DefaultBranch, &repo.Status, &repo.LastIndexedAt,
 		&repo.CreatedAt, &repo.UpdatedAt, &repo.UserID, &repo.Name, &repo.Description, &repo.IsPrivate, &repo.Provider,
+		&repo.LastIndexedCommit, &repo.BranchMode, &repo.Branches, &repo.FirstParent,
+		&repo.CloneDepth, &repo.CloneSince, &repo.SingleBranch, &repo.HistoryTruncated, &repo.BotPatterns,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -153,7 +171,8 @@ func (db *DB) GetRepositoryStatus(ctx context.Context, id int64, userID int64) (
 func (db *DB) GetRepositoryByURL(ctx context.Context, url string) (*Repository, error) {
 	query := `
 		SELECT id, url, local_path, default_branch, status, last_indexed_at, created_at, updated_at,
-		       user_id, name, description, is_private, provider
+		       user_id, name, description, is_private, provider, last_indexed_commit, branch_mode, branches, first_parent,
+		       clone_depth, clone_since, single_branch, history_truncated, bot_patterns
 		FROM repositories
 		WHERE url = $1
 	`
@@ -162,6 +181,8 @@ func (db *DB) GetRepositoryByURL(ctx context.Context, url string) (*Repository,
 	err := db.pool.QueryRow(ctx, query, url).Scan(
 		&repo.ID, &repo.URL, &repo.LocalPath, &repo.DefaultBranch, &repo.Status, &repo.LastIndexedAt,
 		&repo.CreatedAt, &repo.UpdatedAt, &repo.UserID, &repo.Name, &repo.Description, &repo.IsPrivate, &repo.Provider,
+		&repo.LastIndexedCommit, &repo.BranchMode, &repo.Branches, &repo.FirstParent,
+		&repo.CloneDepth, &repo.CloneSince, &repo.SingleBranch, &repo.HistoryTruncated, &repo.BotPatterns,
 	)
 	if err != nil {
 		if err == pgx.ErrNoRows {
@@ -177,7 +198,8 @@ func (db *DB) GetRepositoryByURL(ctx context.Context, url string) (*Repository,
 func (db *DB) ListRepositories(ctx context.Context, userID int64, limit, offset int) ([]*Repository, error) {
 	query := `
 		SELECT id, url, local_path, default_branch, status, last_indexed_at, created_at, updated_at,
-		       user_id, name, description, is_private, provider
+		       user_id, name, description, is_private, provider, last_indexed_commit, branch_mode, branches, first_parent,
+		       clone_depth, clone_since, single_branch, history_truncated, bot_patterns
 		FROM repositories
 		WHERE user_id = $1
 		ORDER BY last_pushed_at DESC NULLS LAST, created_at DESC
@@ -196,6 +218,8 @@ func (db *DB) ListRepositories(ctx context.Context, userID int64, limit, offset
 		err := rows.Scan(
 			&repo.ID, &repo.URL, &repo.LocalPath, &repo.DefaultBranch, &repo.Status, &repo.LastIndexedAt,
 			&repo.CreatedAt, &repo.UpdatedAt, &repo.UserID, &repo.Name, &repo.Description, &repo.IsPrivate, &repo.Provider,
+			&repo.LastIndexedCommit, &repo.BranchMode, &repo.Branches, &repo.FirstParent,
+			&repo.CloneDepth, &repo.CloneSince, &repo.SingleBranch, &repo.HistoryTruncated, &repo.BotPatterns,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan repository: %w", err)
@@ -231,15 +255,30 @@ func (db *DB) UpdateRepository(ctx context.Context, repo *Repository) error {
 	query := `
 		UPDATE repositories
 		SET local_path = $1, status = $2, last_indexed_at = $3, default_branch = $4,
-		    name = $5, description = $6, is_private = $7, provider = $8, user_id = $9
-		WHERE id = $10
+		    name = $5, description = $6, is_private = $7, provider = $8, user_id = $9,
+		    last_indexed_commit = $10, branch_mode = $11, branches = $12, first_parent = $13,
+		    clone_depth = $14, clone_since = $15, single_branch = $16, history_truncated = $17,
+		    bot_patterns = $18
+		WHERE id = $19
 		RETURNING updated_at
 	`
 
+	if repo.BranchMode == "" {
+		repo.BranchMode = BranchModeDefault
+	}
+	if repo.Branches == nil {
+		repo.Branches = []string{}
+	}
+	if repo.BotPatterns == nil {
+		repo.BotPatterns = []string{}
+	}
+
 	err := db.pool.QueryRow(ctx, query,
 		repo.LocalPath, repo.Status, repo.LastIndexedAt, repo.DefaultBranch,
 		repo.Name, repo.Description, repo.IsPrivate, repo.Provider, repo.UserID,
-		repo.ID,
+		repo.LastIndexedCommit, repo.BranchMode, repo.Branches, repo.FirstParent,
+		repo.CloneDepth, repo.CloneSince, repo.SingleBranch, repo.HistoryTruncated,
+		repo.BotPatterns, repo.ID,
 	).Scan(&repo.UpdatedAt)
 	if err != nil {
 		return fmt.Errorf("failed to update repository: %w", err)
@@ -323,3 +362,38 @@ func (db *DB) GetStaleRepositories(ctx context.Context, olderThan time.Duration)
 
 	return repositories, nil
 }
+
+// repositoryDataTables hold per-repository rows without a foreign key to repositories,
+// so they are not removed by the ON DELETE CASCADE of the older tables
+var repositoryDataTables = append([]string{"indexing_progress"}, indexTables...)
+
+// DeleteRepository removes a repository and all its indexed data
+func (db *DB) DeleteRepository(ctx context.Context, id int64) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Including the data of an unfinished index
+	for _, table := range repositoryDataTables {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE repository_id IN ($1, $2)", table), id, StagingID(id)); err != nil {
+			return fmt.Errorf("failed to delete %s: %w", table, err)
+		}
+	}
+
+	// Deploy keys, clone storage and identity aliases cascade
+	result, err := tx.Exec(ctx, `DELETE FROM repositories WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
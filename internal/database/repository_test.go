@@ -33,7 +33,7 @@ func TestCreateRepository(t *testing.T) {
 	}
 
 	mock.ExpectQuery("INSERT INTO repositories").
-		WithArgs(repo.URL, repo.Status, "main", repo.UserID, repo.Name, repo.Description, repo.IsPrivate, repo.Provider).
+		WithArgs(repo.URL, repo.Status, "main", repo.UserID, repo.Name, repo.Description, repo.IsPrivate, repo.Provider, BranchModeDefault, []string{}, false, 0, repo.CloneSince, false, []string{}).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).
 			AddRow(int64(10), time.Now(), time.Now()))
 
@@ -64,8 +64,8 @@ func TestGetRepository(t *testing.T) {
 	expectedID := int64(10)
 	mock.ExpectQuery("SELECT id, url, local_path, default_branch, status, last_indexed_at, created_at, updated_at, user_id, name, description, is_private, provider").
 		WithArgs(expectedID).
-		WillReturnRows(pgxmock.NewRows([]string{"id", "url", "local_path", "default_branch", "status", "last_indexed_at", "created_at", "updated_at", "user_id", "name", "description", "is_private", "provider"}).
-			AddRow(expectedID, "url", nil, "main", StatusPending, nil, time.Now(), time.Now(), int64(1), "name", "desc", false, "github"))
+		WillReturnRows(pgxmock.NewRows([]string{"id", "url", "local_path", "default_branch", "status", "last_indexed_at", "created_at", "updated_at", "user_id", "name", "description", "is_private", "provider", "last_indexed_commit", "branch_mode", "branches", "first_parent", "clone_depth", "clone_since", "single_branch", "history_truncated", "bot_patterns"}).
+			AddRow(expectedID, "url", nil, "main", StatusPending, nil, time.Now(), time.Now(), int64(1), "name", "desc", false, "github", nil, BranchModeDefault, []string{}, false, 0, nil, false, false, []string{}))
 
 	repo, err := db.GetRepository(ctx, expectedID)
 	if err != nil {
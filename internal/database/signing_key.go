@@ -0,0 +1,70 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// CreateSigningKey adds a trusted signing key to a repository
+func (db *DB) CreateSigningKey(ctx context.Context, key *SigningKey) error {
+	query := `
+		INSERT INTO signing_keys (repository_id, key_type, public_key, fingerprint, name)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := db.pool.QueryRow(ctx, query, key.RepositoryID, key.Type, key.PublicKey, key.Fingerprint, key.Name).
+		Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create signing key: %w", err)
+	}
+
+	return nil
+}
+
+// ListSigningKeys retrieves the trusted signing keys of a repository, oldest first
+func (db *DB) ListSigningKeys(ctx context.Context, repositoryID int64) ([]*SigningKey, error) {
+	query := `
+		SELECT id, repository_id, key_type, public_key, fingerprint, name, created_at
+		FROM signing_keys
+		WHERE repository_id = $1
+		ORDER BY id
+	`
+
+	rows, err := db.pool.Query(ctx, query, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list signing keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []*SigningKey{}
+	for rows.Next() {
+		k := &SigningKey{}
+		if err := rows.Scan(&k.ID, &k.RepositoryID, &k.Type, &k.PublicKey, &k.Fingerprint, &k.Name, &k.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan signing key: %w", err)
+		}
+		keys = append(keys, k)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return keys, nil
+}
+
+// DeleteSigningKey removes a trusted signing key of a repository
+func (db *DB) DeleteSigningKey(ctx context.Context, repositoryID, id int64) error {
+	query := `DELETE FROM signing_keys WHERE repository_id = $1 AND id = $2`
+
+	result, err := db.pool.Exec(ctx, query, repositoryID, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete signing key: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// UpsertSnapshots batch inserts or updates sampled language totals
+func (db *DB) UpsertSnapshots(ctx context.Context, snapshots []*Snapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO snapshots (repository_id, period_start, commit_hash, committed_at, language, files, lines, code_lines)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (repository_id, period_start, language)
+		DO UPDATE SET
+			commit_hash = EXCLUDED.commit_hash,
+			committed_at = EXCLUDED.committed_at,
+			files = EXCLUDED.files,
+			lines = EXCLUDED.lines,
+			code_lines = EXCLUDED.code_lines
+	`
+
+	batch := &pgx.Batch{}
+	for _, s := range snapshots {
+		batch.Queue(query, s.RepositoryID, s.PeriodStart, s.CommitHash, s.CommittedAt, s.Language, s.Files, s.Lines, s.CodeLines)
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	for range snapshots {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return fmt.Errorf("failed to execute batch: %w", err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetSnapshotPeriods retrieves the periods already sampled for a repository
+func (db *DB) GetSnapshotPeriods(ctx context.Context, repositoryID int64) ([]time.Time, error) {
+	query := `SELECT DISTINCT period_start FROM snapshots WHERE repository_id = $1`
+
+	rows, err := db.pool.Query(ctx, query, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get snapshot periods: %w", err)
+	}
+	defer rows.Close()
+
+	periods := []time.Time{}
+	for rows.Next() {
+		var period time.Time
+		if err := rows.Scan(&period); err != nil {
+			return nil, fmt.Errorf("failed to scan snapshot period: %w", err)
+		}
+		periods = append(periods, period)
+	}
+
+	return periods, nil
+}
+
+// DeleteSnapshotsByRepository deletes all growth snapshots for a repository
+func (db *DB) DeleteSnapshotsByRepository(ctx context.Context, repositoryID int64) error {
+	query := `DELETE FROM snapshots WHERE repository_id = $1`
+
+	_, err := db.pool.Exec(ctx, query, repositoryID)
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshots: %w", err)
+	}
+
+	return nil
+}
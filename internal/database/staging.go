@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// indexTables hold the data written by an index. A full index writes all of them under the
+// staging key and promotes them together, so readers keep the previous index until it is done.
+var indexTables = []string{
+	"files",
+	"file_ownership",
+	"contributors",
+	"commits",
+	"commit_files",
+	"commit_functions",
+	"commit_coauthors",
+	"commit_branches",
+	"branch_tips",
+	"snapshots",
+	"tags",
+	"release_commits",
+}
+
+// snapshotTables hold the inventory of HEAD, which every index replaces. An incremental index
+// only appends history, so it stages and promotes these alone.
+var snapshotTables = []string{
+	"files",
+	"file_ownership",
+}
+
+// StagingID returns the key an index writes a repository's data under until it is promoted.
+// Repository IDs are positive, so the negated ID never collides with live data.
+func StagingID(repositoryID int64) int64 {
+	return -repositoryID
+}
+
+// ClearStaging removes the staged data of a repository, e.g. left behind by an index that failed,
+// and the checkpoint to resume it from
+func (db *DB) ClearStaging(ctx context.Context, repositoryID int64) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range indexTables {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE repository_id = $1", table), StagingID(repositoryID)); err != nil {
+			return fmt.Errorf("failed to clear staged %s: %w", table, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM index_checkpoints WHERE repository_id = $1`, repositoryID); err != nil {
+		return fmt.Errorf("failed to delete index checkpoint: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// PromoteStaging replaces all indexed data of a repository with its staged data in one transaction.
+// Nothing is left staged, so the checkpoint of the index goes too.
+func (db *DB) PromoteStaging(ctx context.Context, repositoryID int64) error {
+	return db.promote(ctx, repositoryID, indexTables, true)
+}
+
+// PromoteSnapshot replaces the HEAD inventory of a repository with its staged one in one transaction
+func (db *DB) PromoteSnapshot(ctx context.Context, repositoryID int64) error {
+	return db.promote(ctx, repositoryID, snapshotTables, false)
+}
+
+// promote swaps the staged rows of the given tables in for the live ones
+func (db *DB) promote(ctx context.Context, repositoryID int64, tables []string, clearCheckpoint bool) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, table := range tables {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE repository_id = $1", table), repositoryID); err != nil {
+			return fmt.Errorf("failed to clear %s: %w", table, err)
+		}
+		query := fmt.Sprintf("UPDATE %s SET repository_id = $1 WHERE repository_id = $2", table)
+		if _, err := tx.Exec(ctx, query, repositoryID, StagingID(repositoryID)); err != nil {
+			return fmt.Errorf("failed to promote %s: %w", table, err)
+		}
+	}
+	if clearCheckpoint {
+		if _, err := tx.Exec(ctx, `DELETE FROM index_checkpoints WHERE repository_id = $1`, repositoryID); err != nil {
+			return fmt.Errorf("failed to delete index checkpoint: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
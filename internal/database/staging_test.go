@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"testing"
+
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestPromoteSnapshot(t *testing.T) {
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock pool: %v", err)
+	}
+	defer mock.Close()
+
+	db := NewTestDB(mock)
+	ctx := context.Background()
+
+	repoID := int64(7)
+	mock.ExpectBegin()
+	for _, table := range []string{"files", "file_ownership"} {
+		mock.ExpectExec("DELETE FROM " + table + " WHERE repository_id").
+			WithArgs(repoID).
+			WillReturnResult(pgxmock.NewResult("DELETE", 3))
+		mock.ExpectExec("UPDATE "+table+" SET repository_id").
+			WithArgs(repoID, int64(-7)).
+			WillReturnResult(pgxmock.NewResult("UPDATE", 4))
+	}
+	mock.ExpectCommit()
+
+	if err := db.PromoteSnapshot(ctx, repoID); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
+
+// unstagedTables hold per-repository rows that an index does not write, so they are not staged
+var unstagedTables = map[string]string{
+	"repository_deploy_keys": "cascades from repositories",
+	"clone_storage":          "cascades from repositories",
+	"identity_aliases":       "replaced in the transaction promoting an index; cascades from repositories",
+	"index_checkpoints":      "cleared by ClearStaging and PromoteStaging; cascades from repositories",
+	"signing_keys":           "cascades from repositories",
+	"indexing_progress":      "deleted by DeleteRepository",
+}
+
+// repositoryTables returns the tables the migrations give a repository_id column, in creation order
+func repositoryTables(t *testing.T) []string {
+	t.Helper()
+	paths, err := filepath.Glob(filepath.Join("..", "..", "migrations", "*.up.sql"))
+	if err != nil || len(paths) == 0 {
+		t.Fatalf("failed to find migrations: %v", err)
+	}
+	sort.Strings(paths)
+
+	createTable := regexp.MustCompile(`(?is)CREATE TABLE(?: IF NOT EXISTS)?\s+(\w+)\s*\((.*?)\);`)
+	addColumn := regexp.MustCompile(`(?i)ALTER TABLE\s+(\w+)\s+ADD COLUMN\s+repository_id\b`)
+	dropTable := regexp.MustCompile(`(?i)DROP TABLE(?: IF EXISTS)?\s+(\w+)`)
+	repositoryID := regexp.MustCompile(`\brepository_id\b`)
+
+	var tables []string
+	has := make(map[string]bool)
+	for _, path := range paths {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", path, err)
+		}
+		for _, m := range createTable.FindAllStringSubmatch(string(content), -1) {
+			if repositoryID.MatchString(m[2]) && !has[m[1]] {
+				has[m[1]] = true
+				tables = append(tables, m[1])
+			}
+		}
+		for _, m := range addColumn.FindAllStringSubmatch(string(content), -1) {
+			if !has[m[1]] {
+				has[m[1]] = true
+				tables = append(tables, m[1])
+			}
+		}
+		for _, m := range dropTable.FindAllStringSubmatch(string(content), -1) {
+			delete(has, m[1])
+		}
+	}
+
+	var existing []string
+	for _, table := range tables {
+		if has[table] {
+			existing = append(existing, table)
+		}
+	}
+	return existing
+}
+
+func TestIndexTablesCoverRepositoryTables(t *testing.T) {
+	staged := make(map[string]bool, len(indexTables))
+	for _, table := range indexTables {
+		staged[table] = true
+	}
+
+	// Staged rows have no foreign key to cascade from, so a table missing from
+	// indexTables would keep the rows of failed indexes and deleted repositories
+	found := make(map[string]bool)
+	for _, table := range repositoryTables(t) {
+		found[table] = true
+		_, unstaged := unstagedTables[table]
+		switch {
+		case staged[table] && unstaged:
+			t.Errorf("table %s is both staged and listed as unstaged", table)
+		case !staged[table] && !unstaged:
+			t.Errorf("table %s has a repository_id column but is missing from indexTables", table)
+		}
+	}
+
+	for _, table := range indexTables {
+		if !found[table] {
+			t.Errorf("indexTables lists %s, which has no repository_id column", table)
+		}
+	}
+	for table := range unstagedTables {
+		if !found[table] {
+			t.Errorf("unstagedTables lists %s, which has no repository_id column", table)
+		}
+	}
+}
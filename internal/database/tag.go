@@ -0,0 +1,72 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// releaseCommitChunkSize is the number of commit hashes inserted per statement
+const releaseCommitChunkSize = 5000
+
+// ReplaceTags replaces the tags of a repository and the commits each of them released.
+// releases maps a tag name to the hashes of the commits it introduced.
+func (db *DB) ReplaceTags(ctx context.Context, repositoryID int64, tags []*Tag, releases map[string][]string) error {
+	tx, err := db.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `DELETE FROM tags WHERE repository_id = $1`, repositoryID); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM release_commits WHERE repository_id = $1`, repositoryID); err != nil {
+		return fmt.Errorf("failed to clear release commits: %w", err)
+	}
+
+	tagQuery := `
+		INSERT INTO tags (repository_id, name, commit_hash, is_annotated, message, tagger_name, tagger_email, tagged_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	releaseQuery := `
+		INSERT INTO release_commits (repository_id, tag_name, commit_hash)
+		SELECT $1, $3, hash FROM unnest($2::text[]) AS hash
+		ON CONFLICT DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, t := range tags {
+		batch.Queue(tagQuery, repositoryID, t.Name, t.CommitHash, t.IsAnnotated, t.Message, t.TaggerName, t.TaggerEmail, t.TaggedAt)
+	}
+	for tag, hashes := range releases {
+		for i := 0; i < len(hashes); i += releaseCommitChunkSize {
+			end := i + releaseCommitChunkSize
+			if end > len(hashes) {
+				end = len(hashes)
+			}
+			batch.Queue(releaseQuery, repositoryID, hashes[i:end], tag)
+		}
+	}
+
+	br := tx.SendBatch(ctx, batch)
+
+	for i := 0; i < batch.Len(); i++ {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return fmt.Errorf("failed to execute batch: %w", err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return fmt.Errorf("failed to close batch: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
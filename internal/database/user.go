@@ -160,3 +160,17 @@ func (db *DB) GetUserIdentities(ctx context.Context, userID int64) ([]*UserIdent
 	}
 	return identities, nil
 }
+
+// UpdateUserIdentityToken stores a refreshed OAuth token for an identity
+func (db *DB) UpdateUserIdentityToken(ctx context.Context, identity *UserIdentity) error {
+	query := `
+		UPDATE user_identities
+		SET access_token = $1, refresh_token = $2, token_expiry = $3
+		WHERE id = $4
+	`
+	_, err := db.pool.Exec(ctx, query, identity.AccessToken, identity.RefreshToken, identity.TokenExpiry, identity.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update identity token: %w", err)
+	}
+	return nil
+}
@@ -2,9 +2,10 @@ package git
 
 import (
 	"context"
-	"os"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
 )
 
 type BitBucket struct{}
@@ -13,15 +14,15 @@ func NewBitBucket() *BitBucket {
 	return &BitBucket{}
 }
 
-func (b *BitBucket) CloneRepository(ctx context.Context, repoPath string, localPath string) (*git.Repository, error) {
-	// Use bare clone (isBare: true) to only clone .git directory without working tree
-	// This saves disk space and is faster since we only need git history for analysis
-	r, err := git.PlainClone(localPath, true, &git.CloneOptions{
-		URL:      repoPath,
-		Progress: os.Stdout,
-	})
-	if err != nil {
-		return nil, err
-	}
-	return r, nil
+func (b *BitBucket) Name() string {
+	return "bitbucket"
+}
+
+// TokenAuth uses Bitbucket's x-token-auth user for OAuth access tokens
+func (b *BitBucket) TokenAuth(token string) transport.AuthMethod {
+	return &http.BasicAuth{Username: "x-token-auth", Password: token}
+}
+
+func (b *BitBucket) CloneRepository(ctx context.Context, repoPath string, localPath string, opts CloneOptions) (*git.Repository, error) {
+	return cloneRepository(ctx, repoPath, localPath, opts)
 }
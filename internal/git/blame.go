@@ -0,0 +1,58 @@
+package git
+
+import (
+	"context"
+	"log"
+	"sort"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// blameOwnership counts, for each file, the lines at HEAD last changed by each author.
+// Files that cannot be blamed are logged and skipped.
+func blameOwnership(ctx context.Context, repoID int64, headCommit *object.Commit, paths []string) ([]*database.FileOwnership, error) {
+	ownership := []*database.FileOwnership{}
+
+	for _, path := range paths {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		result, err := git.Blame(headCommit, path)
+		if err != nil {
+			log.Printf("Failed to blame %s: %v", path, err)
+			continue
+		}
+
+		byAuthor := make(map[string]*database.FileOwnership)
+		for _, line := range result.Lines {
+			o, ok := byAuthor[line.Author]
+			if !ok {
+				o = &database.FileOwnership{
+					RepositoryID: repoID,
+					FilePath:     path,
+					AuthorEmail:  line.Author,
+					AuthorName:   line.AuthorName,
+				}
+				byAuthor[line.Author] = o
+			}
+			o.Lines++
+		}
+
+		fileOwnership := make([]*database.FileOwnership, 0, len(byAuthor))
+		for _, o := range byAuthor {
+			fileOwnership = append(fileOwnership, o)
+		}
+		sort.Slice(fileOwnership, func(i, j int) bool {
+			return fileOwnership[i].AuthorEmail < fileOwnership[j].AuthorEmail
+		})
+		ownership = append(ownership, fileOwnership...)
+	}
+
+	return ownership, nil
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestBlameOwnership(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo := f.repo
+
+	commit := func(email, content string) {
+		t.Helper()
+		f.add("main.go", content)
+		f.save("change", &object.Signature{Name: email, Email: email, When: time.Now()})
+	}
+
+	// bob rewrites two of alice's three lines, so alice only keeps one
+	commit("alice@example.com", "package main\n\nfunc a() {}\n")
+	commit("bob@example.com", "package main\nfunc b() {}\nfunc c() {}\n")
+
+	ref, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	head, err := repo.CommitObject(ref.Hash())
+	if err != nil {
+		t.Fatalf("failed to get HEAD commit: %v", err)
+	}
+
+	ownership, err := blameOwnership(context.Background(), 1, head, []string{"main.go", "missing.go"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	expected := map[string]int{"alice@example.com": 1, "bob@example.com": 2}
+	if len(ownership) != len(expected) {
+		t.Fatalf("expected %d ownership records, got %d", len(expected), len(ownership))
+	}
+	for _, o := range ownership {
+		if o.FilePath != "main.go" || o.RepositoryID != 1 {
+			t.Errorf("unexpected record %+v", o)
+		}
+		if o.Lines != expected[o.AuthorEmail] {
+			t.Errorf("%s: expected %d lines, got %d", o.AuthorEmail, expected[o.AuthorEmail], o.Lines)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// BranchSelection chooses the branches whose history is indexed
+type BranchSelection struct {
+	Default  string              // Default branch; HEAD is used when empty or missing from the clone
+	Mode     database.BranchMode // Defaults to database.BranchModeDefault
+	Branches []string            // Additional branches for database.BranchModeList
+}
+
+// branchTip is the commit a branch currently points at
+type branchTip struct {
+	Name string
+	Hash plumbing.Hash
+}
+
+// resolveBranches returns the tips of the selected branches, the default branch first.
+// Listed branches missing from the clone are logged and skipped.
+func resolveBranches(repo *git.Repository, sel BranchSelection) ([]branchTip, error) {
+	primary, err := defaultBranchTip(repo, sel.Default)
+	if err != nil {
+		return nil, err
+	}
+	tips := []branchTip{primary}
+
+	var names []string
+	switch sel.Mode {
+	case database.BranchModeList:
+		names = sel.Branches
+	case database.BranchModeAll:
+		names, err = branchNames(repo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list branches: %w", err)
+		}
+	}
+
+	seen := map[string]bool{primary.Name: true}
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		hash, err := branchHash(repo, name)
+		if err != nil {
+			log.Printf("Branch %s not found, skipping: %v", name, err)
+			continue
+		}
+		tips = append(tips, branchTip{Name: name, Hash: hash})
+	}
+
+	return tips, nil
+}
+
+// defaultBranchTip resolves the default branch, falling back to whatever HEAD points at
+func defaultBranchTip(repo *git.Repository, name string) (branchTip, error) {
+	if name != "" {
+		if hash, err := branchHash(repo, name); err == nil {
+			return branchTip{Name: name, Hash: hash}, nil
+		}
+		log.Printf("Default branch %s not found, using HEAD", name)
+	}
+
+	ref, err := repo.Head()
+	if err != nil {
+		return branchTip{}, fmt.Errorf("failed to get HEAD reference: %w", err)
+	}
+
+	name = "HEAD"
+	if ref.Name().IsBranch() {
+		name = ref.Name().Short()
+	}
+	return branchTip{Name: name, Hash: ref.Hash()}, nil
+}
+
+// branchHash resolves a branch by name. Fetched branches live in refs/heads, while a
+// fresh clone only has the remote-tracking refs/remotes/origin ones.
+func branchHash(repo *git.Repository, name string) (plumbing.Hash, error) {
+	ref, err := repo.Reference(plumbing.NewBranchReferenceName(name), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		ref, err = repo.Reference(plumbing.NewRemoteReferenceName(git.DefaultRemoteName, name), true)
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return ref.Hash(), nil
+}
+
+// branchNames lists every local and remote-tracking branch of the clone
+func branchNames(repo *git.Repository) ([]string, error) {
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	defer refs.Close()
+
+	prefix := git.DefaultRemoteName + "/"
+	unique := make(map[string]bool)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		switch {
+		case ref.Name().IsBranch():
+			unique[ref.Name().Short()] = true
+		case ref.Name().IsRemote():
+			name := strings.TrimPrefix(ref.Name().Short(), prefix)
+			if name != ref.Name().Short() && name != "HEAD" {
+				unique[name] = true
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(unique))
+	for name := range unique {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// branchesIter walks the history of several branch tips one after another, yielding each commit once.
+// Commits in seen (e.g. already indexed ones) are skipped together with their ancestors.
+// With firstParent only the mainline of each branch is walked. limit may be nil.
+type branchesIter struct {
+	repo        *git.Repository
+	tips        []branchTip
+	seen        map[plumbing.Hash]bool
+	firstParent bool
+	limit       *historyLimit
+	current     object.CommitIter
+}
+
+func newBranchesIter(repo *git.Repository, tips []branchTip, seen map[plumbing.Hash]bool, firstParent bool, limit *historyLimit) *branchesIter {
+	return &branchesIter{repo: repo, tips: tips, seen: seen, firstParent: firstParent, limit: limit}
+}
+
+func (it *branchesIter) Next() (*object.Commit, error) {
+	for {
+		if it.current == nil {
+			if len(it.tips) == 0 {
+				return nil, io.EOF
+			}
+			tip, err := it.repo.CommitObject(it.tips[0].Hash)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get tip of branch %s: %w", it.tips[0].Name, err)
+			}
+			it.tips = it.tips[1:]
+			if it.firstParent {
+				it.current = &firstParentIter{next: tip, seen: it.seen, limit: it.limit}
+			} else {
+				it.current = it.limit.preorder(tip, it.seen)
+			}
+		}
+
+		c, err := it.current.Next()
+		if err == io.EOF {
+			it.current.Close()
+			it.current = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if it.limit.excludes(c, it.seen) {
+			continue
+		}
+
+		// Later branches stop at commits yielded for earlier ones
+		it.seen[c.Hash] = true
+		return c, nil
+	}
+}
+
+func (it *branchesIter) ForEach(cb func(*object.Commit) error) error {
+	defer it.Close()
+	for {
+		c, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := cb(c); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (it *branchesIter) Close() {
+	if it.current != nil {
+		it.current.Close()
+		it.current = nil
+	}
+	it.tips = nil
+}
+
+// firstParentIter follows the first parent of each commit until a root, an already seen commit
+// or a bound of limit (which may be nil)
+type firstParentIter struct {
+	next  *object.Commit
+	seen  map[plumbing.Hash]bool
+	limit *historyLimit
+}
+
+func (it *firstParentIter) Next() (*object.Commit, error) {
+	c := it.next
+	if c == nil || it.seen[c.Hash] || it.limit.excludes(c, it.seen) {
+		return nil, io.EOF
+	}
+
+	it.next = nil
+	if c.NumParents() > 0 && !it.limit.isMissing(c.ParentHashes[0]) {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		it.next = parent
+	}
+	return c, nil
+}
+
+func (it *firstParentIter) ForEach(cb func(*object.Commit) error) error {
+	for {
+		c, err := it.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := cb(c); err != nil {
+			if err == storer.ErrStop {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (it *firstParentIter) Close() {
+	it.next = nil
+}
+
+// branchMembership lists, for every branch, the hashes of all commits reachable from its tip
+// (only along first parents with firstParent)
+func branchMembership(ctx context.Context, repo *git.Repository, tips []branchTip, firstParent bool, limit *historyLimit) (map[string][]string, error) {
+	membership := make(map[string][]string, len(tips))
+	for _, tip := range tips {
+		hashes := []string{}
+		err := walkBranch(ctx, repo, tip, make(map[plumbing.Hash]bool), firstParent, limit, func(c *object.Commit) {
+			hashes = append(hashes, c.Hash.String())
+		})
+		if err != nil {
+			return nil, err
+		}
+		membership[tip.Name] = hashes
+	}
+	return membership, nil
+}
+
+// addedBranchCommits lists the hashes of the commits a branch gained since it pointed at old, given the
+// commits it reached then. ok is false when old is no longer part of the branch's history (only along
+// first parents with firstParent), i.e. the branch was rewritten and its membership has to be rebuilt.
+func addedBranchCommits(ctx context.Context, repo *git.Repository, tip branchTip, old plumbing.Hash, members map[plumbing.Hash]bool, firstParent bool, limit *historyLimit) ([]string, bool, error) {
+	if tip.Hash == old {
+		return []string{}, true, nil
+	}
+
+	// The walk stops at commits the branch already reached, so old is only met as the parent of a new commit
+	hashes := []string{}
+	reached := false
+	err := walkBranch(ctx, repo, tip, members, firstParent, limit, func(c *object.Commit) {
+		hashes = append(hashes, c.Hash.String())
+		for i, parent := range c.ParentHashes {
+			if parent == old && (i == 0 || !firstParent) {
+				reached = true
+			}
+		}
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	if !reached {
+		return nil, false, nil
+	}
+	return hashes, true, nil
+}
+
+// walkBranch calls fn for every commit reachable from a branch tip, stopping at commits in seen
+func walkBranch(ctx context.Context, repo *git.Repository, tip branchTip, seen map[plumbing.Hash]bool, firstParent bool, limit *historyLimit, fn func(c *object.Commit)) error {
+	iter := newBranchesIter(repo, []branchTip{tip}, seen, firstParent, limit)
+	err := iter.ForEach(func(c *object.Commit) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		fn(c)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to walk branch %s: %w", tip.Name, err)
+	}
+	return nil
+}
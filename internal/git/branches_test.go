@@ -0,0 +1,176 @@
+package git
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestBranchSelection(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo, wt, commit := f.repo, f.wt, f.commitFiles
+
+	// master: root -> main; feature: root -> feature
+	root := commit("root.txt")
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	defaultBranch := head.Name().Short()
+
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true}); err != nil {
+		t.Fatalf("failed to create branch: %v", err)
+	}
+	feature := commit("feature.txt")
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: head.Name()}); err != nil {
+		t.Fatalf("failed to checkout %s: %v", defaultBranch, err)
+	}
+	mainTip := commit("main.txt")
+
+	tests := []struct {
+		name     string
+		sel      BranchSelection
+		expected []branchTip
+	}{
+		{"default", BranchSelection{Default: defaultBranch}, []branchTip{{defaultBranch, mainTip}}},
+		{"missing default falls back to HEAD", BranchSelection{Default: "trunk"}, []branchTip{{defaultBranch, mainTip}}},
+		{"list skips unknown branches", BranchSelection{Mode: database.BranchModeList, Branches: []string{"feature", "gone"}}, []branchTip{{defaultBranch, mainTip}, {"feature", feature}}},
+		{"all", BranchSelection{Default: defaultBranch, Mode: database.BranchModeAll}, []branchTip{{defaultBranch, mainTip}, {"feature", feature}}},
+	}
+
+	for _, tt := range tests {
+		tips, err := resolveBranches(repo, tt.sel)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		if len(tips) != len(tt.expected) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tips)
+			continue
+		}
+		for i := range tips {
+			if tips[i] != tt.expected[i] {
+				t.Errorf("%s: expected %v, got %v", tt.name, tt.expected, tips)
+			}
+		}
+	}
+
+	tips := []branchTip{{defaultBranch, mainTip}, {"feature", feature}}
+
+	// Every commit is walked once across branches
+	var walked []plumbing.Hash
+	err = newBranchesIter(repo, tips, make(map[plumbing.Hash]bool), false, nil).ForEach(func(c *object.Commit) error {
+		walked = append(walked, c.Hash)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(walked) != 3 {
+		t.Errorf("expected 3 commits, got %d", len(walked))
+	}
+
+	// Already indexed commits are skipped
+	walked = nil
+	err = newBranchesIter(repo, tips, map[plumbing.Hash]bool{mainTip: true, root: true}, false, nil).ForEach(func(c *object.Commit) error {
+		walked = append(walked, c.Hash)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(walked) != 1 || walked[0] != feature {
+		t.Errorf("expected only %s, got %v", feature, walked)
+	}
+
+	membership, err := branchMembership(context.Background(), repo, tips, false, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := map[string][]string{
+		defaultBranch: {mainTip.String(), root.String()},
+		"feature":     {feature.String(), root.String()},
+	}
+	for branch, hashes := range expected {
+		got := membership[branch]
+		sort.Strings(got)
+		sort.Strings(hashes)
+		if len(got) != len(hashes) || got[0] != hashes[0] || got[1] != hashes[1] {
+			t.Errorf("%s: expected %v, got %v", branch, hashes, got)
+		}
+	}
+
+	// Merge feature into the default branch: first-parent walks skip the feature commit
+	f.add("feature.txt", "feature.txt\n")
+	merge, err := wt.Commit("merge feature", &git.CommitOptions{
+		Author:  fixtureAuthor(time.Now()),
+		Parents: []plumbing.Hash{mainTip, feature},
+	})
+	if err != nil {
+		t.Fatalf("failed to commit merge: %v", err)
+	}
+
+	for _, firstParent := range []bool{false, true} {
+		iter := newBranchesIter(repo, []branchTip{{defaultBranch, merge}}, make(map[plumbing.Hash]bool), firstParent, nil)
+		got := map[plumbing.Hash][]fileChange{}
+		err := diffCommits(context.Background(), repo, iter, 2, firstParent, func(c *object.Commit, changes []fileChange) error {
+			got[c.Hash] = changes
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		_, walkedFeature := got[feature]
+		if walkedFeature == firstParent {
+			t.Errorf("firstParent=%v: feature commit walked=%v", firstParent, walkedFeature)
+		}
+		// The merge only carries the feature's changes when the feature commit itself is not walked
+		if mergeChanges := len(got[merge]); (mergeChanges == 1) != firstParent {
+			t.Errorf("firstParent=%v: merge has %d changes", firstParent, mergeChanges)
+		}
+	}
+
+	// Branches moving from old to the merge only gain the commits since, unless they were rewritten
+	updates := []struct {
+		name        string
+		old         plumbing.Hash
+		members     []plumbing.Hash
+		firstParent bool
+		expected    []string
+		ok          bool
+	}{
+		{"fast-forward", mainTip, []plumbing.Hash{mainTip, root}, false, []string{merge.String(), feature.String()}, true},
+		{"fast-forward along first parents", mainTip, []plumbing.Hash{mainTip, root}, true, []string{merge.String()}, true},
+		{"merged into", feature, []plumbing.Hash{feature, root}, false, []string{merge.String(), mainTip.String()}, true},
+		{"merged into along first parents", feature, []plumbing.Hash{feature, root}, true, nil, false},
+		{"unchanged", merge, []plumbing.Hash{merge, mainTip, feature, root}, false, []string{}, true},
+	}
+	for _, tt := range updates {
+		seen := make(map[plumbing.Hash]bool)
+		for _, h := range tt.members {
+			seen[h] = true
+		}
+		got, ok, err := addedBranchCommits(context.Background(), repo, branchTip{defaultBranch, merge}, tt.old, seen, tt.firstParent, nil)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tt.name, err)
+		}
+		sort.Strings(got)
+		sort.Strings(tt.expected)
+		if ok != tt.ok || !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s: expected %v, %v, got %v, %v", tt.name, tt.expected, tt.ok, got, ok)
+		}
+	}
+
+	// A branch reset to another line of history is rewritten
+	if _, ok, err := addedBranchCommits(context.Background(), repo, branchTip{"feature", mainTip}, feature, map[plumbing.Hash]bool{feature: true, root: true}, false, nil); err != nil || ok {
+		t.Errorf("expected rewritten branch, got ok=%v, err=%v", ok, err)
+	}
+}
@@ -0,0 +1,147 @@
+package git
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// checkpointer records how far the history phase of a full index got. Batches are flushed to the
+// staging key, which survives a failed index, so a retry skips the commits already flushed instead
+// of starting over. The walk order is deterministic for the same tips and history bounds.
+type checkpointer struct {
+	db          *database.DB
+	repoID      int64
+	fingerprint string
+	resume      *database.IndexCheckpoint // Checkpoint to continue after (nil = start over)
+	batches     int
+}
+
+// historyFingerprint identifies the history a walk of tips visits, so that a checkpoint is only
+// resumed for the same one
+func historyFingerprint(tips []branchTip, limit *historyLimit, opts ProcessOptions) string {
+	h := sha256.New()
+	for _, tip := range tips {
+		fmt.Fprintf(h, "tip %s %s\n", tip.Name, tip.Hash)
+	}
+	fmt.Fprintf(h, "first-parent %t\n", opts.FirstParent)
+	// Signatures are verified against the keys trusted when the commits are staged
+	for _, fingerprint := range opts.Keyring.Fingerprints() {
+		fmt.Fprintf(h, "key %s\n", fingerprint)
+	}
+	// Contributors are flagged as bots with the patterns configured when their commits are staged
+	for _, pattern := range opts.Bots.Patterns() {
+		fmt.Fprintf(h, "bot %s\n", pattern)
+	}
+	if opts.Since != nil {
+		fmt.Fprintf(h, "since %s\n", opts.Since.UTC().Format(time.RFC3339))
+	}
+	if limit != nil {
+		missing := make([]string, 0, len(limit.missing))
+		for hash := range limit.missing {
+			missing = append(missing, hash.String())
+		}
+		sort.Strings(missing)
+		for _, hash := range missing {
+			fmt.Fprintf(h, "shallow %s\n", hash)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// loadCheckpoint returns the checkpointer of a full index. A checkpoint left by an index of a
+// different history is ignored; the caller then clears the staged data along with it.
+func loadCheckpoint(ctx context.Context, db *database.DB, repoID int64, fingerprint string) (*checkpointer, error) {
+	cp := &checkpointer{db: db, repoID: repoID, fingerprint: fingerprint}
+
+	saved, err := db.GetIndexCheckpoint(ctx, repoID)
+	if errors.Is(err, database.ErrNotFound) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if saved.Fingerprint != fingerprint {
+		log.Printf("Discarding checkpoint of repository %d, its history changed since", repoID)
+		return cp, nil
+	}
+
+	log.Printf("Resuming index of repository %d after %d commits (%d batches)", repoID, saved.CommitsProcessed, saved.Batches)
+	cp.resume = saved
+	cp.batches = saved.Batches
+	return cp, nil
+}
+
+// resuming reports whether the index continues from a checkpoint
+func (cp *checkpointer) resuming() bool {
+	return cp != nil && cp.resume != nil
+}
+
+// skip consumes the commits of iter up to and including the checkpointed one, handing each to
+// visit, and returns how many it consumed. Without a checkpoint nothing is consumed.
+func (cp *checkpointer) skip(ctx context.Context, iter object.CommitIter, visit func(*object.Commit)) (int, error) {
+	if !cp.resuming() {
+		return 0, nil
+	}
+
+	skipped := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		default:
+		}
+
+		c, err := iter.Next()
+		if err == io.EOF {
+			// Should not happen for the same fingerprint; start over on the next attempt
+			if err := cp.clear(ctx); err != nil {
+				return 0, err
+			}
+			return 0, fmt.Errorf("checkpointed commit %s not found in history", cp.resume.LastCommit)
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		skipped++
+		visit(c)
+		if c.Hash.String() == cp.resume.LastCommit {
+			return skipped, nil
+		}
+	}
+}
+
+// save records a flushed batch ending with lastCommit; commits is the total flushed so far
+func (cp *checkpointer) save(ctx context.Context, lastCommit string, commits int) error {
+	if cp == nil {
+		return nil
+	}
+	cp.batches++
+	return cp.db.UpsertIndexCheckpoint(ctx, &database.IndexCheckpoint{
+		RepositoryID:     cp.repoID,
+		Fingerprint:      cp.fingerprint,
+		LastCommit:       lastCommit,
+		Batches:          cp.batches,
+		CommitsProcessed: commits,
+	})
+}
+
+// clear removes the checkpoint so that the next attempt starts over
+func (cp *checkpointer) clear(ctx context.Context) error {
+	if cp == nil {
+		return nil
+	}
+	return cp.db.DeleteIndexCheckpoint(ctx, cp.repoID)
+}
@@ -0,0 +1,80 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"git-repository-visualizer/internal/bots"
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/signing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestCheckpointSkip(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo := f.repo
+
+	var hashes []plumbing.Hash
+	for i := 0; i < 5; i++ {
+		hashes = append(hashes, f.commitFiles(fmt.Sprintf("file%d.txt", i)))
+	}
+	tips := []branchTip{{"master", hashes[4]}}
+
+	// The walk goes from the tip backwards; a checkpoint after the second flushed commit
+	cp := &checkpointer{resume: &database.IndexCheckpoint{LastCommit: hashes[3].String()}}
+	iter := newBranchesIter(repo, tips, make(map[plumbing.Hash]bool), false, nil)
+	var visited []plumbing.Hash
+	skipped, err := cp.skip(context.Background(), iter, func(c *object.Commit) {
+		visited = append(visited, c.Hash)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if skipped != 2 || len(visited) != 2 || visited[0] != hashes[4] || visited[1] != hashes[3] {
+		t.Errorf("expected to skip the two newest commits, skipped %d: %v", skipped, visited)
+	}
+
+	// The walk continues with the commits not flushed yet
+	next, err := iter.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.Hash != hashes[2] {
+		t.Errorf("expected walk to continue at %s, got %s", hashes[2], next.Hash)
+	}
+
+	// Without a checkpoint nothing is consumed
+	var none *checkpointer
+	if skipped, err := none.skip(context.Background(), iter, func(*object.Commit) {}); err != nil || skipped != 0 {
+		t.Errorf("expected no skip without checkpoint, got %d (%v)", skipped, err)
+	}
+
+	// The fingerprint follows the tips and history bounds
+	base := historyFingerprint(tips, nil, ProcessOptions{})
+	if historyFingerprint(tips, nil, ProcessOptions{}) != base {
+		t.Error("expected a stable fingerprint")
+	}
+	if historyFingerprint([]branchTip{{"master", hashes[3]}}, nil, ProcessOptions{}) == base {
+		t.Error("expected the fingerprint to change with the tips")
+	}
+	if historyFingerprint(tips, nil, ProcessOptions{FirstParent: true}) == base {
+		t.Error("expected the fingerprint to change with first-parent mode")
+	}
+	keyring := signing.NewKeyring([]string{"ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIEni4Dvc7V9o6QPR72zVxZsvbI191MvoQrfPI3Owzizf test@example.com"})
+	if historyFingerprint(tips, nil, ProcessOptions{Keyring: keyring}) == base {
+		t.Error("expected the fingerprint to change with the trusted keys")
+	}
+	patterns := historyFingerprint(tips, nil, ProcessOptions{Bots: bots.NewDetector([]string{"ci-*@example.com"})})
+	if patterns == base {
+		t.Error("expected the fingerprint to change with the bot patterns")
+	}
+	if historyFingerprint(tips, nil, ProcessOptions{Bots: bots.NewDetector([]string{"ci-*@example.com", "jenkins"})}) == patterns {
+		t.Error("expected the fingerprint to change with added bot patterns")
+	}
+	if historyFingerprint(tips, nil, ProcessOptions{Bots: bots.Default()}) != base {
+		t.Error("expected the built-in heuristics alone to keep the fingerprint")
+	}
+}
@@ -0,0 +1,88 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// conventionalSubject matches a Conventional Commits subject, e.g. "feat(api)!: add tokens"
+var conventionalSubject = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^()]*)\))?!?:\s*\S`)
+
+// areaSubject matches a subject prefixed with the area it changes, e.g. "net/http: fix redirects"
+var areaSubject = regexp.MustCompile(`^([\w./,-]+):\s+(\S.*)$`)
+
+// conventionalTypes maps Conventional Commits types, and common variants, to commit types
+var conventionalTypes = map[string]database.CommitType{
+	"feat":     database.CommitTypeFeature,
+	"feature":  database.CommitTypeFeature,
+	"fix":      database.CommitTypeFix,
+	"bugfix":   database.CommitTypeFix,
+	"hotfix":   database.CommitTypeFix,
+	"refactor": database.CommitTypeRefactor,
+	"perf":     database.CommitTypeRefactor,
+	"style":    database.CommitTypeRefactor,
+	"docs":     database.CommitTypeDocs,
+	"doc":      database.CommitTypeDocs,
+	"test":     database.CommitTypeTest,
+	"tests":    database.CommitTypeTest,
+	"chore":    database.CommitTypeChore,
+	"build":    database.CommitTypeChore,
+	"ci":       database.CommitTypeChore,
+	"deps":     database.CommitTypeChore,
+	"release":  database.CommitTypeChore,
+	"revert":   database.CommitTypeRevert,
+}
+
+// commitKeywords classify subjects without a Conventional Commits prefix. They are tried in
+// order, so "Add tests for the parser" is a test and "Fix typo in README" a fix.
+var commitKeywords = []struct {
+	Type    database.CommitType
+	Pattern *regexp.Regexp
+}{
+	{database.CommitTypeRevert, regexp.MustCompile(`^revert\b`)},
+	{database.CommitTypeFix, regexp.MustCompile(`\b(fix(es|ed|ing)?|bugs?|bugfix|hotfix|crash(es|ed)?|regressions?|resolve[sd]?)\b`)},
+	{database.CommitTypeTest, regexp.MustCompile(`\b(tests?|testing|specs?|coverage)\b`)},
+	{database.CommitTypeDocs, regexp.MustCompile(`\b(docs?|documentation|documents?|readme|changelog|typos?)\b`)},
+	{database.CommitTypeRefactor, regexp.MustCompile(`\b(refactor(s|ed|ing)?|clean ?ups?|cleanup|simplif(y|ies|ied)|restructur(e|es|ed)|renam(e|es|ed)|reorganiz(e|es|ed)|extract(s|ed)?|optimi[sz](e|es|ed|ation))\b`)},
+	{database.CommitTypeChore, regexp.MustCompile(`\b(bump(s|ed)?|dependenc(y|ies)|deps|upgrade[sd]?|release|version|ci|lint)\b`)},
+	{database.CommitTypeFeature, regexp.MustCompile(`\b(add(s|ed)?|implement(s|ed)?|introduc(e|es|ed)|support(s|ed)?|allow(s|ed)?|enable[sd]?|new|feature)\b`)},
+}
+
+// classifyCommit returns the intent of a commit and the area its subject names, if any.
+// Merge commits, with several parents or a "Merge " subject, are CommitTypeMerge whatever the
+// merged branch is called. Otherwise a Conventional Commits prefix decides the type, or the subject
+// is matched against keywords after removing an "area: " prefix. Unmatched commits are CommitTypeOther.
+func classifyCommit(message string, merge bool) (database.CommitType, *string) {
+	subject, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	subject = strings.TrimSpace(subject)
+
+	if merge || strings.HasPrefix(subject, "Merge ") {
+		return database.CommitTypeMerge, nil
+	}
+
+	var scope *string
+	if m := conventionalSubject.FindStringSubmatch(subject); m != nil {
+		if commitType, ok := conventionalTypes[strings.ToLower(m[1])]; ok {
+			if s := strings.TrimSpace(m[2]); s != "" {
+				scope = &s
+			}
+			return commitType, scope
+		}
+	}
+
+	if m := areaSubject.FindStringSubmatch(subject); m != nil {
+		area := m[1]
+		scope = &area
+		subject = m[2]
+	}
+
+	lower := strings.ToLower(subject)
+	for _, k := range commitKeywords {
+		if k.Pattern.MatchString(lower) {
+			return k.Type, scope
+		}
+	}
+	return database.CommitTypeOther, scope
+}
@@ -0,0 +1,51 @@
+package git
+
+import (
+	"testing"
+
+	"git-repository-visualizer/internal/database"
+)
+
+func TestClassifyCommit(t *testing.T) {
+	tests := []struct {
+		message string
+		merge   bool
+		want    database.CommitType
+		scope   string
+	}{
+		{"feat(api): add token endpoint", false, database.CommitTypeFeature, "api"},
+		{"fix: handle empty repositories\n\nCloses #12", false, database.CommitTypeFix, ""},
+		{"refactor(git)!: split processor", false, database.CommitTypeRefactor, "git"},
+		{"perf: cache blob stats", false, database.CommitTypeRefactor, ""},
+		{"docs(readme): describe setup", false, database.CommitTypeDocs, "readme"},
+		{"test: cover mailmap parsing", false, database.CommitTypeTest, ""},
+		{"ci: run vet", false, database.CommitTypeChore, ""},
+		{"revert: feat(api): add token endpoint", false, database.CommitTypeRevert, ""},
+		{"Revert \"Add token endpoint\"", false, database.CommitTypeRevert, ""},
+		{"net/http: fix redirect loop", false, database.CommitTypeFix, "net/http"},
+		{"Fixed crash when the clone is missing", false, database.CommitTypeFix, ""},
+		{"Add tests for the parser", false, database.CommitTypeTest, ""},
+		{"Update README", false, database.CommitTypeDocs, ""},
+		{"Simplify branch resolution", false, database.CommitTypeRefactor, ""},
+		{"Bump golang.org/x/net from 0.1.0 to 0.2.0", false, database.CommitTypeChore, ""},
+		{"Implement growth snapshots", false, database.CommitTypeFeature, ""},
+		{"WIP", false, database.CommitTypeOther, ""},
+		{"Merge pull request #12 from acme/fix-login", false, database.CommitTypeMerge, ""},
+		{"Merge branch 'feature/add-x'", false, database.CommitTypeMerge, ""},
+		{"fix(api): resolve conflicts with main", true, database.CommitTypeMerge, ""},
+	}
+
+	for _, tt := range tests {
+		got, scope := classifyCommit(tt.message, tt.merge)
+		if got != tt.want {
+			t.Errorf("classifyCommit(%q) type = %s, want %s", tt.message, got, tt.want)
+		}
+		gotScope := ""
+		if scope != nil {
+			gotScope = *scope
+		}
+		if gotScope != tt.scope {
+			t.Errorf("classifyCommit(%q) scope = %q, want %q", tt.message, gotScope, tt.scope)
+		}
+	}
+}
@@ -5,6 +5,21 @@ const (
 	FileBatchSize        = 1000 // Number of file inventory records to upsert at once
 	CommitBatchSize      = 100  // Number of commit records to process before flushing to DB
 	ContributorBatchSize = 1000 // Number of contributor records to upsert at once
+	OwnershipBatchSize   = 1000 // Number of blame ownership records to upsert at once
+
+	// Rename detection: minimum similarity (0-100) for an add/delete pair to count as a rename,
+	// and the maximum number of added or deleted files before only exact renames are detected
+	RenameSimilarityScore = 60
+	RenameDetectionLimit  = 1000
+
+	// Default number of goroutines computing commit diffs
+	DefaultDiffWorkers = 4
+
+	// Files larger than this are skipped by the blame pass
+	BlameMaxFileSize = 1024 * 1024 // 1MB
+
+	// Go files larger than this are not parsed for function-level changes
+	FunctionMaxFileSize = 1024 * 1024 // 1MB
 
 	// Buffer sizes for file reading
 	ScannerInitialBufferSize = 64 * 1024   // 64KB initial buffer
@@ -0,0 +1,59 @@
+package git
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+)
+
+// Credentials holds the secrets available for cloning a private repository
+type Credentials struct {
+	Token               string // OAuth access token of the repository owner (HTTP(S) URLs)
+	DeployKey           []byte // PEM encoded private key (SSH URLs)
+	DeployKeyPassphrase string
+}
+
+// authMethod picks the go-git auth method for repoPath: the deploy key for SSH URLs,
+// the service-specific token auth for HTTP(S) URLs, or nil for anonymous access.
+func authMethod(service Service, repoPath string, creds *Credentials) (transport.AuthMethod, error) {
+	if creds == nil {
+		return nil, nil
+	}
+
+	ep, err := transport.NewEndpoint(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	switch ep.Protocol {
+	case "ssh":
+		if len(creds.DeployKey) == 0 {
+			return nil, nil
+		}
+		user := ep.User
+		if user == "" {
+			user = "git"
+		}
+		keys, err := ssh.NewPublicKeys(user, creds.DeployKey, creds.DeployKeyPassphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load deploy key: %w", err)
+		}
+		return keys, nil
+	case "http", "https":
+		if creds.Token == "" {
+			return nil, nil
+		}
+		return service.TokenAuth(creds.Token), nil
+	default:
+		return nil, nil
+	}
+}
+
+// ValidateDeployKey checks that a PEM encoded private key can be used for SSH cloning
+func ValidateDeployKey(privateKey []byte, passphrase string) error {
+	if _, err := ssh.NewPublicKeys("git", privateKey, passphrase); err != nil {
+		return fmt.Errorf("invalid deploy key: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package git
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+	gitssh "github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"golang.org/x/crypto/ssh"
+)
+
+func generateDeployKey(t *testing.T) []byte {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	block, err := ssh.MarshalPrivateKey(priv, "")
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	return pem.EncodeToMemory(block)
+}
+
+func TestAuthMethod(t *testing.T) {
+	creds := &Credentials{Token: "secret", DeployKey: generateDeployKey(t)}
+
+	auth, err := authMethod(NewGitLab(), "https://gitlab.com/group/repo.git", creds)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	basic, ok := auth.(*http.BasicAuth)
+	if !ok {
+		t.Fatalf("expected basic auth, got %T", auth)
+	}
+	if basic.Username != "oauth2" || basic.Password != "secret" {
+		t.Errorf("unexpected basic auth %s:%s", basic.Username, basic.Password)
+	}
+
+	auth, err = authMethod(NewGitHub(), "git@github.com:owner/repo.git", creds)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, ok := auth.(*gitssh.PublicKeys)
+	if !ok {
+		t.Fatalf("expected public keys, got %T", auth)
+	}
+	if keys.User != "git" {
+		t.Errorf("expected ssh user git, got %s", keys.User)
+	}
+
+	// No secret for the URL's protocol means anonymous access
+	auth, err = authMethod(NewGitHub(), "https://github.com/owner/repo", &Credentials{DeployKey: creds.DeployKey})
+	if err != nil || auth != nil {
+		t.Errorf("expected anonymous access, got %v (err: %v)", auth, err)
+	}
+}
+
+func TestValidateDeployKey(t *testing.T) {
+	if err := ValidateDeployKey(generateDeployKey(t), ""); err != nil {
+		t.Errorf("expected valid key, got %v", err)
+	}
+	if err := ValidateDeployKey([]byte("not a key"), ""); err == nil {
+		t.Error("expected error for invalid key")
+	}
+}
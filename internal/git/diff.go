@@ -0,0 +1,119 @@
+package git
+
+import (
+	"context"
+	"fmt"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// diffOptions enables go-git's rename detection so moved files keep their history
+var diffOptions = &object.DiffTreeOptions{
+	DetectRenames: true,
+	RenameScore:   RenameSimilarityScore,
+	RenameLimit:   RenameDetectionLimit,
+}
+
+// fileChange is a single file touched by a commit
+type fileChange struct {
+	Path       string
+	OldPath    string // Previous path for renames and copies
+	ChangeType database.ChangeType
+	Additions  int
+	Deletions  int
+	Functions  []functionChange // Go functions and methods touched (Go files only)
+}
+
+// commitChanges diffs a commit against its first parent with rename detection.
+// Like `git log -C`, an added file is recorded as a copy when it is identical to
+// the previous content of a file modified in the same commit.
+func commitChanges(ctx context.Context, c *object.Commit) ([]fileChange, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	parentTree := &object.Tree{}
+	if c.NumParents() != 0 {
+		parent, err := c.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	changes, err := object.DiffTreeWithOptions(ctx, parentTree, tree, diffOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Previous content of files modified in place are the copy sources
+	copySources := make(map[plumbing.Hash]string)
+	for _, ch := range changes {
+		if ch.From.Name != "" && ch.From.Name == ch.To.Name {
+			copySources[ch.From.TreeEntry.Hash] = ch.From.Name
+		}
+	}
+
+	result := make([]fileChange, 0, len(changes))
+	for _, ch := range changes {
+		// Submodule pointer updates have no content to diff
+		if ch.From.TreeEntry.Mode == filemode.Submodule || ch.To.TreeEntry.Mode == filemode.Submodule {
+			continue
+		}
+
+		action, err := ch.Action()
+		if err != nil {
+			return nil, err
+		}
+
+		var fc fileChange
+		switch action {
+		case merkletrie.Insert:
+			fc.Path = ch.To.Name
+			fc.ChangeType = database.ChangeAdded
+			if src, ok := copySources[ch.To.TreeEntry.Hash]; ok {
+				fc.ChangeType = database.ChangeCopied
+				fc.OldPath = src
+			}
+		case merkletrie.Delete:
+			fc.Path = ch.From.Name
+			fc.ChangeType = database.ChangeDeleted
+		default:
+			fc.Path = ch.To.Name
+			fc.ChangeType = database.ChangeModified
+			if ch.From.Name != ch.To.Name {
+				fc.ChangeType = database.ChangeRenamed
+				fc.OldPath = ch.From.Name
+			}
+		}
+
+		// Binary files and pure renames produce no chunks and count as zero lines
+		patch, err := ch.PatchContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, stat := range patch.Stats() {
+			fc.Additions += stat.Addition
+			fc.Deletions += stat.Deletion
+		}
+
+		if isGoSource(fc.Path) && fc.Additions+fc.Deletions > 0 {
+			if fc.Functions, err = fileFunctionChanges(ch, patch); err != nil {
+				return nil, fmt.Errorf("failed to map changes of %s to functions: %w", fc.Path, err)
+			}
+		}
+
+		result = append(result, fc)
+	}
+
+	return result, nil
+}
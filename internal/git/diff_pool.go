@@ -0,0 +1,154 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// diffJob is a commit waiting to be diffed, tagged with its position in the walk
+type diffJob struct {
+	seq    int
+	commit *object.Commit
+}
+
+// diffResult holds the file changes computed for a diffJob
+type diffResult struct {
+	seq     int
+	commit  *object.Commit
+	changes []fileChange
+	err     error
+}
+
+// diffCommits computes the file changes of every commit from commitIter on a bounded pool of workers.
+// Merge commits get no changes unless diffMerges is set, in which case they are diffed against their first parent.
+// handle is called from the calling goroutine in the iterator's order, so callers can batch without locking.
+// A commit that cannot be diffed stops the walk, so no commit is stored without its changes.
+func diffCommits(ctx context.Context, repo *git.Repository, commitIter object.CommitIter, workers int, diffMerges bool, handle func(c *object.Commit, changes []fileChange) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Open storage handles up front so a failure doesn't leave goroutines behind
+	repos := make([]*git.Repository, workers)
+	for i := range repos {
+		r, err := workerRepository(repo)
+		if err != nil {
+			return fmt.Errorf("failed to open repository for diff worker: %w", err)
+		}
+		repos[i] = r
+	}
+
+	jobs := make(chan diffJob, workers*2)
+	results := make(chan diffResult, workers*2)
+
+	// 1. Producer: walk the history in order. The caller's iterator is in use until producerDone is closed.
+	var walkErr error
+	producerDone := make(chan struct{})
+	go func() {
+		defer close(producerDone)
+		defer close(jobs)
+		seq := 0
+		walkErr = commitIter.ForEach(func(c *object.Commit) error {
+			select {
+			case jobs <- diffJob{seq: seq, commit: c}:
+				seq++
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	// 2. Workers: diff commits concurrently
+	var wg sync.WaitGroup
+	for _, r := range repos {
+		wg.Add(1)
+		go func(r *git.Repository) {
+			defer wg.Done()
+			for job := range jobs {
+				result := diffResult{seq: job.seq, commit: job.commit}
+
+				if diffMerges || job.commit.NumParents() <= 1 {
+					c, err := r.CommitObject(job.commit.Hash)
+					if err == nil {
+						result.changes, err = commitChanges(ctx, c)
+					}
+					result.err = err
+				}
+
+				select {
+				case results <- result:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(r)
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 3. Consumer: hand results over in walk order
+	pending := make(map[int]diffResult)
+	next := 0
+	var handleErr error
+	for result := range results {
+		if handleErr != nil {
+			continue // Drain until the workers have stopped
+		}
+
+		pending[result.seq] = result
+		for {
+			r, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+
+			if r.err != nil {
+				handleErr = fmt.Errorf("failed to diff commit %s: %w", r.commit.Hash, r.err)
+				cancel()
+				break
+			}
+			if err := handle(r.commit, r.changes); err != nil {
+				handleErr = err
+				cancel()
+				break
+			}
+		}
+	}
+
+	// Workers only stop early once the context is done, which stops the producer too
+	<-producerDone
+
+	if handleErr != nil {
+		return handleErr
+	}
+	if walkErr != nil {
+		return fmt.Errorf("failed to iterate commits: %w", walkErr)
+	}
+	return ctx.Err()
+}
+
+// workerRepository opens a separate handle on the repository's objects for a diff worker.
+// go-git's filesystem storage keeps unsynchronized pack indexes, so it can't be shared between goroutines.
+func workerRepository(repo *git.Repository) (*git.Repository, error) {
+	s, ok := repo.Storer.(*filesystem.Storage)
+	if !ok {
+		// In-memory storage is only read during processing and safe to share
+		return repo, nil
+	}
+	return git.Open(filesystem.NewStorage(s.Filesystem(), cache.NewObjectLRUDefault()), nil)
+}
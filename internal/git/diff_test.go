@@ -0,0 +1,193 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestCommitChanges(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo := f.repo
+
+	source := strings.Repeat("func f() {}\n", 20)
+
+	commit := func(msg string, files map[string]string, removed ...string) plumbing.Hash {
+		t.Helper()
+		for name, content := range files {
+			f.add(name, content)
+		}
+		for _, name := range removed {
+			if _, err := f.wt.Remove(name); err != nil {
+				t.Fatalf("failed to remove %s: %v", name, err)
+			}
+		}
+		return f.save(msg, fixtureAuthor(time.Now()))
+	}
+
+	first := commit("initial", map[string]string{"a.go": source, "notes.txt": "todo\n"})
+	second := commit("rename", map[string]string{"b.go": source, "new.txt": "x\ny\n"}, "a.go", "notes.txt")
+	third := commit("copy", map[string]string{"b.go": source + "func g() {}\n", "c.go": source})
+
+	tests := []struct {
+		hash     plumbing.Hash
+		expected map[string]fileChange
+	}{
+		{first, map[string]fileChange{
+			"a.go":      {Path: "a.go", ChangeType: database.ChangeAdded, Additions: 20},
+			"notes.txt": {Path: "notes.txt", ChangeType: database.ChangeAdded, Additions: 1},
+		}},
+		{second, map[string]fileChange{
+			"b.go":      {Path: "b.go", OldPath: "a.go", ChangeType: database.ChangeRenamed},
+			"notes.txt": {Path: "notes.txt", ChangeType: database.ChangeDeleted, Deletions: 1},
+			"new.txt":   {Path: "new.txt", ChangeType: database.ChangeAdded, Additions: 2},
+		}},
+		{third, map[string]fileChange{
+			"b.go": {Path: "b.go", ChangeType: database.ChangeModified, Additions: 1},
+			"c.go": {Path: "c.go", OldPath: "b.go", ChangeType: database.ChangeCopied, Additions: 20},
+		}},
+	}
+
+	for _, tt := range tests {
+		c, err := repo.CommitObject(tt.hash)
+		if err != nil {
+			t.Fatalf("failed to load commit: %v", err)
+		}
+		changes, err := commitChanges(context.Background(), c)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", c.Message, err)
+		}
+		if len(changes) != len(tt.expected) {
+			t.Errorf("%s: expected %d changes, got %d: %+v", c.Message, len(tt.expected), len(changes), changes)
+			continue
+		}
+		for _, change := range changes {
+			if expected := tt.expected[change.Path]; !reflect.DeepEqual(change, expected) {
+				t.Errorf("%s: expected %+v, got %+v", c.Message, expected, change)
+			}
+		}
+	}
+}
+
+func TestDiffCommitsKeepsOrder(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo := f.repo
+
+	var expected []plumbing.Hash
+	when := time.Now().Add(-time.Hour)
+	for i := 0; i < 30; i++ {
+		hash := f.commit("file.txt", strings.Repeat("line\n", i+1), when.Add(time.Duration(i)*time.Second))
+		expected = append([]plumbing.Hash{hash}, expected...)
+	}
+
+	iter, err := repo.Log(&git.LogOptions{Order: git.LogOrderCommitterTime})
+	if err != nil {
+		t.Fatalf("failed to get log: %v", err)
+	}
+	defer iter.Close()
+
+	var got []plumbing.Hash
+	err = diffCommits(context.Background(), repo, iter, 4, false, func(c *object.Commit, changes []fileChange) error {
+		got = append(got, c.Hash)
+		if len(changes) != 1 || changes[0].Additions != 1 {
+			t.Errorf("%s: unexpected changes %+v", c.Hash, changes)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(got) != len(expected) {
+		t.Fatalf("expected %d commits, got %d", len(expected), len(got))
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("commit %d: expected %s, got %s", i, expected[i], got[i])
+		}
+	}
+}
+
+// walkTracker reports whether ForEach is still running on the wrapped iterator
+type walkTracker struct {
+	object.CommitIter
+	walking atomic.Bool
+}
+
+func (w *walkTracker) ForEach(cb func(*object.Commit) error) error {
+	w.walking.Store(true)
+	defer w.walking.Store(false)
+	return w.CommitIter.ForEach(cb)
+}
+
+func TestDiffCommitsStopsWalk(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo := f.repo
+
+	for i := 0; i < 30; i++ {
+		f.commit("file.txt", strings.Repeat("line\n", i+1), time.Now())
+	}
+
+	iter, err := repo.Log(&git.LogOptions{})
+	if err != nil {
+		t.Fatalf("failed to get log: %v", err)
+	}
+	defer iter.Close()
+
+	// The caller may close the iterator as soon as diffCommits returns
+	tracker := &walkTracker{CommitIter: iter}
+	stop := errors.New("stop")
+	err = diffCommits(context.Background(), repo, tracker, 4, false, func(c *object.Commit, changes []fileChange) error {
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected handle error, got %v", err)
+	}
+	if tracker.walking.Load() {
+		t.Error("expected the walk to be finished when diffCommits returns")
+	}
+}
+
+func TestDiffCommitsDiffError(t *testing.T) {
+	repo := newFixtureRepo(t).repo
+
+	// A commit whose tree is missing cannot be diffed
+	sig := object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	broken := &object.Commit{Author: sig, Committer: sig, Message: "broken", TreeHash: plumbing.NewHash("4b825dc642cb6eb9a060e54bf8d69288fbdecb05")}
+	obj := repo.Storer.NewEncodedObject()
+	if err := broken.Encode(obj); err != nil {
+		t.Fatalf("failed to encode commit: %v", err)
+	}
+	hash, err := repo.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatalf("failed to store commit: %v", err)
+	}
+
+	iter, err := repo.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		t.Fatalf("failed to get log: %v", err)
+	}
+	defer iter.Close()
+
+	handled := 0
+	err = diffCommits(context.Background(), repo, iter, 2, false, func(c *object.Commit, changes []fileChange) error {
+		handled++
+		return nil
+	})
+	if err == nil || !strings.Contains(err.Error(), hash.String()) {
+		t.Fatalf("expected diff error for %s, got %v", hash, err)
+	}
+	if handled != 0 {
+		t.Errorf("expected no commit to be handled, got %d", handled)
+	}
+}
@@ -0,0 +1,82 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// fixture is a repository in a temporary directory that tests commit files to
+type fixture struct {
+	t    *testing.T
+	dir  string
+	repo *git.Repository
+	wt   *git.Worktree
+}
+
+// newFixtureRepo initializes an empty fixture repository
+func newFixtureRepo(t *testing.T) *fixture {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to init fixture: %v", err)
+	}
+	wt, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to get worktree: %v", err)
+	}
+	return &fixture{t: t, dir: dir, repo: repo, wt: wt}
+}
+
+// fixtureAuthor is the author and committer of fixture commits at when
+func fixtureAuthor(when time.Time) *object.Signature {
+	return &object.Signature{Name: "Test", Email: "test@example.com", When: when}
+}
+
+// add writes a file of the working copy, creating its directory, and stages it
+func (f *fixture) add(name, content string) {
+	f.t.Helper()
+	path := filepath.Join(f.dir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		f.t.Fatalf("failed to create fixture directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		f.t.Fatalf("failed to write fixture file: %v", err)
+	}
+	if _, err := f.wt.Add(name); err != nil {
+		f.t.Fatalf("failed to stage fixture file: %v", err)
+	}
+}
+
+// save commits the staged changes, authored and committed by sig
+func (f *fixture) save(msg string, sig *object.Signature) plumbing.Hash {
+	f.t.Helper()
+	hash, err := f.wt.Commit(msg, &git.CommitOptions{Author: sig, Committer: sig})
+	if err != nil {
+		f.t.Fatalf("failed to commit fixture: %v", err)
+	}
+	return hash
+}
+
+// commit writes and commits a single file at when, with the file name as message
+func (f *fixture) commit(name, content string, when time.Time) plumbing.Hash {
+	f.t.Helper()
+	f.add(name, content)
+	return f.save(name, fixtureAuthor(when))
+}
+
+// commitFiles commits one file per name, holding its name, and returns the last commit
+func (f *fixture) commitFiles(names ...string) plumbing.Hash {
+	f.t.Helper()
+	var hash plumbing.Hash
+	for _, name := range names {
+		hash = f.commit(name, name+"\n", time.Now())
+	}
+	return hash
+}
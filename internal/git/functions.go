@@ -0,0 +1,192 @@
+package git
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"sort"
+	"strings"
+
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// functionChange is the lines of a Go function or method touched by a commit
+type functionChange struct {
+	Name      string // "Parse", "Server.Start" or "(*Server).Handle"
+	Additions int
+	Deletions int
+}
+
+// goFunction is the line range of a top-level function or method declaration, doc comment included
+type goFunction struct {
+	name       string
+	start, end int
+}
+
+// isGoSource reports whether changes to a file are tracked per function
+func isGoSource(path string) bool {
+	return strings.HasSuffix(path, ".go")
+}
+
+// goFunctions returns the functions and methods declared in Go source, in order.
+// Source that does not parse yields the declarations the parser recovered.
+func goFunctions(src []byte) []goFunction {
+	if len(src) == 0 {
+		return nil
+	}
+	fset := token.NewFileSet()
+	file, _ := parser.ParseFile(fset, "", src, parser.ParseComments|parser.SkipObjectResolution)
+	if file == nil {
+		return nil
+	}
+
+	var funcs []goFunction
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		funcs = append(funcs, goFunction{
+			name:  functionName(fn),
+			start: fset.Position(start).Line,
+			end:   fset.Position(fn.End()).Line,
+		})
+	}
+	return funcs
+}
+
+// functionName qualifies a method with its receiver type, without type parameters
+func functionName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	return receiverName(fn.Recv.List[0].Type) + "." + fn.Name.Name
+}
+
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return "(*" + receiverName(t.X) + ")"
+	case *ast.ParenExpr:
+		return receiverName(t.X)
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	default:
+		return "_"
+	}
+}
+
+// functionAt returns the name of the function declared at a line, empty outside of functions
+func functionAt(funcs []goFunction, line int) string {
+	// Declarations do not overlap, so their ends are in order
+	i := sort.Search(len(funcs), func(i int) bool { return funcs[i].end >= line })
+	if i < len(funcs) && funcs[i].start <= line {
+		return funcs[i].name
+	}
+	return ""
+}
+
+// functionChanges maps the chunks of a file diff to the functions they touch: added lines to the
+// functions of the new content, deleted lines to those of the old one. Lines outside of functions,
+// e.g. imports and type declarations, are not counted.
+func functionChanges(from, to []byte, chunks []fdiff.Chunk) []functionChange {
+	oldFuncs, newFuncs := goFunctions(from), goFunctions(to)
+	if len(oldFuncs) == 0 && len(newFuncs) == 0 {
+		return nil
+	}
+
+	var result []functionChange
+	index := make(map[string]int)
+	count := func(name string, additions, deletions int) {
+		if name == "" {
+			return
+		}
+		i, ok := index[name]
+		if !ok {
+			i = len(result)
+			index[name] = i
+			result = append(result, functionChange{Name: name})
+		}
+		result[i].Additions += additions
+		result[i].Deletions += deletions
+	}
+
+	oldLine, newLine := 1, 1
+	for _, chunk := range chunks {
+		n := countLines(chunk.Content())
+		switch chunk.Type() {
+		case fdiff.Equal:
+			oldLine += n
+			newLine += n
+		case fdiff.Add:
+			for i := 0; i < n; i++ {
+				count(functionAt(newFuncs, newLine+i), 1, 0)
+			}
+			newLine += n
+		case fdiff.Delete:
+			for i := 0; i < n; i++ {
+				count(functionAt(oldFuncs, oldLine+i), 0, 1)
+			}
+			oldLine += n
+		}
+	}
+	return result
+}
+
+// countLines counts the lines of chunk content like the diff stats do, a last line without newline included
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	n := strings.Count(s, "\n")
+	if s[len(s)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// fileFunctionChanges returns the functions touched by a change of a Go file. Files too large to
+// parse on either side are left out.
+func fileFunctionChanges(ch *object.Change, patch *object.Patch) ([]functionChange, error) {
+	from, to, err := ch.Files()
+	if err != nil {
+		return nil, err
+	}
+	if (from != nil && from.Size > FunctionMaxFileSize) || (to != nil && to.Size > FunctionMaxFileSize) {
+		return nil, nil
+	}
+
+	var oldSrc, newSrc []byte
+	if from != nil {
+		content, err := from.Contents()
+		if err != nil {
+			return nil, err
+		}
+		oldSrc = []byte(content)
+	}
+	if to != nil {
+		content, err := to.Contents()
+		if err != nil {
+			return nil, err
+		}
+		newSrc = []byte(content)
+	}
+
+	var result []functionChange
+	for _, fp := range patch.FilePatches() {
+		if fp.IsBinary() {
+			continue
+		}
+		result = append(result, functionChanges(oldSrc, newSrc, fp.Chunks())...)
+	}
+	return result, nil
+}
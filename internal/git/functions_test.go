@@ -0,0 +1,111 @@
+package git
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const functionsBefore = `package server
+
+import "net/http"
+
+// Server serves requests
+type Server struct{}
+
+// Start starts the server
+func (s *Server) Start() error {
+	return nil
+}
+
+func Parse(s string) string {
+	return s
+}
+
+func (l List[T]) Len() int {
+	return 0
+}
+`
+
+const functionsAfter = `package server
+
+import (
+	"log"
+	"net/http"
+)
+
+// Server serves requests
+type Server struct{}
+
+// Start starts the server
+func (s *Server) Start() error {
+	log.Print("starting")
+	return http.ListenAndServe(":8080", nil)
+}
+
+func (l List[T]) Len() int {
+	return 0
+}
+
+func Stop() {}
+`
+
+func TestGoFunctions(t *testing.T) {
+	funcs := goFunctions([]byte(functionsBefore))
+	expected := []goFunction{
+		{name: "(*Server).Start", start: 8, end: 11},
+		{name: "Parse", start: 13, end: 15},
+		{name: "List.Len", start: 17, end: 19},
+	}
+	if !reflect.DeepEqual(funcs, expected) {
+		t.Errorf("expected %+v, got %+v", expected, funcs)
+	}
+
+	for line, name := range map[int]string{1: "", 8: "(*Server).Start", 10: "(*Server).Start", 12: "", 14: "Parse", 19: "List.Len", 20: ""} {
+		if got := functionAt(funcs, line); got != name {
+			t.Errorf("functionAt(%d) = %q, want %q", line, got, name)
+		}
+	}
+
+	if funcs := goFunctions([]byte("not go")); len(funcs) != 0 {
+		t.Errorf("expected no functions for invalid source, got %+v", funcs)
+	}
+}
+
+func TestCommitFunctionChanges(t *testing.T) {
+	f := newFixtureRepo(t)
+
+	var c *object.Commit
+	for _, content := range []string{functionsBefore, functionsAfter} {
+		var err error
+		if c, err = f.repo.CommitObject(f.commit("server.go", content, time.Now())); err != nil {
+			t.Fatalf("failed to load commit: %v", err)
+		}
+	}
+
+	changes, err := commitChanges(context.Background(), c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 {
+		t.Fatalf("expected 1 change, got %+v", changes)
+	}
+
+	// Lines are attributed as the diff aligns them: the closing brace of Parse is matched with
+	// the one of Start, and import lines and blank lines between functions count for none
+	got := make(map[string]functionChange)
+	for _, fn := range changes[0].Functions {
+		got[fn.Name] = fn
+	}
+	expected := map[string]functionChange{
+		"(*Server).Start": {Name: "(*Server).Start", Additions: 2, Deletions: 2},
+		"Parse":           {Name: "Parse", Deletions: 2},
+		"Stop":            {Name: "Stop", Additions: 1},
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected %+v, got %+v", expected, got)
+	}
+}
@@ -0,0 +1,30 @@
+package git
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+// Generic clones from any URL go-git understands (http(s), ssh, git, file and local paths).
+// It is used as the fallback when no host-specific service is registered.
+type Generic struct{}
+
+func NewGeneric() *Generic {
+	return &Generic{}
+}
+
+func (g *Generic) Name() string {
+	return "generic"
+}
+
+// TokenAuth sends the token as password with a placeholder user, which most hosts accept
+func (g *Generic) TokenAuth(token string) transport.AuthMethod {
+	return &http.BasicAuth{Username: "git", Password: token}
+}
+
+func (g *Generic) CloneRepository(ctx context.Context, repoPath string, localPath string, opts CloneOptions) (*git.Repository, error) {
+	return cloneRepository(ctx, repoPath, localPath, opts)
+}
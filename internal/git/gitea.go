@@ -0,0 +1,28 @@
+package git
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+type Gitea struct{}
+
+func NewGitea() *Gitea {
+	return &Gitea{}
+}
+
+func (g *Gitea) Name() string {
+	return "gitea"
+}
+
+// TokenAuth passes the token as password, Gitea ignores the username for token auth
+func (g *Gitea) TokenAuth(token string) transport.AuthMethod {
+	return &http.BasicAuth{Username: "oauth2", Password: token}
+}
+
+func (g *Gitea) CloneRepository(ctx context.Context, repoPath string, localPath string, opts CloneOptions) (*git.Repository, error) {
+	return cloneRepository(ctx, repoPath, localPath, opts)
+}
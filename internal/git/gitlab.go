@@ -0,0 +1,28 @@
+package git
+
+import (
+	"context"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+type GitLab struct{}
+
+func NewGitLab() *GitLab {
+	return &GitLab{}
+}
+
+func (g *GitLab) Name() string {
+	return "gitlab"
+}
+
+// TokenAuth uses the oauth2 user GitLab expects for OAuth access tokens
+func (g *GitLab) TokenAuth(token string) transport.AuthMethod {
+	return &http.BasicAuth{Username: "oauth2", Password: token}
+}
+
+func (g *GitLab) CloneRepository(ctx context.Context, repoPath string, localPath string, opts CloneOptions) (*git.Repository, error) {
+	return cloneRepository(ctx, repoPath, localPath, opts)
+}
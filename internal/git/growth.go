@@ -0,0 +1,211 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/language"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Sampling intervals for codebase growth snapshots
+const (
+	GrowthIntervalOff   = "off"
+	GrowthIntervalWeek  = "week"
+	GrowthIntervalMonth = "month"
+)
+
+// growthSample is the commit whose tree represents a period
+type growthSample struct {
+	Period time.Time
+	Commit *object.Commit
+}
+
+// blobKey identifies a file version; the path matters since it decides the language
+type blobKey struct {
+	Path string
+	Hash plumbing.Hash
+}
+
+// blobStats is what a file version contributes to a snapshot
+type blobStats struct {
+	Language string
+	Lines    int
+	Code     int
+	Skip     bool // Binary, vendored or generated
+}
+
+// periodStart truncates t to the start (UTC) of its ISO week or month
+func periodStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == GrowthIntervalWeek {
+		// Weeks start on Monday
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// growthSamples picks the first commit of every period along the first-parent history of head.
+// Committer dates are used since they follow the order commits landed on the mainline.
+func growthSamples(ctx context.Context, head *object.Commit, interval string, limit *historyLimit) ([]growthSample, error) {
+	first := make(map[time.Time]*object.Commit)
+
+	iter := &firstParentIter{next: head, seen: make(map[plumbing.Hash]bool), limit: limit}
+	err := iter.ForEach(func(c *object.Commit) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		period := periodStart(c.Committer.When, interval)
+		if current, ok := first[period]; !ok || !c.Committer.When.After(current.Committer.When) {
+			first[period] = c
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk mainline history: %w", err)
+	}
+
+	samples := make([]growthSample, 0, len(first))
+	for period, c := range first {
+		samples = append(samples, growthSample{Period: period, Commit: c})
+	}
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Period.Before(samples[j].Period)
+	})
+	return samples, nil
+}
+
+// snapshotTree totals the files and lines per language in the tree of a sampled commit.
+// Binary, vendored and generated files are left out. Results per file version are cached
+// across samples, as most files do not change between two periods.
+func snapshotTree(ctx context.Context, repoID int64, sample growthSample, detector *language.Detector, cache map[blobKey]blobStats) ([]*database.Snapshot, error) {
+	tree, err := sample.Commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tree of %s: %w", sample.Commit.Hash, err)
+	}
+
+	totals := make(map[string]*database.Snapshot)
+	err = tree.Files().ForEach(func(f *object.File) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if !f.Mode.IsFile() {
+			return nil
+		}
+
+		key := blobKey{Path: f.Name, Hash: f.Hash}
+		stats, ok := cache[key]
+		if !ok {
+			stats = fileBlobStats(f, detector)
+			cache[key] = stats
+		}
+		if stats.Skip {
+			return nil
+		}
+
+		total, ok := totals[stats.Language]
+		if !ok {
+			total = &database.Snapshot{
+				RepositoryID: repoID,
+				PeriodStart:  sample.Period,
+				CommitHash:   sample.Commit.Hash.String(),
+				CommittedAt:  sample.Commit.Committer.When,
+				Language:     stats.Language,
+			}
+			totals[stats.Language] = total
+		}
+		total.Files++
+		total.Lines += stats.Lines
+		total.CodeLines += stats.Code
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to walk tree of %s: %w", sample.Commit.Hash, err)
+	}
+
+	snapshots := make([]*database.Snapshot, 0, len(totals))
+	for _, total := range totals {
+		snapshots = append(snapshots, total)
+	}
+	return snapshots, nil
+}
+
+// fileBlobStats classifies and counts a single file version
+func fileBlobStats(f *object.File, detector *language.Detector) blobStats {
+	if binary, _ := f.IsBinary(); binary {
+		return blobStats{Skip: true}
+	}
+	class, counts := classifyFile(f, detector)
+	return blobStats{
+		Language: class.Language,
+		Lines:    counts.Total(),
+		Code:     counts.Code,
+		Skip:     class.Vendored || class.Generated,
+	}
+}
+
+// processGrowth records growth snapshots for the periods of the default branch history that have none yet.
+// A full run replaces all snapshots, an incremental one only adds the new periods.
+func processGrowth(ctx context.Context, db *database.DB, repoID int64, head *object.Commit, limit *historyLimit, opts ProcessOptions, full bool) error {
+	interval := opts.GrowthInterval
+	if interval != GrowthIntervalWeek && interval != GrowthIntervalMonth {
+		return nil
+	}
+
+	sampled := make(map[time.Time]bool)
+	if full {
+		if err := db.DeleteSnapshotsByRepository(ctx, repoID); err != nil {
+			return fmt.Errorf("failed to clear snapshots: %w", err)
+		}
+	} else {
+		periods, err := db.GetSnapshotPeriods(ctx, repoID)
+		if err != nil {
+			return fmt.Errorf("failed to load snapshot periods: %w", err)
+		}
+		for _, p := range periods {
+			sampled[p.UTC()] = true
+		}
+	}
+
+	samples, err := growthSamples(ctx, head, interval, limit)
+	if err != nil {
+		return err
+	}
+
+	detector := opts.Languages
+	if detector == nil {
+		detector = language.Default()
+	}
+
+	cache := make(map[blobKey]blobStats)
+	recorded := 0
+	for _, sample := range samples {
+		if sampled[sample.Period] {
+			continue
+		}
+		snapshots, err := snapshotTree(ctx, repoID, sample, detector, cache)
+		if err != nil {
+			return err
+		}
+		if err := db.UpsertSnapshots(ctx, snapshots); err != nil {
+			return fmt.Errorf("failed to persist snapshots: %w", err)
+		}
+		recorded++
+	}
+
+	log.Printf("Recorded %d growth snapshots (%s) for repository %d", recorded, interval, repoID)
+	return nil
+}
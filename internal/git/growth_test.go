@@ -0,0 +1,61 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/language"
+)
+
+func TestPeriodStart(t *testing.T) {
+	// Sunday evening in UTC-5 is Monday in UTC
+	when := time.Date(2024, 3, 10, 22, 0, 0, 0, time.FixedZone("EST", -5*3600))
+
+	if got := periodStart(when, GrowthIntervalWeek); !got.Equal(time.Date(2024, 3, 11, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected week of 2024-03-11, got %s", got)
+	}
+	if got := periodStart(when, GrowthIntervalMonth); !got.Equal(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected month of 2024-03-01, got %s", got)
+	}
+}
+
+func TestGrowthSamples(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo, commit := f.repo, f.commit
+
+	jan := commit("main.go", "package main\n\n// entry point\nfunc main() {}\n", time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC))
+	commit("util.go", "package main\n", time.Date(2024, 1, 20, 12, 0, 0, 0, time.UTC))
+	mar := commit("vendor/lib/lib.go", "package lib\n", time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC))
+	head := commit("README.md", "# Fixture\n", time.Date(2024, 3, 15, 12, 0, 0, 0, time.UTC))
+
+	headCommit, err := repo.CommitObject(head)
+	if err != nil {
+		t.Fatalf("failed to get HEAD commit: %v", err)
+	}
+
+	samples, err := growthSamples(context.Background(), headCommit, GrowthIntervalMonth, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(samples) != 2 {
+		t.Fatalf("expected 2 monthly samples (February has no commits), got %d", len(samples))
+	}
+	if samples[0].Commit.Hash != jan || samples[1].Commit.Hash != mar {
+		t.Errorf("expected first commits of January and March, got %s and %s", samples[0].Commit.Hash, samples[1].Commit.Hash)
+	}
+
+	cache := make(map[blobKey]blobStats)
+	snapshots, err := snapshotTree(context.Background(), 1, samples[1], language.Default(), cache)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// March starts with main.go and util.go; the vendored file is left out
+	if len(snapshots) != 1 || snapshots[0].Language != "Go" {
+		t.Fatalf("expected a single Go snapshot, got %+v", snapshots)
+	}
+	if snapshots[0].Files != 2 || snapshots[0].Lines != 5 || snapshots[0].CodeLines != 3 {
+		t.Errorf("expected 2 files, 5 lines and 3 code lines, got %+v", snapshots[0])
+	}
+}
@@ -0,0 +1,311 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/revlist"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// Local imports repositories the worker cannot clone over the network: git bundles uploaded
+// through the API and repositories on a path of the worker host. Both are copied into a bare
+// clone at the usual clone path, without a git binary, so that they are indexed like any other
+// clone. It is registered for file URLs; clone depth and single-branch settings do not apply.
+type Local struct{}
+
+func NewLocal() *Local {
+	return &Local{}
+}
+
+func (l *Local) Name() string {
+	return "local"
+}
+
+// TokenAuth returns nil, local sources need no authentication
+func (l *Local) TokenAuth(token string) transport.AuthMethod {
+	return nil
+}
+
+// CloneRepository imports the bundle or repository at repoPath into a bare clone at localPath,
+// or the new commits when the clone already exists
+func (l *Local) CloneRepository(ctx context.Context, repoPath string, localPath string, opts CloneOptions) (*git.Repository, error) {
+	r, err := git.PlainInit(localPath, true)
+	if errors.Is(err, git.ErrRepositoryAlreadyExists) {
+		r, err = git.PlainOpen(localPath)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.FetchRepository(ctx, r, repoPath, opts); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// FetchRepository imports the commits of repoPath that the clone does not have yet
+func (l *Local) FetchRepository(ctx context.Context, repo *git.Repository, repoPath string, opts CloneOptions) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	ep, err := transport.NewEndpoint(repoPath)
+	if err != nil {
+		return fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	info, err := os.Stat(ep.Path)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", ep.Path, err)
+	}
+	if info.IsDir() {
+		return importDirectory(repo, ep.Path)
+	}
+	return importBundle(repo, ep.Path)
+}
+
+// importBundle copies the objects and branches of a git bundle into repo. The pack is skipped
+// when the clone already has every tip, e.g. on a sync without a new upload.
+func importBundle(repo *git.Repository, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	header, err := readBundleHeader(r)
+	if err != nil {
+		return err
+	}
+	for _, hash := range header.prerequisites {
+		if _, err := repo.Storer.EncodedObject(plumbing.AnyObject, hash); err != nil {
+			return fmt.Errorf("bundle requires commit %s, which is not in the clone", hash)
+		}
+	}
+
+	if !hasObjects(repo, header.refs) {
+		log.Printf("Importing bundle %s", path)
+		if err := packfile.UpdateObjectStorage(repo.Storer, r); err != nil {
+			return fmt.Errorf("failed to import bundle: %w", err)
+		}
+	}
+
+	// Bundles list the target of HEAD, not its name, so HEAD follows the first branch at it
+	var head plumbing.ReferenceName
+	for _, ref := range header.refs {
+		if ref.Name() == plumbing.HEAD {
+			for _, branch := range header.refs {
+				if branch.Name().IsBranch() && branch.Hash() == ref.Hash() {
+					head = branch.Name()
+					break
+				}
+			}
+		}
+	}
+	return setImportedReferences(repo, header.refs, head)
+}
+
+// importDirectory copies the objects and branches of the repository at path, bare or not, into repo
+func importDirectory(repo *git.Repository, path string) error {
+	src, err := git.PlainOpen(path)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", path, err)
+	}
+
+	iter, err := src.References()
+	if err != nil {
+		return fmt.Errorf("failed to list references: %w", err)
+	}
+	var refs []*plumbing.Reference
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference {
+			refs = append(refs, ref)
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list references: %w", err)
+	}
+
+	// Objects reachable from what the clone already has are not copied again
+	var wants, haves []plumbing.Hash
+	for _, ref := range refs {
+		wants = append(wants, ref.Hash())
+	}
+	existing, err := repo.References()
+	if err != nil {
+		return fmt.Errorf("failed to list references: %w", err)
+	}
+	err = existing.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && src.Storer.HasEncodedObject(ref.Hash()) == nil {
+			haves = append(haves, ref.Hash())
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list references: %w", err)
+	}
+
+	objects, err := revlist.Objects(src.Storer, wants, haves)
+	if err != nil {
+		return fmt.Errorf("failed to list objects: %w", err)
+	}
+	if len(objects) > 0 {
+		log.Printf("Importing %d objects from %s", len(objects), path)
+		pr, pw := io.Pipe()
+		go func() {
+			_, err := packfile.NewEncoder(pw, src.Storer, false).Encode(objects, 10)
+			pw.CloseWithError(err)
+		}()
+		if err := packfile.UpdateObjectStorage(repo.Storer, pr); err != nil {
+			pr.CloseWithError(err)
+			return fmt.Errorf("failed to import objects: %w", err)
+		}
+	}
+
+	var head plumbing.ReferenceName
+	if ref, err := src.Storer.Reference(plumbing.HEAD); err == nil && ref.Type() == plumbing.SymbolicReference {
+		head = ref.Target()
+	}
+	return setImportedReferences(repo, refs, head)
+}
+
+// hasObjects reports whether repo has the objects every reference points at
+func hasObjects(repo *git.Repository, refs []*plumbing.Reference) bool {
+	for _, ref := range refs {
+		if repo.Storer.HasEncodedObject(ref.Hash()) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// setImportedReferences points the branches and tags of repo at the imported ones and HEAD at
+// head, like the branch refspec of a fetch. Without a head, a HEAD that points nowhere is moved
+// to the first imported branch.
+func setImportedReferences(repo *git.Repository, refs []*plumbing.Reference, head plumbing.ReferenceName) error {
+	var first plumbing.ReferenceName
+	for _, ref := range refs {
+		if !ref.Name().IsBranch() && !ref.Name().IsTag() {
+			continue
+		}
+		if first == "" && ref.Name().IsBranch() {
+			first = ref.Name()
+		}
+		if err := repo.Storer.SetReference(plumbing.NewHashReference(ref.Name(), ref.Hash())); err != nil {
+			return fmt.Errorf("failed to set %s: %w", ref.Name(), err)
+		}
+	}
+
+	if head == "" {
+		if _, err := repo.Head(); err == nil || first == "" {
+			return nil
+		}
+		head = first
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, head)); err != nil {
+		return fmt.Errorf("failed to set HEAD: %w", err)
+	}
+	return nil
+}
+
+// Signatures of the bundle formats that can be imported
+const (
+	bundleV2Signature = "# v2 git bundle"
+	bundleV3Signature = "# v3 git bundle"
+)
+
+// bundleHeader lists what a git bundle contains. The pack follows it.
+type bundleHeader struct {
+	prerequisites []plumbing.Hash // Commits the bundle builds on without containing them
+	refs          []*plumbing.Reference
+}
+
+// readBundleHeader reads the header of a git bundle up to the pack. Version 3 bundles are only
+// accepted with SHA-1 objects and without a filter, which would leave objects out.
+func readBundleHeader(r *bufio.Reader) (*bundleHeader, error) {
+	line, err := readBundleLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if line != bundleV2Signature && line != bundleV3Signature {
+		return nil, fmt.Errorf("not a git bundle")
+	}
+
+	header := &bundleHeader{}
+	for {
+		line, err := readBundleLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@"):
+			if line != "@object-format=sha1" {
+				return nil, fmt.Errorf("unsupported bundle capability %q", line[1:])
+			}
+		case strings.HasPrefix(line, "-"):
+			// Prerequisites may carry a comment after the hash
+			hash, _, _ := strings.Cut(line[1:], " ")
+			if !plumbing.IsHash(hash) {
+				return nil, fmt.Errorf("invalid bundle prerequisite %q", line)
+			}
+			header.prerequisites = append(header.prerequisites, plumbing.NewHash(hash))
+		default:
+			hash, name, ok := strings.Cut(line, " ")
+			if !ok || !plumbing.IsHash(hash) || name == "" {
+				return nil, fmt.Errorf("invalid bundle reference %q", line)
+			}
+			header.refs = append(header.refs, plumbing.NewHashReference(plumbing.ReferenceName(name), plumbing.NewHash(hash)))
+		}
+	}
+
+	if len(header.refs) == 0 {
+		return nil, fmt.Errorf("bundle has no references")
+	}
+	return header, nil
+}
+
+// readBundleLine reads a line of a bundle header without its line feed
+func readBundleLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err == io.EOF {
+		return "", fmt.Errorf("truncated bundle header")
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read bundle: %w", err)
+	}
+	return strings.TrimSuffix(line, "\n"), nil
+}
+
+// ValidateBundle checks that r starts with the header of a git bundle that can be imported on its
+// own, i.e. that contains the whole history of its references and at least one branch
+func ValidateBundle(r io.Reader) error {
+	header, err := readBundleHeader(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+	if len(header.prerequisites) > 0 {
+		return fmt.Errorf("bundle builds on %d commits it does not contain; create it with the full history, e.g. git bundle create repo.bundle --all", len(header.prerequisites))
+	}
+	for _, ref := range header.refs {
+		if ref.Name().IsBranch() {
+			return nil
+		}
+	}
+	return fmt.Errorf("bundle has no branches")
+}
@@ -0,0 +1,134 @@
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/revlist"
+)
+
+// writeBundle writes a v2 bundle of the fixture's HEAD branch, like git bundle create --all
+func writeBundle(t *testing.T, repo *git.Repository, path string) {
+	t.Helper()
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(bundleV2Signature + "\n")
+	buf.WriteString(head.Hash().String() + " " + head.Name().String() + "\n")
+	buf.WriteString(head.Hash().String() + " HEAD\n\n")
+
+	objects, err := revlist.Objects(repo.Storer, []plumbing.Hash{head.Hash()}, nil)
+	if err != nil {
+		t.Fatalf("failed to list objects: %v", err)
+	}
+	if _, err := packfile.NewEncoder(&buf, repo.Storer, false).Encode(objects, 10); err != nil {
+		t.Fatalf("failed to encode pack: %v", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("failed to write bundle: %v", err)
+	}
+}
+
+func TestLocalImport(t *testing.T) {
+	f := newFixtureRepo(t)
+	src, repo := f.dir, f.repo
+	first := f.commitFiles("a.txt")
+
+	bundle := filepath.Join(t.TempDir(), "repo.bundle")
+	writeBundle(t, repo, bundle)
+
+	for _, source := range []string{src, bundle} {
+		local := NewLocal()
+		clonePath := filepath.Join(t.TempDir(), "clone")
+
+		clone, err := local.CloneRepository(context.Background(), "file://"+source, clonePath, CloneOptions{})
+		if err != nil {
+			t.Fatalf("%s: failed to import: %v", source, err)
+		}
+		head, err := clone.Head()
+		if err != nil {
+			t.Fatalf("%s: failed to get HEAD: %v", source, err)
+		}
+		if head.Hash() != first {
+			t.Errorf("%s: expected HEAD %s, got %s", source, first, head.Hash())
+		}
+		if _, err := clone.CommitObject(first); err != nil {
+			t.Errorf("%s: imported commit missing: %v", source, err)
+		}
+	}
+
+	// A directory is fetched incrementally into the existing clone
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	clone, err := NewLocal().CloneRepository(context.Background(), "file://"+src, clonePath, CloneOptions{})
+	if err != nil {
+		t.Fatalf("failed to import: %v", err)
+	}
+	second := f.commitFiles("b.txt")
+	if err := NewLocal().FetchRepository(context.Background(), clone, "file://"+src, CloneOptions{}); err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+	head, err := clone.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if head.Hash() != second {
+		t.Errorf("expected HEAD %s after fetch, got %s", second, head.Hash())
+	}
+}
+
+func TestReadBundleHeader(t *testing.T) {
+	hash := strings.Repeat("a", 40)
+	tests := []struct {
+		name          string
+		header        string
+		refs          int
+		prerequisites int
+		wantErr       bool
+	}{
+		{"v2", "# v2 git bundle\n" + hash + " refs/heads/main\n" + hash + " HEAD\n\n", 2, 0, false},
+		{"v3 sha1", "# v3 git bundle\n@object-format=sha1\n" + hash + " refs/heads/main\n\n", 1, 0, false},
+		{"prerequisite with comment", "# v2 git bundle\n-" + hash + " fix typo\n" + hash + " refs/heads/main\n\n", 1, 1, false},
+		{"v3 sha256", "# v3 git bundle\n@object-format=sha256\n" + hash + " refs/heads/main\n\n", 0, 0, true},
+		{"v3 filter", "# v3 git bundle\n@filter=blob:none\n" + hash + " refs/heads/main\n\n", 0, 0, true},
+		{"not a bundle", "PACK", 0, 0, true},
+		{"truncated", "# v2 git bundle\n" + hash + " refs/heads/main\n", 0, 0, true},
+		{"no references", "# v2 git bundle\n\n", 0, 0, true},
+		{"invalid reference", "# v2 git bundle\nmain\n\n", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		header, err := readBundleHeader(bufio.NewReader(strings.NewReader(tt.header)))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if len(header.refs) != tt.refs || len(header.prerequisites) != tt.prerequisites {
+			t.Errorf("%s: expected %d refs and %d prerequisites, got %d and %d",
+				tt.name, tt.refs, tt.prerequisites, len(header.refs), len(header.prerequisites))
+		}
+	}
+
+	if err := ValidateBundle(strings.NewReader("# v2 git bundle\n-" + hash + "\n" + hash + " refs/heads/main\n\n")); err == nil {
+		t.Error("expected error for a bundle with prerequisites")
+	}
+	if err := ValidateBundle(strings.NewReader("# v2 git bundle\n" + hash + " refs/tags/v1\n\n")); err == nil {
+		t.Error("expected error for a bundle without branches")
+	}
+}
@@ -0,0 +1,107 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// mailmapFile is where git reads the repository's mailmap from
+const mailmapFile = ".mailmap"
+
+// parseMailmap reads the entries of a .mailmap file, in any of the forms git accepts:
+//
+//	Proper Name <commit@email>
+//	<proper@email> <commit@email>
+//	Proper Name <proper@email> <commit@email>
+//	Proper Name <proper@email> Commit Name <commit@email>
+//
+// Comments start with '#'. Commit emails and names are lower-cased since git matches them
+// case-insensitively; a later entry for the same identity replaces an earlier one.
+func parseMailmap(content string, repoID int64) []*database.IdentityAlias {
+	var aliases []*database.IdentityAlias
+	index := make(map[[2]string]int)
+
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+
+		name1, email1, rest, ok := mailmapIdentity(line)
+		if !ok {
+			continue
+		}
+		alias := &database.IdentityAlias{RepositoryID: repoID, Source: database.AliasSourceMailmap}
+		if name1 != "" {
+			alias.CanonicalName = &name1
+		}
+
+		if name2, email2, _, ok := mailmapIdentity(rest); ok {
+			alias.Email = strings.ToLower(email2)
+			alias.CommitName = strings.ToLower(name2)
+			if email1 != "" {
+				alias.CanonicalEmail = &email1
+			}
+		} else {
+			alias.Email = strings.ToLower(email1)
+		}
+		if alias.Email == "" || (alias.CanonicalName == nil && alias.CanonicalEmail == nil) {
+			continue
+		}
+
+		key := [2]string{alias.Email, alias.CommitName}
+		if i, ok := index[key]; ok {
+			aliases[i] = alias
+			continue
+		}
+		index[key] = len(aliases)
+		aliases = append(aliases, alias)
+	}
+	return aliases
+}
+
+// mailmapIdentity splits "Name <email>" off the start of s, returning what follows it
+func mailmapIdentity(s string) (name, email, rest string, ok bool) {
+	open := strings.IndexByte(s, '<')
+	if open < 0 {
+		return "", "", "", false
+	}
+	end := strings.IndexByte(s[open:], '>')
+	if end < 0 {
+		return "", "", "", false
+	}
+	end += open
+	return strings.TrimSpace(s[:open]), strings.TrimSpace(s[open+1 : end]), s[end+1:], true
+}
+
+// processMailmap replaces the repository's .mailmap aliases with the entries of the file at HEAD
+func processMailmap(ctx context.Context, db *database.DB, repoID int64, headCommit *object.Commit) error {
+	var aliases []*database.IdentityAlias
+
+	f, err := headCommit.File(mailmapFile)
+	switch {
+	case errors.Is(err, object.ErrFileNotFound):
+	case err != nil:
+		return fmt.Errorf("failed to read %s: %w", mailmapFile, err)
+	default:
+		content, err := f.Contents()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", mailmapFile, err)
+		}
+		aliases = parseMailmap(content, repoID)
+	}
+
+	if err := db.ReplaceMailmapAliases(ctx, repoID, aliases); err != nil {
+		return fmt.Errorf("failed to persist mailmap: %w", err)
+	}
+	if len(aliases) > 0 {
+		log.Printf("Recorded %d mailmap entries for repository %d", len(aliases), repoID)
+	}
+	return nil
+}
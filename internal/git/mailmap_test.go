@@ -0,0 +1,46 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+
+	"git-repository-visualizer/internal/database"
+)
+
+func TestParseMailmap(t *testing.T) {
+	content := `# Canonical identities
+Jane Doe <jane@example.com>
+<john@example.com> <John@Laptop.local>
+Jane Doe <jane@example.com> <12345+jane@users.noreply.github.com>
+Joe Developer <joe@example.com> Joe <BUILD@example.com>   # CI commits
+Joe Developer <joe@example.com> joe <build@example.com>
+not an entry
+<only-email@example.com>
+`
+
+	str := func(s string) *string { return &s }
+	alias := func(email, commitName string, canonicalEmail, canonicalName *string) *database.IdentityAlias {
+		return &database.IdentityAlias{
+			RepositoryID:   7,
+			Source:         database.AliasSourceMailmap,
+			Email:          email,
+			CommitName:     commitName,
+			CanonicalEmail: canonicalEmail,
+			CanonicalName:  canonicalName,
+		}
+	}
+
+	got := parseMailmap(content, 7)
+	want := []*database.IdentityAlias{
+		alias("jane@example.com", "", nil, str("Jane Doe")),
+		alias("john@laptop.local", "", str("john@example.com"), nil),
+		alias("12345+jane@users.noreply.github.com", "", str("jane@example.com"), str("Jane Doe")),
+		alias("build@example.com", "joe", str("joe@example.com"), str("Joe Developer")),
+	}
+	if !reflect.DeepEqual(got, want) {
+		for _, a := range got {
+			t.Logf("%+v", *a)
+		}
+		t.Fatalf("parseMailmap() returned %d aliases, want %d", len(got), len(want))
+	}
+}
@@ -4,65 +4,266 @@ import (
 	"bufio"
 	"context"
 	"fmt"
+	"io"
 	"log"
-	"strings"
 	"time"
 
+	"git-repository-visualizer/internal/bots"
 	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/language"
+	"git-repository-visualizer/internal/signing"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 // ProcessResult contains the results of processing a repository
 type ProcessResult struct {
+	HeadCommit         string // Hash of the default branch commit that was indexed
 	CommitsProcessed   int
 	ContributorsFound  int
 	FilesTracked       int
 	ProcessingDuration time.Duration
+	CommitsPerSecond   float64 // History throughput
+	HistoryTruncated   bool    // History behind a shallow clone boundary or before Since was not indexed
+}
+
+// ProcessOptions controls the optional, more expensive parts of processing
+type ProcessOptions struct {
+	Blame          bool               // Record surviving lines per author at HEAD by blaming every file
+	DiffWorkers    int                // Goroutines computing commit diffs (0 = DefaultDiffWorkers)
+	Branches       BranchSelection    // Branches to index; the snapshot is taken from the default one
+	FirstParent    bool               // Only walk first parents; merges then carry the changes of the merged branch
+	Languages      *language.Detector // Language and file classification (nil = built-in tables)
+	Bots           *bots.Detector     // Flags automated contributors (nil = built-in heuristics)
+	Keyring        *signing.Keyring   // Keys trusted to sign commits (nil = no key is trusted)
+	GrowthInterval string             // Sample growth snapshots per GrowthIntervalWeek or GrowthIntervalMonth (other values disable it)
+	Since          *time.Time         // Commits committed before are not walked (nil = whole history)
+	Progress       ProgressFunc       // Receives the phase and position of the index (nil = not reported)
 }
 
 // ProcessRepository extracts commit data from a cloned repository and persists to database
-func ProcessRepository(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository) (*ProcessResult, error) {
+func ProcessRepository(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, opts ProcessOptions) (*ProcessResult, error) {
 	startTime := time.Now()
 
-	// 1. Get HEAD reference
-	ref, err := repo.Head()
+	// 1. Resolve the selected branches, the default branch first
+	tips, err := resolveBranches(repo, opts.Branches)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get HEAD reference: %w", err)
+		return nil, err
 	}
 
-	headCommit, err := repo.CommitObject(ref.Hash())
+	headCommit, err := repo.CommitObject(tips[0].Hash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
 	}
 
+	limit, err := newHistoryLimit(repo, opts.Since)
+	if err != nil {
+		return nil, err
+	}
+	progress := newProgressTracker(opts.Progress)
+
+	// Everything is written under the staging key and promoted at the end,
+	// so readers keep seeing the previous index in the meantime
+	stagingID := database.StagingID(repoID)
+	checkpoint, err := loadCheckpoint(ctx, db, repoID, historyFingerprint(tips, limit, opts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load checkpoint: %w", err)
+	}
+	// The history staged before a checkpoint is kept; the other phases rewrite their staged data
+	if !checkpoint.resuming() {
+		if err := db.ClearStaging(ctx, repoID); err != nil {
+			return nil, fmt.Errorf("failed to clear staged data: %w", err)
+		}
+	}
+
 	// 2. Snapshot Phase: Capture current file state (Inventory)
-	filesTracked, err := processSnapshot(ctx, db, repoID, headCommit)
+	progress.update(Progress{Phase: PhaseSnapshot})
+	filesTracked, err := processSnapshot(ctx, db, stagingID, headCommit, opts)
 	if err != nil {
 		return nil, fmt.Errorf("snapshot failed: %w", err)
 	}
 
 	// 3. History Phase: Walk Commits
-	commitsProcessed, contributorsFound, err := processHistory(ctx, db, repoID, repo, ref)
+	historyStart := time.Now()
+	commitsProcessed, contributorsFound, err := processHistory(ctx, db, stagingID, repo, tips, limit, opts, checkpoint)
 	if err != nil {
 		return nil, fmt.Errorf("history processing failed: %w", err)
 	}
 
+	// 4. Branch Phase: Record which branches reach each commit
+	progress.update(Progress{Phase: PhaseBranches})
+	if err := processBranches(ctx, db, stagingID, repo, tips, limit, opts, false); err != nil {
+		return nil, fmt.Errorf("branch processing failed: %w", err)
+	}
+
+	// 5. Growth Phase: Sample the default branch history
+	progress.update(Progress{Phase: PhaseGrowth})
+	if err := processGrowth(ctx, db, stagingID, headCommit, limit, opts, true); err != nil {
+		return nil, fmt.Errorf("growth processing failed: %w", err)
+	}
+
+	// 6. Tag Phase: Record tags and the commits each of them released
+	progress.update(Progress{Phase: PhaseTags})
+	if err := processTags(ctx, db, stagingID, repo, limit); err != nil {
+		return nil, fmt.Errorf("tag processing failed: %w", err)
+	}
+
+	// 7. Switch readers over to the new index and .mailmap aliases in one transaction, dropping the checkpoint
+	err = db.InTx(ctx, func(tx *database.DB) error {
+		if err := processMailmap(ctx, tx, repoID, headCommit); err != nil {
+			return fmt.Errorf("mailmap processing failed: %w", err)
+		}
+		if err := tx.PromoteStaging(ctx, repoID); err != nil {
+			return fmt.Errorf("failed to promote index: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	progress.update(Progress{Phase: PhaseDone, Percent: 100, CommitsProcessed: commitsProcessed, CommitsTotal: commitsProcessed})
+	return &ProcessResult{
+		HeadCommit:         tips[0].Hash.String(),
+		CommitsProcessed:   commitsProcessed,
+		ContributorsFound:  contributorsFound,
+		FilesTracked:       filesTracked,
+		ProcessingDuration: time.Since(startTime),
+		CommitsPerSecond:   throughput(commitsProcessed, time.Since(historyStart)),
+		HistoryTruncated:   limit.Truncated(),
+	}, nil
+}
+
+// ProcessRepositoryIncremental indexes only the commits added since lastCommit and refreshes the file inventory.
+// It falls back to a full ProcessRepository when lastCommit is no longer part of the default branch's history (e.g. after a force push).
+// All writes of an update are made in one transaction.
+func ProcessRepositoryIncremental(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, lastCommit string, opts ProcessOptions) (*ProcessResult, error) {
+	startTime := time.Now()
+
+	// 1. Resolve the selected branches, the default branch first
+	tips, err := resolveBranches(repo, opts.Branches)
+	if err != nil {
+		return nil, err
+	}
+
+	limit, err := newHistoryLimit(repo, opts.Since)
+	if err != nil {
+		return nil, err
+	}
+	progress := newProgressTracker(opts.Progress)
+
+	// Other branches may have moved even when the default one did not
+	if len(tips) == 1 && tips[0].Hash.String() == lastCommit {
+		log.Printf("Repository %d is already up to date at %s", repoID, lastCommit)
+		// Tags can be added to existing commits
+		if err := processTags(ctx, db, repoID, repo, limit); err != nil {
+			return nil, fmt.Errorf("tag processing failed: %w", err)
+		}
+		progress.update(Progress{Phase: PhaseDone, Percent: 100})
+		return &ProcessResult{
+			HeadCommit:         lastCommit,
+			ProcessingDuration: time.Since(startTime),
+			HistoryTruncated:   limit.Truncated(),
+		}, nil
+	}
+
+	headCommit, err := repo.CommitObject(tips[0].Hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get HEAD commit: %w", err)
+	}
+
+	// 2. Make sure the previously indexed HEAD is still part of the history
+	lastIndexed, err := repo.CommitObject(plumbing.NewHash(lastCommit))
+	if err != nil {
+		log.Printf("Last indexed commit %s not found in repository %d, running full index", lastCommit, repoID)
+		return ProcessRepository(ctx, db, repoID, repo, opts)
+	}
+
+	isAncestor, err := limit.isAncestor(lastIndexed.Hash, headCommit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare with last indexed commit: %w", err)
+	}
+	if !isAncestor {
+		log.Printf("History of repository %d was rewritten since %s, running full index", repoID, lastCommit)
+		return ProcessRepository(ctx, db, repoID, repo, opts)
+	}
+
+	// The new history is appended to the live index, so all of it is written in one transaction:
+	// readers keep seeing the previous index until the update is complete, and a failed one leaves no trace
+	var filesTracked, commitsProcessed, contributorsFound int
+	historyStart := time.Now()
+	err = db.InTx(ctx, func(tx *database.DB) error {
+		// 3. Snapshot Phase: Refresh file inventory at the new HEAD. New history is only appended,
+		// but the inventory is replaced, so it is staged and promoted like a full index.
+		progress.update(Progress{Phase: PhaseSnapshot})
+		var err error
+		filesTracked, err = processSnapshot(ctx, tx, database.StagingID(repoID), headCommit, opts)
+		if err != nil {
+			return fmt.Errorf("snapshot failed: %w", err)
+		}
+		if err := tx.PromoteSnapshot(ctx, repoID); err != nil {
+			return fmt.Errorf("failed to promote snapshot: %w", err)
+		}
+		if err := processMailmap(ctx, tx, repoID, headCommit); err != nil {
+			return fmt.Errorf("mailmap processing failed: %w", err)
+		}
+
+		// 4. History Phase: Walk only the commits that are not indexed yet
+		historyStart = time.Now()
+		commitsProcessed, contributorsFound, err = processNewHistory(ctx, tx, repoID, repo, tips, limit, opts)
+		if err != nil {
+			return fmt.Errorf("history processing failed: %w", err)
+		}
+
+		// 5. Branch Phase: Record which branches reach the new commits
+		progress.update(Progress{Phase: PhaseBranches})
+		if err := processBranches(ctx, tx, repoID, repo, tips, limit, opts, true); err != nil {
+			return fmt.Errorf("branch processing failed: %w", err)
+		}
+
+		// 6. Growth Phase: Sample the periods added since the last index
+		progress.update(Progress{Phase: PhaseGrowth})
+		if err := processGrowth(ctx, tx, repoID, headCommit, limit, opts, false); err != nil {
+			return fmt.Errorf("growth processing failed: %w", err)
+		}
+
+		// 7. Tag Phase: Record tags and the commits each of them released
+		progress.update(Progress{Phase: PhaseTags})
+		if err := processTags(ctx, tx, repoID, repo, limit); err != nil {
+			return fmt.Errorf("tag processing failed: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	progress.update(Progress{Phase: PhaseDone, Percent: 100, CommitsProcessed: commitsProcessed, CommitsTotal: commitsProcessed})
 	return &ProcessResult{
+		HeadCommit:         tips[0].Hash.String(),
 		CommitsProcessed:   commitsProcessed,
 		ContributorsFound:  contributorsFound,
 		FilesTracked:       filesTracked,
 		ProcessingDuration: time.Since(startTime),
+		CommitsPerSecond:   throughput(commitsProcessed, time.Since(historyStart)),
+		HistoryTruncated:   limit.Truncated(),
 	}, nil
 }
 
 // processSnapshot handles the inventory of files at HEAD
-func processSnapshot(ctx context.Context, db *database.DB, repoID int64, headCommit *object.Commit) (int, error) {
+func processSnapshot(ctx context.Context, db *database.DB, repoID int64, headCommit *object.Commit, opts ProcessOptions) (int, error) {
 	log.Printf("Snapshotting file inventory for repository %d...", repoID)
 
 	files := []*database.File{}
+	blamePaths := []string{}
+
+	detector := opts.Languages
+	if detector == nil {
+		detector = language.Default()
+	}
 
 	headTree, err := headCommit.Tree()
 	if err != nil {
@@ -76,36 +277,41 @@ func processSnapshot(ctx context.Context, db *database.DB, repoID int64, headCom
 		default:
 		}
 
-		// Simple language detection by extension
-		parts := strings.Split(f.Name, ".")
-		lang := "Plain Text"
-		if len(parts) > 1 {
-			lang = parts[len(parts)-1]
-		}
-
-		// Count lines
-		lines := 0
 		if !f.Mode.IsFile() {
 			return nil
 		}
 
-		r, err := f.Reader()
-		if err == nil {
-			scanner := bufio.NewScanner(r)
-			buf := make([]byte, ScannerInitialBufferSize)
-			scanner.Buffer(buf, ScannerMaxBufferSize)
-			for scanner.Scan() {
-				lines++
-			}
-			r.Close()
+		// Binary blobs (images, archives) have no meaningful lines; unreadable blobs count as text like before
+		binary, _ := f.IsBinary()
+
+		var class language.Classification
+		var counts language.LineCounts
+		if binary {
+			class = detector.Detect(f.Name, "")
+		} else {
+			class, counts = classifyFile(f, detector)
 		}
 
 		files = append(files, &database.File{
-			RepositoryID: repoID,
-			Path:         f.Name,
-			Language:     lang,
-			Lines:        lines,
+			RepositoryID:    repoID,
+			Path:            f.Name,
+			Language:        class.Language,
+			Lines:           counts.Total(),
+			CodeLines:       counts.Code,
+			CommentLines:    counts.Comment,
+			BlankLines:      counts.Blank,
+			IsBinary:        binary,
+			SizeBytes:       f.Size,
+			BlobHash:        f.Hash.String(),
+			IsVendored:      class.Vendored,
+			IsGenerated:     class.Generated,
+			IsDocumentation: class.Documentation,
 		})
+
+		// Only text files of reasonable size are worth blaming
+		if opts.Blame && !binary && f.Mode != filemode.Symlink && f.Size <= BlameMaxFileSize {
+			blamePaths = append(blamePaths, f.Name)
+		}
 		return nil
 	})
 	if err != nil {
@@ -129,68 +335,210 @@ func processSnapshot(ctx context.Context, db *database.DB, repoID int64, headCom
 	}
 	log.Printf("Persisted %d files inventory", len(files))
 
+	// Blame Pass: ownership of the surviving lines (cleared when disabled so it never goes stale)
+	if err := db.DeleteFileOwnershipByRepository(ctx, repoID); err != nil {
+		return 0, fmt.Errorf("failed to clear file ownership: %w", err)
+	}
+	if opts.Blame {
+		log.Printf("Blaming %d files for repository %d...", len(blamePaths), repoID)
+		ownership, err := blameOwnership(ctx, repoID, headCommit, blamePaths)
+		if err != nil {
+			return 0, fmt.Errorf("failed to blame files: %w", err)
+		}
+		for i := 0; i < len(ownership); i += OwnershipBatchSize {
+			end := i + OwnershipBatchSize
+			if end > len(ownership) {
+				end = len(ownership)
+			}
+			if err := db.UpsertFileOwnership(ctx, ownership[i:end]); err != nil {
+				return 0, fmt.Errorf("failed to persist file ownership: %w", err)
+			}
+		}
+		log.Printf("Persisted %d file ownership records", len(ownership))
+	}
+
 	return len(files), nil
 }
 
-// processHistory handles walking the commit log and extracting granular events
-func processHistory(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, ref *plumbing.Reference) (int, int, error) {
-	// Clear old history first
-	if err := db.DeleteContributorsByRepository(ctx, repoID); err != nil {
-		return 0, 0, fmt.Errorf("failed to clear contributors: %w", err)
+// classifyFile detects the language of a text file and splits its lines into code, comments and blanks.
+// The first line is read before detection since it may hold a shebang or a generated-code header.
+func classifyFile(f *object.File, detector *language.Detector) (language.Classification, language.LineCounts) {
+	r, err := f.Reader()
+	if err != nil {
+		return detector.Detect(f.Name, ""), language.LineCounts{}
+	}
+	defer r.Close()
+
+	var class language.Classification
+	var counter *language.LineCounter
+
+	scanner := bufio.NewScanner(r)
+	buf := make([]byte, ScannerInitialBufferSize)
+	scanner.Buffer(buf, ScannerMaxBufferSize)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if counter == nil {
+			class = detector.Detect(f.Name, line)
+			counter = language.NewLineCounter(class.Language)
+		}
+		counter.Add(line)
+	}
+
+	if counter == nil {
+		return detector.Detect(f.Name, ""), language.LineCounts{}
+	}
+	return class, counter.Counts()
+}
+
+// processHistory handles walking the commit log of the selected branches and extracting granular events
+func processHistory(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, tips []branchTip, limit *historyLimit, opts ProcessOptions, checkpoint *checkpointer) (int, int, error) {
+	// repoID is the staging key, which ProcessRepository cleared beforehand unless it resumes from checkpoint
+	total, err := countCommits(ctx, repo, tips, make(map[plumbing.Hash]bool), opts.FirstParent, limit, opts.Progress)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to count commits: %w", err)
 	}
-	if err := db.DeleteCommitsByRepository(ctx, repoID); err != nil {
-		return 0, 0, fmt.Errorf("failed to clear commits: %w", err)
+
+	commitIter := newBranchesIter(repo, tips, make(map[plumbing.Hash]bool), opts.FirstParent, limit)
+	defer commitIter.Close()
+
+	return walkHistory(ctx, db, repoID, repo, commitIter, total, opts, checkpoint)
+}
+
+// processNewHistory appends the commits reachable from the branch tips that have not been indexed yet.
+// Already indexed commits stop the walk, so only the new part of the history is visited.
+func processNewHistory(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, tips []branchTip, limit *historyLimit, opts ProcessOptions) (int, int, error) {
+	hashes, err := db.GetCommitHashesByRepository(ctx, repoID)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to load indexed commits: %w", err)
 	}
-	if err := db.DeleteCommitFilesByRepository(ctx, repoID); err != nil {
-		return 0, 0, fmt.Errorf("failed to clear commit files: %w", err)
+
+	indexed := make(map[plumbing.Hash]bool, len(hashes))
+	for _, h := range hashes {
+		indexed[plumbing.NewHash(h)] = true
 	}
 
-	commitIter, err := repo.Log(&git.LogOptions{
-		From:  ref.Hash(),
-		Order: git.LogOrderCommitterTime,
-	})
+	total, err := countCommits(ctx, repo, tips, indexed, opts.FirstParent, limit, opts.Progress)
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to get commit log: %w", err)
+		return 0, 0, fmt.Errorf("failed to count commits: %w", err)
 	}
+
+	commitIter := newBranchesIter(repo, tips, indexed, opts.FirstParent, limit)
 	defer commitIter.Close()
 
+	return walkHistory(ctx, db, repoID, repo, commitIter, total, opts, nil)
+}
+
+// processBranches records which branches reach the repository's commits. With update, a branch only
+// gets the commits it gained since the last index; new and rewritten branches are walked in full.
+func processBranches(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, tips []branchTip, limit *historyLimit, opts ProcessOptions, update bool) error {
+	recorded := make(map[string]string)
+	if update {
+		var err error
+		if recorded, err = db.GetBranchTips(ctx, repoID); err != nil {
+			return fmt.Errorf("failed to load branch tips: %w", err)
+		}
+	}
+
+	branches := make(map[string]database.BranchMembership, len(tips))
+	rebuilt := 0
+	for _, tip := range tips {
+		var hashes []string
+		ok := false
+		if old, found := recorded[tip.Name]; found {
+			members, err := db.GetBranchCommitHashes(ctx, repoID, tip.Name)
+			if err != nil {
+				return fmt.Errorf("failed to load commits of branch %s: %w", tip.Name, err)
+			}
+			seen := make(map[plumbing.Hash]bool, len(members))
+			for _, h := range members {
+				seen[plumbing.NewHash(h)] = true
+			}
+			if hashes, ok, err = addedBranchCommits(ctx, repo, tip, plumbing.NewHash(old), seen, opts.FirstParent, limit); err != nil {
+				return err
+			}
+		}
+		if !ok {
+			membership, err := branchMembership(ctx, repo, []branchTip{tip}, opts.FirstParent, limit)
+			if err != nil {
+				return err
+			}
+			hashes = membership[tip.Name]
+			rebuilt++
+		}
+		branches[tip.Name] = database.BranchMembership{Tip: tip.Hash.String(), Hashes: hashes, Rebuild: !ok}
+	}
+
+	if err := db.UpdateCommitBranches(ctx, repoID, branches); err != nil {
+		return fmt.Errorf("failed to persist commit branches: %w", err)
+	}
+	log.Printf("Recorded branch membership for %d branches (%d walked in full)", len(tips), rebuilt)
+	return nil
+}
+
+// walkHistory extracts commits, commit files and contributors from the iterator and persists them in batches.
+// Diffs are computed on opts.DiffWorkers goroutines while batches are still flushed in walk order.
+// total is the expected number of commits, used for progress reporting only. With a checkpoint every flushed
+// batch is recorded, and the commits flushed by a previous attempt are skipped.
+func walkHistory(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, commitIter object.CommitIter, total int, opts ProcessOptions, checkpoint *checkpointer) (int, int, error) {
 	// Temporary aggregators
 	contributorMap := make(map[string]*database.Contributor)
 	commitsBatch := []*database.Commit{}
 	commitFilesBatch := []*database.CommitFile{}
+	commitFunctionsBatch := []*database.CommitFunction{}
+	coAuthorsBatch := []*database.CommitCoAuthor{}
 
-	commitCount := 0
+	// Commits flushed before the checkpoint only count towards their authors
+	commitCount, err := checkpoint.skip(ctx, commitIter, func(c *object.Commit) {
+		trackContributor(repoID, c, opts.Bots, contributorMap)
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to resume from checkpoint: %w", err)
+	}
 
-	err = commitIter.ForEach(func(c *object.Commit) error {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
+	workers := opts.DiffWorkers
+	if workers < 1 {
+		workers = DefaultDiffWorkers
+	}
+
+	// Merges repeat changes already attributed to the merged commits, unless those are not walked
+	diffMerges := opts.FirstParent
+
+	progress := newProgressTracker(opts.Progress)
+	progress.resume(commitCount, total)
 
+	err = diffCommits(ctx, repo, commitIter, workers, diffMerges, func(c *object.Commit, changes []fileChange) error {
 		commitCount++
+		progress.commits(commitCount, total)
 		// Process individual commit
-		processSingleCommit(repoID, c, contributorMap, &commitsBatch, &commitFilesBatch)
+		processSingleCommit(repoID, c, changes, opts.Bots, opts.Keyring, contributorMap, &commitsBatch, &commitFilesBatch, &commitFunctionsBatch, &coAuthorsBatch)
 
 		// Batch Flushing
 		if len(commitsBatch) >= CommitBatchSize {
-			if err := flushBatches(ctx, db, commitsBatch, commitFilesBatch); err != nil {
+			if err := flushBatches(ctx, db, commitsBatch, commitFilesBatch, commitFunctionsBatch, coAuthorsBatch); err != nil {
+				return err
+			}
+			if err := checkpoint.save(ctx, commitsBatch[len(commitsBatch)-1].Hash, commitCount); err != nil {
 				return err
 			}
 			// Reset slices (keeping capacity)
 			commitsBatch = commitsBatch[:0]
 			commitFilesBatch = commitFilesBatch[:0]
+			commitFunctionsBatch = commitFunctionsBatch[:0]
+			coAuthorsBatch = coAuthorsBatch[:0]
 			log.Printf("Processed %d commits...", commitCount)
 		}
 		return nil
 	})
 	if err != nil {
-		return 0, 0, fmt.Errorf("failed to iterate commits: %w", err)
+		return 0, 0, err
 	}
 
 	// Flush remaining
 	if len(commitsBatch) > 0 {
-		if err := flushBatches(ctx, db, commitsBatch, commitFilesBatch); err != nil {
+		if err := flushBatches(ctx, db, commitsBatch, commitFilesBatch, commitFunctionsBatch, coAuthorsBatch); err != nil {
+			return 0, 0, err
+		}
+		if err := checkpoint.save(ctx, commitsBatch[len(commitsBatch)-1].Hash, commitCount); err != nil {
 			return 0, 0, err
 		}
 	}
@@ -215,11 +563,11 @@ func processHistory(ctx context.Context, db *database.DB, repoID int64, repo *gi
 	return commitCount, len(contributors), nil
 }
 
-func processSingleCommit(repoID int64, c *object.Commit, contributorMap map[string]*database.Contributor, commitsBatch *[]*database.Commit, commitFilesBatch *[]*database.CommitFile) {
+// trackContributor credits c to its author, widening the author's first and last commit dates
+func trackContributor(repoID int64, c *object.Commit, detector *bots.Detector, contributorMap map[string]*database.Contributor) {
 	commitTime := c.Author.When
 	email := c.Author.Email
 
-	// 1. Contributor Tracking
 	contributor, exists := contributorMap[email]
 	if !exists {
 		contributor = &database.Contributor{
@@ -228,6 +576,7 @@ func processSingleCommit(repoID int64, c *object.Commit, contributorMap map[stri
 			Name:          c.Author.Name,
 			FirstCommitAt: &commitTime,
 			LastCommitAt:  &commitTime,
+			IsBot:         detector.IsBot(c.Author.Name, email),
 		}
 		contributorMap[email] = contributor
 	}
@@ -237,41 +586,145 @@ func processSingleCommit(repoID int64, c *object.Commit, contributorMap map[stri
 	if contributor.LastCommitAt == nil || commitTime.After(*contributor.LastCommitAt) {
 		contributor.LastCommitAt = &commitTime
 	}
+}
+
+func processSingleCommit(repoID int64, c *object.Commit, changes []fileChange, detector *bots.Detector, keyring *signing.Keyring, contributorMap map[string]*database.Contributor, commitsBatch *[]*database.Commit, commitFilesBatch *[]*database.CommitFile, commitFunctionsBatch *[]*database.CommitFunction, coAuthorsBatch *[]*database.CommitCoAuthor) {
+	commitTime := c.Author.When
+	committerTime := c.Committer.When
+	email := c.Author.Email
+
+	// 1. Contributor Tracking
+	trackContributor(repoID, c, detector, contributorMap)
 
 	// 2. Commit Record
+	commitType, scope := classifyCommit(c.Message, c.NumParents() > 1)
 	dbCommit := &database.Commit{
 		RepositoryID: repoID,
 		Hash:         c.Hash.String(),
 		AuthorEmail:  email,
 		AuthorName:   c.Author.Name,
 		Message:      c.Message,
+		ParentHashes: parentHashes(c),
+		IsMerge:      c.NumParents() > 1,
 		CommittedAt:  commitTime,
+
+		CommitterEmail: c.Committer.Email,
+		CommitterName:  c.Committer.Name,
+		CommitterAt:    &committerTime,
+
+		Type:  commitType,
+		Scope: scope,
 	}
+	recordSignature(dbCommit, c, keyring)
 	*commitsBatch = append(*commitsBatch, dbCommit)
 
-	// 3. Diff / CommitFiles
-	// go-git Stats() builds patches and counts stats
-	stats, err := c.Stats()
-	if err == nil {
-		for _, stat := range stats {
-			cf := &database.CommitFile{
+	// 3. Co-authors credited by trailers
+	for _, ca := range parseCoAuthors(c.Message, email) {
+		*coAuthorsBatch = append(*coAuthorsBatch, &database.CommitCoAuthor{
+			RepositoryID: repoID,
+			CommitHash:   c.Hash.String(),
+			Email:        ca.Email,
+			Name:         ca.Name,
+			IsBot:        detector.IsBot(ca.Name, ca.Email),
+		})
+	}
+
+	// 4. Diff / CommitFiles (computed by the diff workers)
+	for _, change := range changes {
+		cf := &database.CommitFile{
+			RepositoryID: repoID,
+			CommitHash:   c.Hash.String(),
+			FilePath:     change.Path,
+			ChangeType:   change.ChangeType,
+			Additions:    change.Additions,
+			Deletions:    change.Deletions,
+		}
+		if change.OldPath != "" {
+			oldPath := change.OldPath
+			cf.OldPath = &oldPath
+		}
+		*commitFilesBatch = append(*commitFilesBatch, cf)
+
+		for _, fn := range change.Functions {
+			*commitFunctionsBatch = append(*commitFunctionsBatch, &database.CommitFunction{
 				RepositoryID: repoID,
 				CommitHash:   c.Hash.String(),
-				FilePath:     stat.Name,
-				Additions:    stat.Addition,
-				Deletions:    stat.Deletion,
-			}
-			*commitFilesBatch = append(*commitFilesBatch, cf)
+				FilePath:     change.Path,
+				Function:     fn.Name,
+				Additions:    fn.Additions,
+				Deletions:    fn.Deletions,
+			})
 		}
 	}
 }
 
-func flushBatches(ctx context.Context, db *database.DB, commits []*database.Commit, commitFiles []*database.CommitFile) error {
+// recordSignature records the type of a commit's signature and whether a trusted key made it
+func recordSignature(dbCommit *database.Commit, c *object.Commit, keyring *signing.Keyring) {
+	dbCommit.SignatureStatus = database.SignatureUnsigned
+	if c.PGPSignature == "" {
+		return
+	}
+
+	// The signature covers the commit object as encoded without it; a commit that cannot be
+	// encoded leaves no payload, so its signature counts as unverified
+	payload, err := signedPayload(c)
+	if err != nil {
+		log.Printf("Failed to encode commit %s for signature verification: %v", c.Hash, err)
+	}
+
+	sigType, status, fingerprint := keyring.Verify(c.PGPSignature, payload, c.Committer.When)
+	dbCommit.SignatureStatus = status
+	if sigType != "" {
+		dbCommit.SignatureType = &sigType
+	}
+	if fingerprint != "" {
+		dbCommit.SignatureKey = &fingerprint
+	}
+}
+
+// signedPayload returns the commit object encoded without its signature
+func signedPayload(c *object.Commit) ([]byte, error) {
+	obj := &plumbing.MemoryObject{}
+	if err := c.EncodeWithoutSignature(obj); err != nil {
+		return nil, err
+	}
+	r, err := obj.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// parentHashes returns the hashes of a commit's parents in order
+func parentHashes(c *object.Commit) []string {
+	hashes := make([]string, len(c.ParentHashes))
+	for i, h := range c.ParentHashes {
+		hashes[i] = h.String()
+	}
+	return hashes
+}
+
+// throughput returns the number of commits processed per second
+func throughput(commits int, d time.Duration) float64 {
+	if d <= 0 {
+		return 0
+	}
+	return float64(commits) / d.Seconds()
+}
+
+func flushBatches(ctx context.Context, db *database.DB, commits []*database.Commit, commitFiles []*database.CommitFile, commitFunctions []*database.CommitFunction, coAuthors []*database.CommitCoAuthor) error {
 	if err := db.UpsertCommits(ctx, commits); err != nil {
 		return fmt.Errorf("failed to persist batch commits: %w", err)
 	}
 	if err := db.UpsertCommitFiles(ctx, commitFiles); err != nil {
 		return fmt.Errorf("failed to persist batch commit files: %w", err)
 	}
+	if err := db.UpsertCommitFunctions(ctx, commitFunctions); err != nil {
+		return fmt.Errorf("failed to persist batch commit functions: %w", err)
+	}
+	if err := db.UpsertCommitCoAuthors(ctx, coAuthors); err != nil {
+		return fmt.Errorf("failed to persist batch commit co-authors: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,309 @@
+package git
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+// statement is a write made through a recordingPool
+type statement struct {
+	sql  string
+	args []any
+}
+
+// recordingPool records the statements written through it, batched ones included, and answers
+// queries from a mock. pgxmock cannot send batches, which the index phases write with.
+type recordingPool struct {
+	pgxmock.PgxPoolIface
+	writes []statement
+}
+
+// newRecordingDB returns a DB writing to a recordingPool and the mock its queries are answered by
+func newRecordingDB(t *testing.T) (*database.DB, *recordingPool) {
+	t.Helper()
+	mock, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock pool: %v", err)
+	}
+	t.Cleanup(mock.Close)
+	mock.MatchExpectationsInOrder(false)
+	pool := &recordingPool{PgxPoolIface: mock}
+	return database.NewTestDB(pool), pool
+}
+
+func (p *recordingPool) Begin(ctx context.Context) (pgx.Tx, error) {
+	return recordingTx{pool: p}, nil
+}
+
+func (p *recordingPool) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	p.writes = append(p.writes, statement{sql: sql, args: args})
+	return pgconn.CommandTag{}, nil
+}
+
+func (p *recordingPool) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	for _, q := range b.QueuedQueries {
+		p.writes = append(p.writes, statement{sql: q.SQL, args: q.Arguments})
+	}
+	return recordedBatch{}
+}
+
+// written returns the recorded statements whose SQL contains fragment
+func (p *recordingPool) written(fragment string) []statement {
+	var matched []statement
+	for _, s := range p.writes {
+		if strings.Contains(s.sql, fragment) {
+			matched = append(matched, s)
+		}
+	}
+	return matched
+}
+
+// committed returns the hashes of the commits written under repoID
+func (p *recordingPool) committed(repoID int64) []string {
+	var hashes []string
+	for _, s := range p.written("INSERT INTO commits (") {
+		if s.args[0] == repoID {
+			hashes = append(hashes, s.args[1].(string))
+		}
+	}
+	sort.Strings(hashes)
+	return hashes
+}
+
+// recordingTx runs a transaction's statements on its recordingPool; nested transactions share it
+type recordingTx struct {
+	pgx.Tx
+	pool *recordingPool
+}
+
+func (tx recordingTx) Begin(ctx context.Context) (pgx.Tx, error) { return tx, nil }
+func (tx recordingTx) Commit(ctx context.Context) error          { return nil }
+func (tx recordingTx) Rollback(ctx context.Context) error        { return nil }
+
+func (tx recordingTx) Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	return tx.pool.Exec(ctx, sql, args...)
+}
+
+func (tx recordingTx) Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error) {
+	return tx.pool.Query(ctx, sql, args...)
+}
+
+func (tx recordingTx) QueryRow(ctx context.Context, sql string, args ...any) pgx.Row {
+	return tx.pool.QueryRow(ctx, sql, args...)
+}
+
+func (tx recordingTx) SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults {
+	return tx.pool.SendBatch(ctx, b)
+}
+
+// recordedBatch reports every statement of a batch as executed
+type recordedBatch struct {
+	pgx.BatchResults
+}
+
+func (recordedBatch) Exec() (pgconn.CommandTag, error) { return pgconn.CommandTag{}, nil }
+func (recordedBatch) Close() error                     { return nil }
+
+// sortedHashes returns the hashes as sorted strings
+func sortedHashes(hashes ...plumbing.Hash) []string {
+	s := make([]string, len(hashes))
+	for i, h := range hashes {
+		s[i] = h.String()
+	}
+	sort.Strings(s)
+	return s
+}
+
+// incrementalOptions indexes the fixture's default branch with monthly growth snapshots
+var incrementalOptions = ProcessOptions{Branches: BranchSelection{Default: "master"}, GrowthInterval: GrowthIntervalMonth}
+
+func TestProcessRepositoryIncrementalUpToDate(t *testing.T) {
+	f := newFixtureRepo(t)
+	f.commit("a.txt", "a\n", time.Now())
+	head := f.commit("b.txt", "b\n", time.Now())
+
+	db, pool := newRecordingDB(t)
+	result, err := ProcessRepositoryIncremental(context.Background(), db, 1, f.repo, head.String(), incrementalOptions)
+	if err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if result.HeadCommit != head.String() || result.CommitsProcessed != 0 {
+		t.Errorf("expected no commits processed at %s, got %d at %s", head, result.CommitsProcessed, result.HeadCommit)
+	}
+
+	// Only tags, which can be added to indexed commits, are refreshed
+	for _, s := range pool.writes {
+		if !strings.Contains(s.sql, "tags") && !strings.Contains(s.sql, "release_commits") {
+			t.Errorf("expected nothing but tags written, got %s", strings.Join(strings.Fields(s.sql), " "))
+		}
+	}
+}
+
+func TestProcessRepositoryIncrementalFastForward(t *testing.T) {
+	f := newFixtureRepo(t)
+	january := time.Date(2024, 1, 10, 12, 0, 0, 0, time.UTC)
+	march := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	first := f.commit("a.txt", "a\n", january)
+	last := f.commit("b.txt", "b\n", january.Add(time.Hour))
+	third := f.commit("c.txt", "c\n", march)
+	fourth := f.commit("d.txt", "d\n", march.Add(time.Hour))
+
+	db, pool := newRecordingDB(t)
+	pool.ExpectQuery("SELECT hash FROM commits").WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"hash"}).AddRow(first.String()).AddRow(last.String()))
+	pool.ExpectQuery("FROM branch_tips").WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"branch", "commit_hash"}).AddRow("master", last.String()))
+	pool.ExpectQuery("FROM commit_branches").WithArgs(int64(1), "master").
+		WillReturnRows(pgxmock.NewRows([]string{"commit_hash"}).AddRow(first.String()).AddRow(last.String()))
+	pool.ExpectQuery("FROM snapshots").WithArgs(int64(1)).
+		WillReturnRows(pgxmock.NewRows([]string{"period_start"}).AddRow(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	result, err := ProcessRepositoryIncremental(context.Background(), db, 1, f.repo, last.String(), incrementalOptions)
+	if err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := pool.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+
+	if result.HeadCommit != fourth.String() || result.CommitsProcessed != 2 {
+		t.Errorf("expected 2 commits processed up to %s, got %d up to %s", fourth, result.CommitsProcessed, result.HeadCommit)
+	}
+	if got, expected := pool.committed(1), sortedHashes(third, fourth); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected only the new commits %v written, got %v", expected, got)
+	}
+	if staged := pool.committed(database.StagingID(1)); len(staged) > 0 {
+		t.Errorf("expected no history staged, got %v", staged)
+	}
+
+	// The branch keeps its membership and gains the new commits
+	for _, s := range pool.written("INSERT INTO commit_branches") {
+		got := append([]string(nil), s.args[1].([]string)...)
+		sort.Strings(got)
+		if !reflect.DeepEqual(got, sortedHashes(third, fourth)) {
+			t.Errorf("expected the new commits added to the branch, got %v", got)
+		}
+	}
+	for _, s := range pool.written("DELETE FROM commit_branches") {
+		if rebuilt := s.args[2].([]string); len(rebuilt) > 0 {
+			t.Errorf("expected no branch rebuilt, got %v", rebuilt)
+		}
+	}
+
+	// January is already sampled
+	snapshots := pool.written("INSERT INTO snapshots")
+	if len(snapshots) == 0 {
+		t.Fatal("expected growth snapshots of the new periods")
+	}
+	for _, s := range snapshots {
+		if period := s.args[1].(time.Time); period.Month() == time.January {
+			t.Errorf("expected the sampled period %s to be skipped", period)
+		}
+	}
+}
+
+func TestProcessRepositoryIncrementalForcePush(t *testing.T) {
+	f := newFixtureRepo(t)
+	first := f.commit("a.txt", "a\n", time.Now())
+	last := f.commit("b.txt", "b\n", time.Now())
+
+	// The indexed commit is dropped from the branch and replaced
+	if err := f.wt.Reset(&git.ResetOptions{Commit: first, Mode: git.HardReset}); err != nil {
+		t.Fatalf("failed to reset fixture: %v", err)
+	}
+	rewritten := f.commit("c.txt", "c\n", time.Now())
+
+	db, pool := newRecordingDB(t)
+	pool.ExpectQuery("FROM index_checkpoints").WithArgs(int64(1)).WillReturnError(pgx.ErrNoRows)
+	pool.ExpectQuery("INSERT INTO index_checkpoints").WithArgs(int64(1), pgxmock.AnyArg(), first.String(), 1, 2).
+		WillReturnRows(pgxmock.NewRows([]string{"updated_at"}).AddRow(time.Now()))
+
+	result, err := ProcessRepositoryIncremental(context.Background(), db, 1, f.repo, last.String(), incrementalOptions)
+	if err != nil {
+		t.Fatalf("failed to update: %v", err)
+	}
+	if err := pool.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+
+	if result.HeadCommit != rewritten.String() || result.CommitsProcessed != 2 {
+		t.Errorf("expected 2 commits processed up to %s, got %d up to %s", rewritten, result.CommitsProcessed, result.HeadCommit)
+	}
+	// A full index stages the whole history and promotes it over the live one
+	if got, expected := pool.committed(database.StagingID(1)), sortedHashes(first, rewritten); !reflect.DeepEqual(got, expected) {
+		t.Errorf("expected the rewritten history %v staged, got %v", expected, got)
+	}
+	if len(pool.written("UPDATE commits SET repository_id")) == 0 {
+		t.Error("expected the staged history to be promoted")
+	}
+}
+
+func TestProcessSnapshotBinaryFiles(t *testing.T) {
+	source := "package main\n\nfunc main() {}\n"
+	image := "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\n\n"
+
+	f := newFixtureRepo(t)
+	f.add("main.go", source)
+	f.add("logo.png", image)
+	head, err := f.repo.CommitObject(f.save("initial", fixtureAuthor(time.Now())))
+	if err != nil {
+		t.Fatalf("failed to get commit: %v", err)
+	}
+
+	db, pool := newRecordingDB(t)
+	tracked, err := processSnapshot(context.Background(), db, 1, head, ProcessOptions{})
+	if err != nil {
+		t.Fatalf("failed to snapshot: %v", err)
+	}
+	if tracked != 2 {
+		t.Fatalf("expected 2 files tracked, got %d", tracked)
+	}
+
+	tests := map[string]struct {
+		content string
+		binary  bool
+		lines   int
+	}{
+		"main.go":  {source, false, 3},
+		"logo.png": {image, true, 0},
+	}
+	for _, s := range pool.written("INSERT INTO files") {
+		path := s.args[1].(string)
+		expected, ok := tests[path]
+		if !ok {
+			t.Errorf("unexpected file %s", path)
+			continue
+		}
+		delete(tests, path)
+
+		if binary := s.args[7].(bool); binary != expected.binary {
+			t.Errorf("%s: expected binary %v, got %v", path, expected.binary, binary)
+		}
+		if lines := s.args[3].(int); lines != expected.lines {
+			t.Errorf("%s: expected %d lines, got %d", path, expected.lines, lines)
+		}
+		// Size and hash are those of the git blob
+		if size := s.args[8].(int64); size != int64(len(expected.content)) {
+			t.Errorf("%s: expected size %d, got %d", path, len(expected.content), size)
+		}
+		blob := plumbing.ComputeHash(plumbing.BlobObject, []byte(expected.content))
+		if hash := s.args[9].(string); hash != blob.String() {
+			t.Errorf("%s: expected blob hash %s, got %s", path, blob, hash)
+		}
+	}
+	for path := range tests {
+		t.Errorf("expected %s in the inventory", path)
+	}
+}
@@ -0,0 +1,151 @@
+package git
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"maps"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Indexing phases reported through ProcessOptions.Progress, in order
+const (
+	PhaseCloning  = "cloning"
+	PhaseSnapshot = "snapshot"
+	PhaseHistory  = "history"
+	PhaseBranches = "branches"
+	PhaseGrowth   = "growth"
+	PhaseTags     = "tags"
+	PhaseDone     = "done"
+)
+
+// ProgressInterval is the minimum time between two updates within a phase
+const ProgressInterval = time.Second
+
+// Progress is the position of a running index
+type Progress struct {
+	Phase            string
+	Percent          float64 // Of the current phase (0-100)
+	CommitsProcessed int
+	CommitsTotal     int
+	ETA              *time.Duration // Remaining time of the history phase (nil in other phases)
+}
+
+// ProgressFunc receives progress updates. Phase changes are always reported, updates
+// within a phase at most every ProgressInterval.
+type ProgressFunc func(Progress)
+
+// progressTracker throttles the updates sent to a ProgressFunc, which may be nil
+type progressTracker struct {
+	report  ProgressFunc
+	phase   string
+	last    time.Time
+	started time.Time // Start of the phase, for the ETA
+	resumed int       // Commits done by a previous attempt, which do not count towards the throughput
+}
+
+func newProgressTracker(report ProgressFunc) *progressTracker {
+	return &progressTracker{report: report}
+}
+
+// update reports p, unless it is in the current phase and the previous update was too recent
+func (t *progressTracker) update(p Progress) {
+	if t == nil || t.report == nil {
+		return
+	}
+	now := time.Now()
+	if p.Phase != t.phase {
+		t.phase = p.Phase
+		t.started = now
+	} else if now.Sub(t.last) < ProgressInterval {
+		return
+	}
+	t.last = now
+	t.report(p)
+}
+
+// commits reports the history phase, estimating the remaining time from the throughput so far
+func (t *progressTracker) commits(done, total int) {
+	p := Progress{Phase: PhaseHistory, CommitsProcessed: done, CommitsTotal: total}
+	if total > 0 {
+		p.Percent = float64(done) * 100 / float64(total)
+	}
+	if t != nil && t.phase == PhaseHistory && done > t.resumed && total >= done {
+		elapsed := time.Since(t.started)
+		eta := time.Duration(float64(elapsed) / float64(done-t.resumed) * float64(total-done)).Round(time.Second)
+		p.ETA = &eta
+	}
+	t.update(p)
+}
+
+// resume starts the history phase at the commits done by a previous attempt
+func (t *progressTracker) resume(done, total int) {
+	if t != nil {
+		t.resumed = done
+	}
+	t.commits(done, total)
+}
+
+// sidebandProgress matches the progress lines git servers send while packing, e.g.
+// "Counting objects:  45% (450/1000)"
+var sidebandProgress = regexp.MustCompile(`(\d+)% \(\d+/\d+\)`)
+
+// cloneProgress is an io.Writer for go-git's Progress option that reports the clone phase.
+// Lines are separated by carriage returns and may be split across writes.
+type cloneProgress struct {
+	tracker *progressTracker
+	pending []byte
+}
+
+func (w *cloneProgress) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexAny(w.pending, "\r\n")
+		if i < 0 {
+			break
+		}
+		line := w.pending[:i]
+		w.pending = w.pending[i+1:]
+		if m := sidebandProgress.FindSubmatch(line); m != nil {
+			percent, _ := strconv.ParseFloat(string(m[1]), 64)
+			w.tracker.update(Progress{Phase: PhaseCloning, Percent: percent})
+		}
+	}
+	return len(p), nil
+}
+
+// progressWriter returns the writer for go-git's Progress option, reporting the clone phase when report is set
+func progressWriter(report ProgressFunc) io.Writer {
+	if report == nil {
+		return os.Stdout
+	}
+	tracker := newProgressTracker(report)
+	tracker.update(Progress{Phase: PhaseCloning})
+	return io.MultiWriter(os.Stdout, &cloneProgress{tracker: tracker})
+}
+
+// countCommits returns the number of commits a walk of tips yields, so the history phase can report
+// a percentage. seen is not modified. The count is skipped (0) when nobody listens for progress.
+func countCommits(ctx context.Context, repo *git.Repository, tips []branchTip, seen map[plumbing.Hash]bool, firstParent bool, limit *historyLimit, report ProgressFunc) (int, error) {
+	if report == nil {
+		return 0, nil
+	}
+	count := 0
+	err := newBranchesIter(repo, tips, maps.Clone(seen), firstParent, limit).ForEach(func(c *object.Commit) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		count++
+		return nil
+	})
+	return count, err
+}
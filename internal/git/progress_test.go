@@ -0,0 +1,74 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCloneProgress(t *testing.T) {
+	var updates []Progress
+	w := &cloneProgress{tracker: newProgressTracker(func(p Progress) {
+		updates = append(updates, p)
+	})}
+
+	// Lines split across writes; only the first update of the phase gets past the throttle
+	w.Write([]byte("Enumerating objects: 10, done.\nCounting objects:  4"))
+	w.Write([]byte("5% (45/100)\rCounting objects: 100% (100/100), done.\n"))
+
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 throttled update, got %v", updates)
+	}
+	if updates[0].Phase != PhaseCloning || updates[0].Percent != 45 {
+		t.Errorf("expected cloning at 45%%, got %+v", updates[0])
+	}
+}
+
+func TestProgressTrackerCommits(t *testing.T) {
+	var updates []Progress
+	tracker := newProgressTracker(func(p Progress) {
+		updates = append(updates, p)
+	})
+
+	tracker.commits(0, 200)
+	tracker.started = time.Now().Add(-10 * time.Second)
+	tracker.last = time.Time{}
+	tracker.commits(50, 200)
+
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %v", updates)
+	}
+	last := updates[1]
+	if last.Percent != 25 || last.CommitsProcessed != 50 || last.CommitsTotal != 200 {
+		t.Errorf("expected 50 of 200 commits (25%%), got %+v", last)
+	}
+	// 50 commits in 10s leaves 150 commits for about 30s
+	if last.ETA == nil || *last.ETA != 30*time.Second {
+		t.Errorf("expected an ETA of 30s, got %v", last.ETA)
+	}
+
+	// Phase changes are never throttled
+	tracker.update(Progress{Phase: PhaseBranches})
+	if len(updates) != 3 || updates[2].Phase != PhaseBranches {
+		t.Errorf("expected the branches phase to be reported, got %v", updates)
+	}
+
+	// A resumed walk only measures the commits of this attempt: 20 commits in 10s leaves 80 for about 40s
+	updates = nil
+	tracker = newProgressTracker(func(p Progress) {
+		updates = append(updates, p)
+	})
+	tracker.resume(100, 200)
+	tracker.started = time.Now().Add(-10 * time.Second)
+	tracker.last = time.Time{}
+	tracker.commits(120, 200)
+
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %v", updates)
+	}
+	if updates[0].ETA != nil {
+		t.Errorf("expected no ETA before a commit of this attempt, got %v", *updates[0].ETA)
+	}
+	if last := updates[1]; last.ETA == nil || *last.ETA != 40*time.Second {
+		t.Errorf("expected an ETA of 40s, got %v", last.ETA)
+	}
+}
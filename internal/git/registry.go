@@ -0,0 +1,106 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"git-repository-visualizer/internal/config"
+
+	"github.com/go-git/go-git/v5/plumbing/transport"
+)
+
+// Registry selects the Service responsible for a repository URL.
+// Services are matched by host first, then by URL scheme, and finally the fallback is used.
+type Registry struct {
+	mu       sync.RWMutex
+	hosts    map[string]Service
+	schemes  map[string]Service
+	fallback Service
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		hosts:    make(map[string]Service),
+		schemes:  make(map[string]Service),
+		fallback: NewGeneric(),
+	}
+}
+
+// RegisterHost routes every repository on the given host (e.g. "gitlab.example.com") to the service
+func (r *Registry) RegisterHost(host string, s Service) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hosts[strings.ToLower(host)] = s
+}
+
+// RegisterScheme routes every repository with the given URL scheme (e.g. "file", "ssh") to the service
+func (r *Registry) RegisterScheme(scheme string, s Service) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemes[strings.ToLower(scheme)] = s
+}
+
+// SetFallback sets the service used when neither host nor scheme match. A nil fallback rejects unknown URLs.
+func (r *Registry) SetFallback(s Service) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = s
+}
+
+func (r *Registry) Get(repoPath string) (Service, error) {
+	ep, err := transport.NewEndpoint(repoPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid repository URL: %w", err)
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if s, ok := r.hosts[strings.ToLower(ep.Host)]; ok && ep.Host != "" {
+		return s, nil
+	}
+	if s, ok := r.schemes[strings.ToLower(ep.Protocol)]; ok {
+		return s, nil
+	}
+	if r.fallback != nil {
+		return r.fallback, nil
+	}
+	return nil, fmt.Errorf("unsupported repository type: %s", repoPath)
+}
+
+// InitializeServices registers the well-known public hosts and any configured self-hosted domains
+func (r *Registry) InitializeServices(cfg config.GitConfig) error {
+	r.RegisterHost("github.com", NewGitHub())
+	r.RegisterHost("bitbucket.org", NewBitBucket())
+	r.RegisterHost("gitlab.com", NewGitLab())
+	r.RegisterHost("gitea.com", NewGitea())
+	r.RegisterScheme("file", NewLocal())
+
+	for host, kind := range cfg.Hosts {
+		s, err := newService(kind)
+		if err != nil {
+			return fmt.Errorf("invalid git host %s: %w", host, err)
+		}
+		r.RegisterHost(host, s)
+	}
+	return nil
+}
+
+// newService creates a service by its name as used in configuration
+func newService(kind string) (Service, error) {
+	switch strings.ToLower(kind) {
+	case "github":
+		return NewGitHub(), nil
+	case "bitbucket":
+		return NewBitBucket(), nil
+	case "gitlab":
+		return NewGitLab(), nil
+	case "gitea":
+		return NewGitea(), nil
+	case "generic":
+		return NewGeneric(), nil
+	default:
+		return nil, fmt.Errorf("unknown service type %q", kind)
+	}
+}
@@ -0,0 +1,92 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/config"
+)
+
+func TestRegistryGet(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.InitializeServices(config.GitConfig{
+		Hosts: map[string]string{"git.example.com": "gitlab"},
+	})
+	if err != nil {
+		t.Fatalf("failed to initialize services: %v", err)
+	}
+
+	tests := []struct {
+		url      string
+		expected string
+	}{
+		{"https://github.com/owner/repo", "github"},
+		{"git@github.com:owner/repo.git", "github"},
+		{"https://bitbucket.org/owner/repo.git", "bitbucket"},
+		{"https://gitlab.com/group/sub/repo", "gitlab"},
+		{"https://git.example.com/team/repo.git", "gitlab"},
+		{"ssh://git@git.example.com:2222/team/repo.git", "gitlab"},
+		{"https://codeberg.org/owner/repo", "generic"},
+		{"file:///srv/git/repo.git", "local"},
+		{"FILE:///srv/git/repo.git", "local"},
+		{"HTTPS://GitHub.com/owner/repo", "github"},
+		{"/srv/git/repo.git", "local"},
+	}
+
+	for _, tt := range tests {
+		s, err := registry.Get(tt.url)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.url, err)
+			continue
+		}
+		if s.Name() != tt.expected {
+			t.Errorf("%s: expected service %s, got %s", tt.url, tt.expected, s.Name())
+		}
+	}
+}
+
+func TestRegistryGetWithoutFallback(t *testing.T) {
+	registry := NewRegistry()
+	registry.SetFallback(nil)
+	registry.RegisterHost("github.com", NewGitHub())
+
+	if _, err := registry.Get("https://gitlab.com/group/repo"); err == nil {
+		t.Error("expected error for unsupported host")
+	}
+}
+
+func TestInitializeServicesUnknownType(t *testing.T) {
+	registry := NewRegistry()
+	err := registry.InitializeServices(config.GitConfig{
+		Hosts: map[string]string{"git.example.com": "svn"},
+	})
+	if err == nil {
+		t.Error("expected error for unknown service type")
+	}
+}
+
+func TestCloneLocalFixture(t *testing.T) {
+	f := newFixtureRepo(t)
+	src := f.dir
+	hash := f.commit("main.go", "package main\n", time.Now())
+
+	service, err := NewRegistry().Get("file://" + src)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clone, err := service.CloneRepository(context.Background(), "file://"+src, filepath.Join(t.TempDir(), "clone"), CloneOptions{})
+	if err != nil {
+		t.Fatalf("failed to clone fixture: %v", err)
+	}
+
+	head, err := clone.Head()
+	if err != nil {
+		t.Fatalf("failed to get HEAD: %v", err)
+	}
+	if head.Hash() != hash {
+		t.Errorf("expected HEAD %s, got %s", hash, head.Hash())
+	}
+}
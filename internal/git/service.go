@@ -2,51 +2,196 @@ package git
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"strings"
+	"os"
 
 	"git-repository-visualizer/internal/database"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/transport"
 )
 
+// branchRefSpec fetches remote branches straight into local branches. Clones are bare,
+// so HEAD points at refs/heads/<branch> and has to move forward on every fetch.
+const branchRefSpec = config.RefSpec("+refs/heads/*:refs/heads/*")
+
+// tagRefSpec fetches every tag, moving tags that were re-pointed on the remote
+const tagRefSpec = config.RefSpec("+refs/tags/*:refs/tags/*")
+
+// Service clones repositories from a git host
 type Service interface {
-	CloneRepository(ctx context.Context, repoPath string, localPath string) (*git.Repository, error)
+	Name() string
+	TokenAuth(token string) transport.AuthMethod
+	CloneRepository(ctx context.Context, repoPath string, localPath string, opts CloneOptions) (*git.Repository, error)
+}
+
+// Fetcher is implemented by services that bring an existing clone up to date themselves,
+// rather than through a fetch from the remote
+type Fetcher interface {
+	FetchRepository(ctx context.Context, repo *git.Repository, repoPath string, opts CloneOptions) error
+}
+
+// CloneOptions configures how a Service clones and fetches a repository
+type CloneOptions struct {
+	Auth         transport.AuthMethod // nil for anonymous access
+	Depth        int                  // Commits fetched from each branch tip (0 = full history)
+	SingleBranch bool                 // Only clone Branch
+	Branch       string               // Branch of a single-branch clone (empty = the remote's default branch)
+	Progress     ProgressFunc         // Receives the clone phase (nil = not reported)
+}
+
+// cloneRepository creates a bare clone of repoPath at localPath, or fetches updates when the clone already exists
+func cloneRepository(ctx context.Context, repoPath string, localPath string, opts CloneOptions) (*git.Repository, error) {
+	// Use bare clone (isBare: true) to only clone .git directory without working tree
+	// This saves disk space and is faster since we only need git history for analysis
+	cloneOpts := &git.CloneOptions{
+		URL:          repoPath,
+		Auth:         opts.Auth,
+		Depth:        opts.Depth,
+		SingleBranch: opts.SingleBranch,
+		Progress:     progressWriter(opts.Progress),
+	}
+	if opts.SingleBranch && opts.Branch != "" {
+		cloneOpts.ReferenceName = plumbing.NewBranchReferenceName(opts.Branch)
+	}
+	r, err := git.PlainCloneContext(ctx, localPath, true, cloneOpts)
+	if err != nil {
+		// If repository already exists, open and fetch updates
+		if errors.Is(err, git.ErrRepositoryAlreadyExists) {
+			r, err = git.PlainOpen(localPath)
+			if err != nil {
+				return nil, err
+			}
+			// go-git cannot unshallow a clone, so full history needs a fresh one
+			if shallow, err := r.Storer.Shallow(); err == nil && len(shallow) > 0 && opts.Depth == 0 {
+				log.Printf("Clone at %s is shallow, cloning again with full history", localPath)
+				if err := os.RemoveAll(localPath); err != nil {
+					return nil, fmt.Errorf("failed to remove shallow clone: %w", err)
+				}
+				return cloneRepository(ctx, repoPath, localPath, opts)
+			}
+			log.Printf("Repository exists, fetching updates...")
+			if err := fetchRepository(ctx, r, opts); err != nil {
+				return nil, err
+			}
+			return r, nil
+		}
+		return nil, err
+	}
+	return r, nil
 }
 
-func getService(repoPath string) Service {
-	if strings.HasPrefix(repoPath, "https://github.com/") {
-		return NewGitHub()
+// fetchRepository pulls new commits from the remote into an existing bare clone.
+// Single-branch clones only fetch their branch, or the one HEAD points at, and the tags of its history.
+func fetchRepository(ctx context.Context, repo *git.Repository, opts CloneOptions) error {
+	refSpecs := []config.RefSpec{branchRefSpec, tagRefSpec}
+	if opts.SingleBranch {
+		branch := plumbing.NewBranchReferenceName(opts.Branch)
+		if opts.Branch == "" {
+			head, err := repo.Storer.Reference(plumbing.HEAD)
+			if err != nil {
+				return fmt.Errorf("failed to read HEAD: %w", err)
+			}
+			branch = head.Target()
+		}
+		refSpecs = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", branch, branch))}
 	}
-	if strings.HasPrefix(repoPath, "https://bitbucket.org/") {
-		return NewBitBucket()
+
+	err := repo.FetchContext(ctx, &git.FetchOptions{
+		RefSpecs: refSpecs,
+		Auth:     opts.Auth,
+		Depth:    opts.Depth,
+		Progress: progressWriter(opts.Progress),
+	})
+	// "already up-to-date" is not an error for our use case
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return err
 	}
 	return nil
 }
 
-// IndexRepository clones a repository and processes its commit history
-func IndexRepository(ctx context.Context, db *database.DB, repoID int64, repoPath string, localPath string) error {
+// IndexRepository clones a repository and processes its commit history.
+// creds may be nil for public repositories; clone.Auth is derived from them.
+func IndexRepository(ctx context.Context, db *database.DB, services *Registry, repoID int64, repoPath string, localPath string, creds *Credentials, clone CloneOptions, opts ProcessOptions) (*ProcessResult, error) {
 	// Clone repository from remote and store to local path designated
-	service := getService(repoPath)
-	if service == nil {
-		return fmt.Errorf("unsupported repository type")
+	service, err := services.Get(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := authMethod(service, repoPath, creds)
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("Cloning repository %s to %s", repoPath, localPath)
-	repo, err := service.CloneRepository(ctx, repoPath, localPath)
+	log.Printf("Cloning repository %s to %s via %s", repoPath, localPath, service.Name())
+	clone.Auth = auth
+	clone.Progress = opts.Progress
+	repo, err := service.CloneRepository(ctx, repoPath, localPath, clone)
 	if err != nil {
-		return fmt.Errorf("failed to clone repository: %w", err)
+		return nil, fmt.Errorf("failed to clone repository: %w", err)
 	}
 
 	log.Printf("Processing repository commits...")
-	result, err := ProcessRepository(ctx, db, repoID, repo)
+	result, err := ProcessRepository(ctx, db, repoID, repo, opts)
 	if err != nil {
-		return fmt.Errorf("failed to process repository: %w", err)
+		return nil, fmt.Errorf("failed to process repository: %w", err)
 	}
 
-	log.Printf("Repository indexed: %d commits, %d contributors in %v",
-		result.CommitsProcessed, result.ContributorsFound, result.ProcessingDuration)
+	log.Printf("Repository indexed: %d commits, %d contributors in %v (%.1f commits/sec)",
+		result.CommitsProcessed, result.ContributorsFound, result.ProcessingDuration, result.CommitsPerSecond)
 
-	return nil
+	return result, nil
+}
+
+// UpdateRepository fetches new commits into the existing clone and indexes only the history added since lastCommit.
+// When the clone is missing, e.g. after it was evicted to free disk space, the repository is cloned again.
+func UpdateRepository(ctx context.Context, db *database.DB, services *Registry, repoID int64, repoPath string, localPath string, lastCommit string, creds *Credentials, clone CloneOptions, opts ProcessOptions) (*ProcessResult, error) {
+	service, err := services.Get(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := authMethod(service, repoPath, creds)
+	if err != nil {
+		return nil, err
+	}
+	clone.Auth = auth
+	clone.Progress = opts.Progress
+
+	repo, err := git.PlainOpen(localPath)
+	switch {
+	case errors.Is(err, git.ErrRepositoryNotExists):
+		log.Printf("No clone found at %s, cloning repository %s again", localPath, repoPath)
+		repo, err = service.CloneRepository(ctx, repoPath, localPath, clone)
+		if err != nil {
+			return nil, fmt.Errorf("failed to clone repository: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	default:
+		log.Printf("Fetching updates for repository %s", repoPath)
+		if f, ok := service.(Fetcher); ok {
+			err = f.FetchRepository(ctx, repo, repoPath, clone)
+		} else {
+			err = fetchRepository(ctx, repo, clone)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch repository: %w", err)
+		}
+	}
+
+	result, err := ProcessRepositoryIncremental(ctx, db, repoID, repo, lastCommit, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to process repository: %w", err)
+	}
+
+	log.Printf("Repository updated: %d new commits, %d contributors in %v (%.1f commits/sec)",
+		result.CommitsProcessed, result.ContributorsFound, result.ProcessingDuration, result.CommitsPerSecond)
+
+	return result, nil
 }
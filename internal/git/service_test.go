@@ -0,0 +1,53 @@
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestCloneSingleBranch(t *testing.T) {
+	f := newFixtureRepo(t)
+	src, wt := f.dir, f.wt
+	main := f.commitFiles("a.txt")
+
+	// The remote's HEAD moves to another branch than the configured default
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("dev"), Create: true}); err != nil {
+		t.Fatalf("failed to check out dev: %v", err)
+	}
+	f.commitFiles("b.txt")
+
+	clonePath := filepath.Join(t.TempDir(), "clone")
+	opts := CloneOptions{SingleBranch: true, Branch: "master"}
+	clone, err := cloneRepository(context.Background(), src, clonePath, opts)
+	if err != nil {
+		t.Fatalf("failed to clone: %v", err)
+	}
+	ref, err := clone.Reference(plumbing.NewBranchReferenceName("master"), true)
+	if err != nil || ref.Hash() != main {
+		t.Fatalf("expected master at %s, got %v (%v)", main, ref, err)
+	}
+	if _, err := clone.Reference(plumbing.NewBranchReferenceName("dev"), true); err == nil {
+		t.Error("expected dev not to be cloned")
+	}
+
+	// Fetches stay on the configured branch
+	next := f.commitFiles("c.txt")
+	if err := wt.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")}); err != nil {
+		t.Fatalf("failed to check out master: %v", err)
+	}
+	main = f.commitFiles("d.txt")
+	if err := fetchRepository(context.Background(), clone, opts); err != nil {
+		t.Fatalf("failed to fetch: %v", err)
+	}
+	ref, err = clone.Reference(plumbing.NewBranchReferenceName("master"), true)
+	if err != nil || ref.Hash() != main {
+		t.Errorf("expected master at %s after fetch, got %v (%v)", main, ref, err)
+	}
+	if _, err := clone.CommitObject(next); err == nil {
+		t.Error("expected commits of dev not to be fetched")
+	}
+}
@@ -0,0 +1,138 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// readTags resolves every tag of the repository to its commit. Annotated tags carry the tagger and
+// message, lightweight tags take the commit date. Tags of trees or blobs are skipped.
+func readTags(repo *git.Repository, repoID int64) ([]*database.Tag, error) {
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	tags := []*database.Tag{}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		tag := &database.Tag{
+			RepositoryID: repoID,
+			Name:         ref.Name().Short(),
+		}
+
+		if annotated, err := repo.TagObject(ref.Hash()); err == nil {
+			c, err := tagCommit(repo, annotated)
+			if err == plumbing.ErrObjectNotFound || err == object.ErrUnsupportedObject {
+				return nil
+			} else if err != nil {
+				return fmt.Errorf("failed to read tag %s: %w", tag.Name, err)
+			}
+			message := annotated.Message
+			tag.CommitHash = c.Hash.String()
+			tag.IsAnnotated = true
+			tag.Message = &message
+			tag.TaggerName = &annotated.Tagger.Name
+			tag.TaggerEmail = &annotated.Tagger.Email
+			tag.TaggedAt = annotated.Tagger.When
+		} else if err == plumbing.ErrObjectNotFound {
+			c, err := repo.CommitObject(ref.Hash())
+			if err != nil {
+				return nil
+			}
+			tag.CommitHash = c.Hash.String()
+			tag.TaggedAt = c.Committer.When
+		} else {
+			return fmt.Errorf("failed to read tag %s: %w", tag.Name, err)
+		}
+
+		tags = append(tags, tag)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Oldest first, so each tag releases what earlier ones did not
+	sort.SliceStable(tags, func(i, j int) bool {
+		if tags[i].TaggedAt.Equal(tags[j].TaggedAt) {
+			return tags[i].Name < tags[j].Name
+		}
+		return tags[i].TaggedAt.Before(tags[j].TaggedAt)
+	})
+	return tags, nil
+}
+
+// tagCommit follows an annotated tag, and the tags it may point to, down to the commit.
+// Tags of trees or blobs yield object.ErrUnsupportedObject.
+func tagCommit(repo *git.Repository, annotated *object.Tag) (*object.Commit, error) {
+	for annotated.TargetType == plumbing.TagObject {
+		next, err := repo.TagObject(annotated.Target)
+		if err != nil {
+			return nil, err
+		}
+		annotated = next
+	}
+	return annotated.Commit()
+}
+
+// releaseCommits assigns every commit reachable from a tag to the oldest tag containing it.
+// tags must be ordered oldest first; the walk of each tag stops at commits already released.
+func releaseCommits(ctx context.Context, repo *git.Repository, tags []*database.Tag, limit *historyLimit) (map[string][]string, error) {
+	releases := make(map[string][]string, len(tags))
+	seen := make(map[plumbing.Hash]bool)
+
+	for _, tag := range tags {
+		tip, err := repo.CommitObject(plumbing.NewHash(tag.CommitHash))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get commit of tag %s: %w", tag.Name, err)
+		}
+
+		hashes := []string{}
+		iter := limit.preorder(tip, seen)
+		err = iter.ForEach(func(c *object.Commit) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+			if limit.excludes(c, seen) {
+				return nil
+			}
+			seen[c.Hash] = true
+			hashes = append(hashes, c.Hash.String())
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to walk tag %s: %w", tag.Name, err)
+		}
+		releases[tag.Name] = hashes
+	}
+	return releases, nil
+}
+
+// processTags replaces the tags of the repository and the commits each of them released
+func processTags(ctx context.Context, db *database.DB, repoID int64, repo *git.Repository, limit *historyLimit) error {
+	tags, err := readTags(repo, repoID)
+	if err != nil {
+		return err
+	}
+
+	releases, err := releaseCommits(ctx, repo, tags, limit)
+	if err != nil {
+		return err
+	}
+
+	if err := db.ReplaceTags(ctx, repoID, tags, releases); err != nil {
+		return fmt.Errorf("failed to persist tags: %w", err)
+	}
+	log.Printf("Recorded %d tags for repository %d", len(tags), repoID)
+	return nil
+}
@@ -0,0 +1,89 @@
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestReadTagsAndReleaseCommits(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo := f.repo
+
+	commit := func(name string, when time.Time) plumbing.Hash {
+		t.Helper()
+		return f.commit(name, name+"\n", when)
+	}
+
+	first := commit("a.txt", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	v1 := commit("b.txt", time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC))
+	third := commit("c.txt", time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+	v2 := commit("d.txt", time.Date(2024, 2, 2, 12, 0, 0, 0, time.UTC))
+
+	// Lightweight v1.0.0 takes the commit date, annotated v2.0.0 the tagger date
+	if _, err := repo.CreateTag("v1.0.0", v1, nil); err != nil {
+		t.Fatalf("failed to create lightweight tag: %v", err)
+	}
+	tagger := &object.Signature{Name: "Releaser", Email: "release@example.com", When: time.Date(2024, 2, 3, 9, 0, 0, 0, time.UTC)}
+	v2Ref, err := repo.CreateTag("v2.0.0", v2, &git.CreateTagOptions{Tagger: tagger, Message: "Second release"})
+	if err != nil {
+		t.Fatalf("failed to create annotated tag: %v", err)
+	}
+
+	// A tag of the annotated tag resolves to the same commit, with its own tagger
+	retagger := &object.Signature{Name: "Releaser", Email: "release@example.com", When: tagger.When.Add(time.Hour)}
+	if _, err := repo.CreateTag("v2.0.0-final", v2Ref.Hash(), &git.CreateTagOptions{Tagger: retagger, Message: "Final"}); err != nil {
+		t.Fatalf("failed to create nested tag: %v", err)
+	}
+
+	tags, err := readTags(repo, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tags) != 3 {
+		t.Fatalf("expected 3 tags, got %d", len(tags))
+	}
+
+	if tags[0].Name != "v1.0.0" || tags[0].IsAnnotated || tags[0].CommitHash != v1.String() {
+		t.Errorf("expected lightweight v1.0.0 at %s first, got %+v", v1, tags[0])
+	}
+	if tags[1].Name != "v2.0.0" || !tags[1].IsAnnotated || tags[1].CommitHash != v2.String() {
+		t.Errorf("expected annotated v2.0.0 at %s second, got %+v", v2, tags[1])
+	}
+	if !tags[1].TaggedAt.Equal(tagger.When) || tags[1].TaggerEmail == nil || *tags[1].TaggerEmail != "release@example.com" {
+		t.Errorf("expected tagger identity and date on v2.0.0, got %+v", tags[1])
+	}
+
+	if tags[2].Name != "v2.0.0-final" || !tags[2].IsAnnotated || tags[2].CommitHash != v2.String() || !tags[2].TaggedAt.Equal(retagger.When) {
+		t.Errorf("expected nested v2.0.0-final at %s third, got %+v", v2, tags[2])
+	}
+
+	releases, err := releaseCommits(context.Background(), repo, tags, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	assertCommits := func(tag string, expected ...plumbing.Hash) {
+		t.Helper()
+		got := make(map[string]bool)
+		for _, h := range releases[tag] {
+			got[h] = true
+		}
+		if len(got) != len(expected) {
+			t.Errorf("expected %d commits in %s, got %v", len(expected), tag, releases[tag])
+			return
+		}
+		for _, h := range expected {
+			if !got[h.String()] {
+				t.Errorf("expected %s in %s, got %v", h, tag, releases[tag])
+			}
+		}
+	}
+	assertCommits("v1.0.0", first, v1)
+	assertCommits("v2.0.0", third, v2)
+	assertCommits("v2.0.0-final")
+}
@@ -0,0 +1,41 @@
+package git
+
+import (
+	"regexp"
+	"strings"
+)
+
+// coAuthorTrailer matches a "Co-authored-by: Name <email>" trailer line
+var coAuthorTrailer = regexp.MustCompile(`(?i)^co-authored-by:\s*(.*?)\s*<([^<>\s]+)>\s*$`)
+
+// coAuthor is a person credited by a commit trailer
+type coAuthor struct {
+	Name  string
+	Email string
+}
+
+// parseCoAuthors extracts the Co-authored-by trailers of a commit message.
+// Duplicates and trailers naming the commit's own author are dropped; emails are compared case-insensitively.
+func parseCoAuthors(message, authorEmail string) []coAuthor {
+	var coAuthors []coAuthor
+	seen := map[string]bool{strings.ToLower(authorEmail): true}
+
+	for _, line := range strings.Split(message, "\n") {
+		m := coAuthorTrailer.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		key := strings.ToLower(m[2])
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		name := m[1]
+		if name == "" {
+			name = m[2]
+		}
+		coAuthors = append(coAuthors, coAuthor{Name: name, Email: m[2]})
+	}
+	return coAuthors
+}
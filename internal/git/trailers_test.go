@@ -0,0 +1,33 @@
+package git
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCoAuthors(t *testing.T) {
+	message := `Add parser
+
+Some details.
+
+Co-authored-by: Jane Doe <jane@example.com>
+co-authored-by: John Roe <JOHN@example.com>
+Co-Authored-By: Jane D. <Jane@Example.com>
+Co-authored-by: Author Self <author@example.com>
+Co-authored-by: missing email
+Signed-off-by: Someone <someone@example.com>
+`
+
+	got := parseCoAuthors(message, "Author@example.com")
+	want := []coAuthor{
+		{Name: "Jane Doe", Email: "jane@example.com"},
+		{Name: "John Roe", Email: "JOHN@example.com"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if got := parseCoAuthors("Plain commit\n", "author@example.com"); len(got) != 0 {
+		t.Errorf("expected no co-authors, got %+v", got)
+	}
+}
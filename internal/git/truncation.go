@@ -0,0 +1,97 @@
+package git
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// historyLimit bounds the history walked in a truncated clone. Parents of the commits at the
+// boundary of a shallow clone are missing from the object store, and commits older than the
+// since date are left out on purpose. A nil limit walks the whole history.
+type historyLimit struct {
+	since     *time.Time
+	missing   map[plumbing.Hash]bool // Parents of the shallow commits
+	truncated bool                   // Set once a walk stopped at one of the bounds
+}
+
+// newHistoryLimit reads the shallow boundary of the clone. since may be nil.
+func newHistoryLimit(repo *git.Repository, since *time.Time) (*historyLimit, error) {
+	shallow, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read shallow commits: %w", err)
+	}
+
+	limit := &historyLimit{since: since, missing: make(map[plumbing.Hash]bool)}
+	for _, hash := range shallow {
+		c, err := repo.CommitObject(hash)
+		if err != nil {
+			continue
+		}
+		for _, parent := range c.ParentHashes {
+			limit.missing[parent] = true
+		}
+	}
+	// Deepening a shallow clone can leave hashes in the list whose parents are present again
+	for parent := range limit.missing {
+		if _, err := repo.CommitObject(parent); err == nil {
+			delete(limit.missing, parent)
+		}
+	}
+	return limit, nil
+}
+
+// Truncated reports whether the walked history stops before the root commits
+func (l *historyLimit) Truncated() bool {
+	return l != nil && (l.truncated || len(l.missing) > 0)
+}
+
+// excludes reports whether c is older than the since date. Its parents are then marked
+// seen so that walks do not go further back along this line of history.
+func (l *historyLimit) excludes(c *object.Commit, seen map[plumbing.Hash]bool) bool {
+	if l == nil || l.since == nil || !c.Committer.When.Before(*l.since) {
+		return false
+	}
+	l.truncated = true
+	seen[c.Hash] = true
+	for _, parent := range c.ParentHashes {
+		seen[parent] = true
+	}
+	return true
+}
+
+// isMissing reports whether hash lies behind the shallow boundary
+func (l *historyLimit) isMissing(hash plumbing.Hash) bool {
+	return l != nil && l.missing[hash]
+}
+
+// preorder walks the history of c like object.NewCommitPreorderIter, without
+// looking up the parents missing from a shallow clone
+func (l *historyLimit) preorder(c *object.Commit, seen map[plumbing.Hash]bool) object.CommitIter {
+	var ignore []plumbing.Hash
+	if l != nil {
+		ignore = make([]plumbing.Hash, 0, len(l.missing))
+		for hash := range l.missing {
+			ignore = append(ignore, hash)
+		}
+	}
+	return object.NewCommitPreorderIter(c, seen, ignore)
+}
+
+// isAncestor reports whether ancestor is reachable from c. Unlike Commit.IsAncestor it stops at
+// the shallow boundary, so an ancestor behind it is reported as unreachable.
+func (l *historyLimit) isAncestor(ancestor plumbing.Hash, c *object.Commit) (bool, error) {
+	found := false
+	err := l.preorder(c, make(map[plumbing.Hash]bool)).ForEach(func(c *object.Commit) error {
+		if c.Hash == ancestor {
+			found = true
+			return storer.ErrStop
+		}
+		return nil
+	})
+	return found, err
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestHistoryLimit(t *testing.T) {
+	f := newFixtureRepo(t)
+	repo := f.repo
+
+	commit := func(name string, when time.Time) plumbing.Hash {
+		t.Helper()
+		return f.commit(name, name+"\n", when)
+	}
+
+	root := commit("a.txt", time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+	middle := commit("b.txt", time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC))
+	head := commit("c.txt", time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC))
+
+	walk := func(repo *git.Repository, limit *historyLimit, firstParent bool) []plumbing.Hash {
+		t.Helper()
+		var hashes []plumbing.Hash
+		tips := []branchTip{{"main", head}}
+		err := newBranchesIter(repo, tips, make(map[plumbing.Hash]bool), firstParent, limit).ForEach(func(c *object.Commit) error {
+			hashes = append(hashes, c.Hash)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected walk error: %v", err)
+		}
+		return hashes
+	}
+
+	// Commits before the since date are left out
+	since := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	for _, firstParent := range []bool{false, true} {
+		limit := &historyLimit{since: &since}
+		hashes := walk(repo, limit, firstParent)
+		if len(hashes) != 2 || hashes[0] != head || hashes[1] != middle {
+			t.Errorf("firstParent=%v: expected %s and %s, got %v", firstParent, head, middle, hashes)
+		}
+		if !limit.Truncated() {
+			t.Errorf("firstParent=%v: expected history to be truncated by the since date", firstParent)
+		}
+	}
+
+	unbounded := &historyLimit{}
+	if hashes := walk(repo, unbounded, false); len(hashes) != 3 || unbounded.Truncated() {
+		t.Errorf("expected the full untruncated history, got %v", hashes)
+	}
+
+	// Simulate a shallow clone of depth 2: the root commit is missing
+	if err := repo.Storer.SetShallow([]plumbing.Hash{middle}); err != nil {
+		t.Fatalf("failed to mark shallow commit: %v", err)
+	}
+	rootHex := root.String()
+	if err := os.Remove(filepath.Join(f.dir, ".git", "objects", rootHex[:2], rootHex[2:])); err != nil {
+		t.Fatalf("failed to remove root commit: %v", err)
+	}
+	shallow, err := git.PlainOpen(f.dir)
+	if err != nil {
+		t.Fatalf("failed to reopen fixture: %v", err)
+	}
+
+	limit, err := newHistoryLimit(shallow, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !limit.Truncated() {
+		t.Error("expected shallow history to be truncated")
+	}
+	for _, firstParent := range []bool{false, true} {
+		if hashes := walk(shallow, limit, firstParent); len(hashes) != 2 {
+			t.Errorf("firstParent=%v: expected the 2 commits of the shallow clone, got %v", firstParent, hashes)
+		}
+	}
+
+	headCommit, err := shallow.CommitObject(head)
+	if err != nil {
+		t.Fatalf("failed to get HEAD commit: %v", err)
+	}
+	if ok, err := limit.isAncestor(middle, headCommit); err != nil || !ok {
+		t.Errorf("expected %s to be an ancestor of HEAD, got %v (err %v)", middle, ok, err)
+	}
+	if ok, err := limit.isAncestor(root, headCommit); err != nil || ok {
+		t.Errorf("expected %s behind the shallow boundary to be unreachable, got %v (err %v)", root, ok, err)
+	}
+}
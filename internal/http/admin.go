@@ -0,0 +1,29 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GetStorageUsage handles GET /api/v1/admin/storage
+// Reports the disk usage of the clones kept by the workers, largest first.
+func (h *Handler) GetStorageUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	usage, err := h.db.ListCloneStorageUsage(ctx)
+	if err != nil {
+		Error(w, fmt.Errorf("failed to get storage usage: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	var total int64
+	for _, u := range usage {
+		total += u.SizeBytes
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"total_bytes":  total,
+		"quota_bytes":  h.storageQuota,
+		"repositories": usage,
+	})
+}
@@ -21,6 +21,7 @@ type mockPublisher struct{}
 
 func (m *mockPublisher) PublishIndexJob(ctx context.Context, repoID int) error  { return nil }
 func (m *mockPublisher) PublishUpdateJob(ctx context.Context, repoID int) error { return nil }
+func (m *mockPublisher) PublishDeleteJob(ctx context.Context, repoID int) error { return nil }
 func (m *mockPublisher) PublishDiscoverJob(ctx context.Context, userID int64, provider string) error {
 	return nil
 }
@@ -41,6 +42,9 @@ func (m *mockAuthProvider) FetchProfile(ctx context.Context, token *oauth2.Token
 func (m *mockAuthProvider) FetchRepositories(ctx context.Context, token *oauth2.Token) ([]auth.RemoteRepo, error) {
 	return nil, nil
 }
+func (m *mockAuthProvider) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(token)
+}
 
 func TestAuthCallback(t *testing.T) {
 	mockPool, err := pgxmock.NewPool()
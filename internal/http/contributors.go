@@ -2,6 +2,7 @@ package http
 
 import (
 	"fmt"
+	"git-repository-visualizer/internal/database"
 	"git-repository-visualizer/internal/validation"
 	"net/http"
 	"strconv"
@@ -28,7 +29,18 @@ func (h *Handler) ListContributors(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	limit, offset := h.GetLimitOffset(r)
 
-	contributors, err := h.db.GetContributorsByRepository(ctx, repoID, limit, offset)
+	filter := database.ContributorFilter{
+		Branch:           r.URL.Query().Get("branch"),
+		IncludeCoAuthors: r.URL.Query().Get("include_coauthors") == "true",
+		ExcludeBots:      r.URL.Query().Get("exclude_bots") != "false",
+	}
+
+	var contributors []*database.Contributor
+	if filter.Branch != "" || filter.IncludeCoAuthors {
+		contributors, err = h.db.GetContributorsFiltered(ctx, repoID, filter, limit, offset)
+	} else {
+		contributors, err = h.db.GetContributorsByRepository(ctx, repoID, filter.ExcludeBots, limit, offset)
+	}
 	if err != nil {
 		parsedErr := validation.ParseDatabaseError(err)
 		Error(w, parsedErr, http.StatusInternalServerError)
@@ -0,0 +1,114 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/git"
+	"git-repository-visualizer/internal/validation"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// SetDeployKeyRequest represents the request body for setting a repository deploy key
+type SetDeployKeyRequest struct {
+	PrivateKey string `json:"private_key"`
+	Passphrase string `json:"passphrase"`
+}
+
+// SetDeployKey handles PUT /api/v1/repositories/{id}/deploy-key
+func (h *Handler) SetDeployKey(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	var req SetDeployKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, fmt.Errorf("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	v := validation.New()
+	v.Required("private_key", req.PrivateKey)
+	if req.PrivateKey != "" {
+		v.Custom("private_key", func() error {
+			return git.ValidateDeployKey([]byte(req.PrivateKey), req.Passphrase)
+		})
+	}
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	if _, err := h.db.GetRepositoryForUser(ctx, id, user.ID); err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	key := &database.DeployKey{
+		RepositoryID: id,
+		PrivateKey:   req.PrivateKey,
+	}
+	if req.Passphrase != "" {
+		key.Passphrase = &req.Passphrase
+	}
+
+	if err := h.db.UpsertDeployKey(ctx, key); err != nil {
+		parsedErr := validation.ParseDatabaseError(err)
+		Error(w, parsedErr, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, key)
+}
+
+// DeleteDeployKey handles DELETE /api/v1/repositories/{id}/deploy-key
+func (h *Handler) DeleteDeployKey(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	if _, err := h.db.GetRepositoryForUser(ctx, id, user.ID); err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	if err := h.db.DeleteDeployKey(ctx, id); err != nil {
+		if validation.IsNotFound(err) {
+			Error(w, fmt.Errorf("deploy key not found"), http.StatusNotFound)
+			return
+		}
+		Error(w, fmt.Errorf("failed to delete deploy key: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"message":       "deploy key deleted",
+		"repository_id": id,
+	})
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"fmt"
+	"git-repository-visualizer/internal/stats"
 	"git-repository-visualizer/internal/validation"
 	"net/http"
 	"strconv"
@@ -35,7 +36,21 @@ func (h *Handler) ListFiles(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	opts := stats.FileInventoryOptions{}
+	if largestStr := r.URL.Query().Get("largest"); largestStr != "" {
+		if parsed, err := strconv.Atoi(largestStr); err == nil && parsed > 0 {
+			opts.LargestBinaries = parsed
+		}
+	}
+
+	summary, err := stats.GetFileInventory(ctx, h.db.Pool(), repoID, opts)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
 	JSON(w, http.StatusOK, map[string]interface{}{
-		"files": files,
+		"files":   files,
+		"summary": summary,
 	})
 }
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/config"
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/stats"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestListFilesBinaryInfo(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock pool: %v", err)
+	}
+	defer mockPool.Close()
+
+	h := NewHandler(database.NewTestDB(mockPool), &mockPublisher{}, &config.Config{})
+	repoID := int64(4)
+	logoHash := "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"
+
+	mockPool.ExpectQuery("FROM files").
+		WithArgs(repoID, pgxmock.AnyArg(), pgxmock.AnyArg()).
+		WillReturnRows(pgxmock.NewRows([]string{"id", "repository_id", "path", "language", "lines", "code_lines", "comment_lines", "blank_lines", "is_binary", "size_bytes", "blob_hash", "is_vendored", "is_generated", "is_documentation", "created_at", "updated_at"}).
+			AddRow(int64(1), repoID, "main.go", "Go", 3, 2, 0, 1, false, int64(29), "e2a1f8c4b2d3a9d4c7f1e0b6a5d4c3b2a1f0e9d8", false, false, false, time.Now(), time.Now()).
+			AddRow(int64(2), repoID, "logo.png", "", 0, 0, 0, 0, true, int64(2048), logoHash, false, false, false, time.Now(), time.Now()))
+	mockPool.ExpectQuery("GROUP BY extension").
+		WithArgs(repoID).
+		WillReturnRows(pgxmock.NewRows([]string{"extension", "files", "binary_files", "bytes", "lines"}).
+			AddRow("png", 1, 1, int64(2048), 0).
+			AddRow("go", 1, 0, int64(29), 3))
+	mockPool.ExpectQuery("WHERE repository_id = \\$1 AND is_binary").
+		WithArgs(repoID, stats.DefaultLargestBinaries).
+		WillReturnRows(pgxmock.NewRows([]string{"path", "size_bytes", "blob_hash", "binary_bytes"}).
+			AddRow("logo.png", int64(2048), logoHash, int64(2048)))
+
+	rctx := chi.NewRouteContext()
+	rctx.URLParams.Add("repoID", "4")
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/repositories/4/files", nil)
+	req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+	rr := httptest.NewRecorder()
+
+	h.ListFiles(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var body struct {
+		Files   []database.File           `json:"files"`
+		Summary stats.FileInventoryResult `json:"summary"`
+	}
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(body.Files) != 2 {
+		t.Fatalf("expected 2 files, got %d", len(body.Files))
+	}
+	logo := body.Files[1]
+	if !logo.IsBinary || logo.Lines != 0 || logo.SizeBytes != 2048 || logo.BlobHash != logoHash {
+		t.Errorf("expected logo.png as a 2048 byte binary blob %s without lines, got %+v", logoHash, logo)
+	}
+	if body.Files[0].IsBinary {
+		t.Error("expected main.go not to be binary")
+	}
+
+	summary := body.Summary
+	if summary.TotalFiles != 2 || summary.TotalBytes != 2077 || summary.TotalLines != 3 || summary.BinaryFiles != 1 || summary.BinaryBytes != 2048 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+	if len(summary.LargestBinaries) != 1 || summary.LargestBinaries[0] != (stats.LargeFile{Path: "logo.png", SizeBytes: 2048, BlobHash: logoHash}) {
+		t.Errorf("expected logo.png as the largest binary, got %+v", summary.LargestBinaries)
+	}
+
+	if err := mockPool.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
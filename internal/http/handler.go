@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"git-repository-visualizer/internal/auth"
 	"git-repository-visualizer/internal/config"
@@ -21,6 +22,10 @@ type Handler struct {
 	httpCfg      config.HTTPConfig
 	authRegistry *auth.Registry
 	jwtManager   *auth.JWTManager
+	adminEmails  map[string]bool
+	storageQuota int64  // Bytes (0 = unlimited)
+	storagePath  string // Worker storage path, where uploaded bundles are stored
+	maxBundle    int64  // Bytes
 }
 
 func NewHandler(db *database.DB, publisher queue.IPublisher, cfg *config.Config) *Handler {
@@ -34,6 +39,13 @@ func NewHandler(db *database.DB, publisher queue.IPublisher, cfg *config.Config)
 		httpCfg:      cfg.HTTP,
 		authRegistry: registry,
 		jwtManager:   auth.NewJWTManager(cfg.Auth.JWTSecret),
+		adminEmails:  make(map[string]bool),
+		storageQuota: int64(cfg.Worker.StorageQuotaMB) * 1024 * 1024,
+		storagePath:  cfg.Worker.StoragePath,
+		maxBundle:    int64(cfg.HTTP.MaxBundleMB) * 1024 * 1024,
+	}
+	for _, email := range cfg.Auth.AdminEmails {
+		h.adminEmails[strings.ToLower(email)] = true
 	}
 
 	// Apply global middleware
@@ -65,6 +77,7 @@ func (h *Handler) registerRoutes() {
 
 			r.Post("/repositories", h.CreateRepository)
 			r.Post("/repositories/sync", h.SyncUserRepositories)
+			r.Post("/repositories/bundle", h.UploadBundle)
 			r.Get("/repositories", h.ListRepositories)
 
 			// Routes requiring ownership
@@ -72,9 +85,19 @@ func (h *Handler) registerRoutes() {
 				// Internal handlers now check ownership strictly using GetRepositoryForUser
 				r.Patch("/repositories/{id}", h.UpdateRepository)
 				r.Get("/repositories/{id}", h.GetRepository)
+				r.Delete("/repositories/{id}", h.DeleteRepository)
 				r.Get("/repositories/{id}/status", h.GetRepositoryStatus)
 				r.Post("/repositories/{id}/index", h.IndexRepository)
 				r.Post("/repositories/{id}/sync", h.SyncRepository)
+				r.Put("/repositories/{id}/deploy-key", h.SetDeployKey)
+				r.Delete("/repositories/{id}/deploy-key", h.DeleteDeployKey)
+				r.Get("/repositories/{id}/signing-keys", h.ListSigningKeys)
+				r.Post("/repositories/{id}/signing-keys", h.AddSigningKey)
+				r.Delete("/repositories/{id}/signing-keys/{keyID}", h.DeleteSigningKey)
+				r.Put("/repositories/{id}/bundle", h.ReplaceBundle)
+				r.Get("/repositories/{id}/identities", h.ListIdentities)
+				r.Post("/repositories/{id}/identities", h.MergeIdentities)
+				r.Delete("/repositories/{id}/identities/{aliasID}", h.DeleteIdentityAlias)
 
 				// Repository stats
 				r.Route("/repositories/{repoID}/stats", func(r chi.Router) {
@@ -83,11 +106,26 @@ func (h *Handler) registerRoutes() {
 					r.Use(h.ValidateRepositoryStatus)
 					r.Get("/contributors", h.ListContributors)
 					r.Get("/files", h.ListFiles)
+					r.Get("/languages", h.GetLanguages)
+					r.Get("/growth", h.GetGrowth)
+					r.Get("/releases", h.GetReleases)
+					r.Get("/commit-types", h.GetCommitTypes)
+					r.Get("/signing", h.GetSigning)
 					r.Get("/bus-factor", h.GetBusFactor)
+					r.Get("/ownership", h.GetOwnership)
 					r.Get("/churn", h.GetChurnStats)
+					r.Get("/file-history", h.GetFileHistory)
 					r.Get("/commit-activity", h.GetCommitActivity)
+					r.Get("/branches", h.ListBranches)
 				})
 			})
+
+			// Administration
+			r.Route("/admin", func(r chi.Router) {
+				r.Use(h.RequireAdmin)
+				r.Get("/storage", h.GetStorageUsage)
+				r.Post("/repositories/local", h.ImportLocalRepository)
+			})
 		})
 
 		// Queue management
@@ -106,7 +144,8 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 }
 
 // ValidateRepositoryStatus middleware ensures that statistics endpoints only serve data
-// for repositories that have been fully indexed.
+// for repositories that have been fully indexed. Once an index completed, its data is served
+// while the repository is re-indexed and after a re-index failed.
 func (h *Handler) ValidateRepositoryStatus(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		repoIDStr := chi.URLParam(r, "repoID")
@@ -134,7 +173,7 @@ func (h *Handler) ValidateRepositoryStatus(next http.Handler) http.Handler {
 			return
 		}
 
-		if repo.Status != database.StatusCompleted {
+		if repo.LastIndexedAt == nil {
 			Error(w, fmt.Errorf("repository indexing is not completed (current status: %s). please wait for indexing to finish", repo.Status), http.StatusConflict)
 			return
 		}
@@ -0,0 +1,66 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/config"
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestValidateRepositoryStatus(t *testing.T) {
+	mockPool, err := pgxmock.NewPool()
+	if err != nil {
+		t.Fatalf("failed to create mock pool: %v", err)
+	}
+	defer mockPool.Close()
+
+	h := NewHandler(database.NewTestDB(mockPool), &mockPublisher{}, &config.Config{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	indexedAt := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name          string
+		status        database.RepositoryStatus
+		lastIndexedAt *time.Time
+		expected      int
+	}{
+		{"first index running", database.StatusIndexing, nil, http.StatusConflict},
+		{"first index failed", database.StatusFailed, nil, http.StatusConflict},
+		{"indexed", database.StatusCompleted, &indexedAt, http.StatusOK},
+		{"re-index running", database.StatusIndexing, &indexedAt, http.StatusOK},
+		{"re-index failed", database.StatusFailed, &indexedAt, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		repoID := int64(4)
+		mockPool.ExpectQuery("SELECT id, url, local_path, default_branch, status, last_indexed_at").
+			WithArgs(repoID).
+			WillReturnRows(pgxmock.NewRows([]string{"id", "url", "local_path", "default_branch", "status", "last_indexed_at", "created_at", "updated_at", "user_id", "name", "description", "is_private", "provider", "last_indexed_commit", "branch_mode", "branches", "first_parent", "clone_depth", "clone_since", "single_branch", "history_truncated", "bot_patterns"}).
+				AddRow(repoID, "url", nil, "main", tt.status, tt.lastIndexedAt, time.Now(), time.Now(), int64(1), "name", "desc", false, "github", nil, database.BranchModeDefault, []string{}, false, 0, nil, false, false, []string{}))
+
+		rctx := chi.NewRouteContext()
+		rctx.URLParams.Add("id", "4")
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/repositories/4/churn", nil)
+		req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, rctx))
+		rr := httptest.NewRecorder()
+
+		h.ValidateRepositoryStatus(next).ServeHTTP(rr, req)
+
+		if rr.Code != tt.expected {
+			t.Errorf("%s: expected status %d, got %d: %s", tt.name, tt.expected, rr.Code, rr.Body.String())
+		}
+	}
+
+	if err := mockPool.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet expectations: %v", err)
+	}
+}
@@ -0,0 +1,202 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/validation"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// MergeIdentitiesRequest represents the request body for merging identities into a canonical one
+type MergeIdentitiesRequest struct {
+	CanonicalEmail string   `json:"canonical_email"`
+	CanonicalName  string   `json:"canonical_name"`
+	Emails         []string `json:"emails"` // Identities to aggregate under the canonical one
+}
+
+// ListIdentities handles GET /api/v1/repositories/{id}/identities
+func (h *Handler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	if _, err := h.db.GetRepositoryForUser(ctx, id, user.ID); err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	aliases, err := h.db.ListIdentityAliases(ctx, id)
+	if err != nil {
+		parsedErr := validation.ParseDatabaseError(err)
+		Error(w, parsedErr, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"aliases": aliases,
+	})
+}
+
+// MergeIdentities handles POST /api/v1/repositories/{id}/identities
+// Stats aggregate the listed emails under the canonical identity from the next request on;
+// no re-index is needed. An email merged before is moved to the new canonical identity, together
+// with the emails merged into it.
+func (h *Handler) MergeIdentities(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	var req MergeIdentitiesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, fmt.Errorf("invalid request body"), http.StatusBadRequest)
+		return
+	}
+	req.CanonicalEmail = strings.TrimSpace(req.CanonicalEmail)
+	req.CanonicalName = strings.TrimSpace(req.CanonicalName)
+
+	v := validation.New()
+	v.Required("canonical_email", req.CanonicalEmail)
+	v.Custom("emails", func() error {
+		if len(req.Emails) == 0 {
+			return fmt.Errorf("at least one email is required")
+		}
+		for _, email := range req.Emails {
+			if strings.TrimSpace(email) == "" {
+				return fmt.Errorf("emails must not be empty")
+			}
+		}
+		return nil
+	})
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	if _, err := h.db.GetRepositoryForUser(ctx, id, user.ID); err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	canonicalEmail := req.CanonicalEmail
+	var canonicalName *string
+	if req.CanonicalName != "" {
+		canonicalName = &req.CanonicalName
+	}
+
+	// The canonical email is aliased to itself too, so its own commits take the canonical name
+	emails := append([]string{canonicalEmail}, req.Emails...)
+	seen := make(map[string]bool)
+	var aliases []*database.IdentityAlias
+	for _, email := range emails {
+		key := strings.ToLower(strings.TrimSpace(email))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if key == strings.ToLower(canonicalEmail) && canonicalName == nil {
+			continue
+		}
+		aliases = append(aliases, &database.IdentityAlias{
+			RepositoryID:   id,
+			Email:          key,
+			CanonicalEmail: &canonicalEmail,
+			CanonicalName:  canonicalName,
+		})
+	}
+
+	if err := h.db.UpsertManualAliases(ctx, aliases); err != nil {
+		parsedErr := validation.ParseDatabaseError(err)
+		Error(w, parsedErr, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"aliases": aliases,
+	})
+}
+
+// DeleteIdentityAlias handles DELETE /api/v1/repositories/{id}/identities/{aliasID}
+// Only manual aliases can be deleted; .mailmap entries are changed by editing the file.
+func (h *Handler) DeleteIdentityAlias(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	aliasID, err := strconv.ParseInt(chi.URLParam(r, "aliasID"), 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid alias ID"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	if _, err := h.db.GetRepositoryForUser(ctx, id, user.ID); err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	alias, err := h.db.GetIdentityAlias(ctx, id, aliasID)
+	if err != nil {
+		if validation.IsNotFound(err) {
+			Error(w, fmt.Errorf("alias not found"), http.StatusNotFound)
+			return
+		}
+		Error(w, fmt.Errorf("failed to get alias: %w", err), http.StatusInternalServerError)
+		return
+	}
+	if alias.Source != database.AliasSourceManual {
+		Error(w, fmt.Errorf("aliases read from .mailmap are changed by editing the file"), http.StatusConflict)
+		return
+	}
+
+	if err := h.db.DeleteIdentityAlias(ctx, id, aliasID); err != nil {
+		if validation.IsNotFound(err) {
+			Error(w, fmt.Errorf("alias not found"), http.StatusNotFound)
+			return
+		}
+		Error(w, fmt.Errorf("failed to delete alias: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"message":  "alias deleted",
+		"alias_id": aliasID,
+	})
+}
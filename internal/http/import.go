@@ -0,0 +1,255 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/git"
+	"git-repository-visualizer/internal/storage"
+	"git-repository-visualizer/internal/validation"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// bundleMemory is the part of an upload kept in memory while parsing the form, the rest is spooled to disk
+const bundleMemory = 32 << 20
+
+// errBundleInUse rejects replacing the bundle a running index or update may be reading
+var errBundleInUse = errors.New("repository is being indexed; replace its bundle once the job has finished")
+
+// ImportLocalRepositoryRequest represents the request body for indexing a repository on the worker host
+type ImportLocalRepositoryRequest struct {
+	Path          string `json:"path"` // Absolute path of a bare repository or working copy
+	Name          string `json:"name"`
+	DefaultBranch string `json:"default_branch"`
+}
+
+// localURL is the URL of a repository imported from a path of the worker host, which routes it to git.Local
+func localURL(path string) string {
+	return "file://" + path
+}
+
+// readBundleUpload parses a multipart upload and stores its "bundle" file at path. Large uploads
+// take longer than the server timeouts, so they are lifted for the request. The returned error is
+// a validation error when the upload is not a usable bundle. When replaceable is set, it is called
+// once the upload is validated, right before it replaces the file at path.
+func (h *Handler) readBundleUpload(w http.ResponseWriter, r *http.Request, path string, replaceable func() error) (int, error) {
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+
+	r.Body = http.MaxBytesReader(w, r.Body, h.maxBundle)
+	if err := r.ParseMultipartForm(bundleMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return http.StatusRequestEntityTooLarge, fmt.Errorf("bundle exceeds %d bytes", h.maxBundle)
+		}
+		return http.StatusBadRequest, fmt.Errorf("invalid multipart form: %w", err)
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	file, _, err := r.FormFile("bundle")
+	if err != nil {
+		v := validation.New()
+		v.Custom("bundle", func() error { return fmt.Errorf("bundle file is required") })
+		return http.StatusBadRequest, v.Validate()
+	}
+	defer file.Close()
+
+	var invalid, busy error
+	err = storage.SaveBundle(path, file, func(r io.Reader) error {
+		if invalid = git.ValidateBundle(r); invalid != nil {
+			return invalid
+		}
+		if replaceable != nil {
+			busy = replaceable()
+		}
+		return busy
+	})
+	if invalid != nil {
+		v := validation.New()
+		v.Custom("bundle", func() error { return invalid })
+		return http.StatusBadRequest, v.Validate()
+	}
+	if errors.Is(busy, errBundleInUse) {
+		return http.StatusConflict, busy
+	}
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	return 0, nil
+}
+
+// createImportedRepository stores a repository imported from url and queues its first index
+func (h *Handler) createImportedRepository(w http.ResponseWriter, r *http.Request, url, name, defaultBranch string) {
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+
+	if defaultBranch == "" {
+		defaultBranch = "main"
+	}
+
+	repo := &database.Repository{
+		URL:           url,
+		Name:          &name,
+		DefaultBranch: defaultBranch,
+		BranchMode:    database.BranchModeDefault,
+		Status:        database.StatusPending,
+		UserID:        &user.ID,
+	}
+
+	if err := h.db.CreateRepository(ctx, repo); err != nil {
+		parsedErr := validation.ParseDatabaseError(err)
+		Error(w, parsedErr, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.publisher.PublishIndexJob(ctx, int(repo.ID)); err != nil {
+		Error(w, fmt.Errorf("failed to queue index job: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusAccepted, map[string]interface{}{
+		"message":       "index job queued successfully",
+		"repository_id": repo.ID,
+		"repository":    repo,
+	})
+}
+
+// UploadBundle handles POST /api/v1/repositories/bundle
+// Creates a repository from a git bundle uploaded as multipart form, for hosts the workers cannot
+// reach. The bundle is stored under the worker storage path and indexed like a clone.
+func (h *Handler) UploadBundle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	path, err := storage.NewBundlePath(h.storagePath)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+	if status, err := h.readBundleUpload(w, r, path, nil); err != nil {
+		Error(w, err, status)
+		return
+	}
+
+	name := strings.TrimSpace(r.FormValue("name"))
+	defaultBranch := r.FormValue("default_branch")
+
+	v := validation.New()
+	v.Required("name", name).MaxLength("name", name, 255)
+	v.MaxLength("default_branch", defaultBranch, 255)
+	if err := v.Validate(); err != nil {
+		storage.RemoveBundle(h.storagePath, path)
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.createImportedRepository(w, r, localURL(path), name, defaultBranch)
+}
+
+// ReplaceBundle handles PUT /api/v1/repositories/{id}/bundle
+// Replaces the bundle of a repository created by UploadBundle and queues an update, which indexes
+// the commits the new bundle adds. The bundle is not replaced while a job may be reading it.
+func (h *Handler) ReplaceBundle(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	repo, err := h.db.GetRepositoryForUser(ctx, id, user.ID)
+	if err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	path, ok := strings.CutPrefix(repo.URL, "file://")
+	if !ok || !storage.IsBundlePath(h.storagePath, path) {
+		Error(w, fmt.Errorf("repository was not created from a bundle"), http.StatusConflict)
+		return
+	}
+
+	if repo.Status == database.StatusIndexing {
+		Error(w, errBundleInUse, http.StatusConflict)
+		return
+	}
+
+	// The upload can take long, so the status is checked again before the bundle is replaced
+	replaceable := func() error {
+		current, err := h.db.GetRepository(ctx, id)
+		if err != nil {
+			return err
+		}
+		if current.Status == database.StatusIndexing {
+			return errBundleInUse
+		}
+		return nil
+	}
+	if status, err := h.readBundleUpload(w, r, path, replaceable); err != nil {
+		Error(w, err, status)
+		return
+	}
+
+	if err := h.publisher.PublishUpdateJob(ctx, int(id)); err != nil {
+		Error(w, fmt.Errorf("failed to queue update job: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusAccepted, map[string]interface{}{
+		"message":       "Update job queued successfully",
+		"repository_id": id,
+		"repository":    repo,
+	})
+}
+
+// ImportLocalRepository handles POST /api/v1/admin/repositories/local
+// Creates a repository from a path of the worker host and queues its index. The path is only
+// opened by the workers, so a path they cannot read fails the index rather than the request.
+func (h *Handler) ImportLocalRepository(w http.ResponseWriter, r *http.Request) {
+	var req ImportLocalRepositoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, fmt.Errorf("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	v := validation.New()
+	v.Required("path", req.Path)
+	if req.Path != "" {
+		v.Custom("path", func() error {
+			if !filepath.IsAbs(req.Path) {
+				return fmt.Errorf("path must be absolute")
+			}
+			return nil
+		})
+	}
+	v.Required("name", req.Name).MaxLength("name", req.Name, 255)
+	v.MaxLength("default_branch", req.DefaultBranch, 255)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	h.createImportedRepository(w, r, localURL(filepath.Clean(req.Path)), strings.TrimSpace(req.Name), req.DefaultBranch)
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/config"
+	"git-repository-visualizer/internal/database"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/pashagolub/pgxmock/v3"
+)
+
+func TestReplaceBundleWhileIndexing(t *testing.T) {
+	hash := "3b18e512dba79e4c8300dd08aeb37f8e728b8dad"
+	previous := []byte("# v2 git bundle\n" + hash + " refs/heads/main\n\n")
+	upload := []byte("# v2 git bundle\n" + hash + " refs/heads/feature\n\n")
+
+	tests := []struct {
+		name      string
+		status    database.RepositoryStatus // When the request arrives
+		completed database.RepositoryStatus // Once the upload is validated, empty if it is not checked
+	}{
+		{"indexing when requested", database.StatusIndexing, ""},
+		{"indexing once uploaded", database.StatusCompleted, database.StatusIndexing},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPool, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock pool: %v", err)
+			}
+			defer mockPool.Close()
+
+			storagePath := t.TempDir()
+			bundle := filepath.Join(storagePath, "bundles", "0123456789abcdef.bundle")
+			if err := os.MkdirAll(filepath.Dir(bundle), 0o755); err != nil {
+				t.Fatalf("failed to create bundle directory: %v", err)
+			}
+			if err := os.WriteFile(bundle, previous, 0o644); err != nil {
+				t.Fatalf("failed to write bundle: %v", err)
+			}
+
+			h := NewHandler(database.NewTestDB(mockPool), &mockPublisher{}, &config.Config{
+				HTTP:   config.HTTPConfig{MaxBundleMB: 1},
+				Worker: config.WorkerConfig{StoragePath: storagePath},
+			})
+
+			repoID, userID := int64(4), int64(1)
+			columns := []string{"id", "url", "local_path", "default_branch", "status", "last_indexed_at", "created_at", "updated_at", "user_id", "name", "description", "is_private", "provider", "last_indexed_commit", "branch_mode", "branches", "first_parent", "clone_depth", "clone_since", "single_branch", "history_truncated", "bot_patterns"}
+			row := func(status database.RepositoryStatus) *pgxmock.Rows {
+				return pgxmock.NewRows(columns).
+					AddRow(repoID, "file://"+bundle, nil, "main", status, nil, time.Now(), time.Now(), userID, "name", "desc", false, "", nil, database.BranchModeDefault, []string{}, false, 0, nil, false, false, []string{})
+			}
+			mockPool.ExpectQuery("WHERE id = \\$1 AND user_id = \\$2").
+				WithArgs(repoID, userID).
+				WillReturnRows(row(tt.status))
+			if tt.completed != "" {
+				mockPool.ExpectQuery("WHERE id = \\$1").
+					WithArgs(repoID).
+					WillReturnRows(row(tt.completed))
+			}
+
+			var body bytes.Buffer
+			form := multipart.NewWriter(&body)
+			part, err := form.CreateFormFile("bundle", "repo.bundle")
+			if err != nil {
+				t.Fatalf("failed to create form file: %v", err)
+			}
+			part.Write(upload)
+			form.Close()
+
+			rctx := chi.NewRouteContext()
+			rctx.URLParams.Add("id", "4")
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/repositories/4/bundle", &body)
+			req.Header.Set("Content-Type", form.FormDataContentType())
+			ctx := context.WithValue(req.Context(), chi.RouteCtxKey, rctx)
+			ctx = context.WithValue(ctx, userKey, &database.User{ID: userID})
+			req = req.WithContext(ctx)
+			rr := httptest.NewRecorder()
+
+			h.ReplaceBundle(rr, req)
+
+			if rr.Code != http.StatusConflict {
+				t.Errorf("expected status %d, got %d: %s", http.StatusConflict, rr.Code, rr.Body.String())
+			}
+			stored, err := os.ReadFile(bundle)
+			if err != nil {
+				t.Fatalf("failed to read bundle: %v", err)
+			}
+			if !bytes.Equal(stored, previous) {
+				t.Errorf("expected the bundle to be kept, got %q", stored)
+			}
+
+			if err := mockPool.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
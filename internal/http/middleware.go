@@ -37,6 +37,11 @@ func (w *wrappedWriter) WriteHeader(statusCode int) {
 	w.statusCode = statusCode
 }
 
+// Unwrap gives http.ResponseController access to the underlying writer
+func (w *wrappedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func Logger(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -149,3 +154,21 @@ func (h *Handler) RequireRepoOwnership(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequireAdmin middleware restricts a route to the users listed in ADMIN_EMAILS
+func (h *Handler) RequireAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user := GetUserFromContext(r.Context())
+		if user == nil {
+			Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+			return
+		}
+
+		if !h.adminEmails[strings.ToLower(user.Email)] {
+			Error(w, fmt.Errorf("admin access required"), http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
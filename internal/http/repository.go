@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"time"
 
+	"git-repository-visualizer/internal/bots"
 	"git-repository-visualizer/internal/database"
 	"git-repository-visualizer/internal/validation"
 
@@ -15,12 +18,87 @@ import (
 
 // CreateRepositoryRequest represents the request body for creating a repository
 type CreateRepositoryRequest struct {
-	URL           string `json:"url"`
-	DefaultBranch string `json:"default_branch"`
+	URL           string   `json:"url"`
+	DefaultBranch string   `json:"default_branch"` // Unchanged when empty
+	BranchMode    string   `json:"branch_mode"`    // "default", "list" or "all"
+	Branches      []string `json:"branches"`
+	FirstParent   bool     `json:"first_parent"`
+	CloneDepth    int      `json:"clone_depth"` // 0 = full history
+	CloneSince    string   `json:"clone_since"` // RFC 3339 timestamp or YYYY-MM-DD
+	SingleBranch  bool     `json:"single_branch"`
+	BotPatterns   []string `json:"bot_patterns"` // Email or name globs of bot accounts
 }
 
 type UpdateRepositoryRequest struct {
-	DefaultBranch string `json:"default_branch"`
+	DefaultBranch string   `json:"default_branch"` // Unchanged when empty
+	BranchMode    string   `json:"branch_mode"`    // Unchanged when empty
+	Branches      []string `json:"branches"`       // Unchanged when omitted
+	FirstParent   *bool    `json:"first_parent"`   // Unchanged when omitted
+	CloneDepth    *int     `json:"clone_depth"`    // Unchanged when omitted
+	CloneSince    *string  `json:"clone_since"`    // Unchanged when omitted, cleared when empty
+	SingleBranch  *bool    `json:"single_branch"`  // Unchanged when omitted
+	BotPatterns   []string `json:"bot_patterns"`   // Unchanged when omitted
+}
+
+// historyChanged reports whether the settings deciding which commits are indexed differ
+func historyChanged(a, b *database.Repository) bool {
+	return a.DefaultBranch != b.DefaultBranch ||
+		a.BranchMode != b.BranchMode ||
+		a.FirstParent != b.FirstParent ||
+		!slices.Equal(a.Branches, b.Branches) ||
+		a.CloneDepth != b.CloneDepth ||
+		a.SingleBranch != b.SingleBranch ||
+		!sameTime(a.CloneSince, b.CloneSince)
+}
+
+// sameTime reports whether two optional timestamps are equal
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// parseCloneSince parses a history cutoff given as an RFC 3339 timestamp or a date (UTC midnight).
+// An empty value means no cutoff.
+func parseCloneSince(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("must be an RFC 3339 timestamp or a YYYY-MM-DD date")
+}
+
+// validateBranchSelection checks a branch mode and its list of branches. A single-branch clone only
+// fetches the default branch, so it cannot index other branches.
+func validateBranchSelection(v *validation.Validator, mode database.BranchMode, branches []string, singleBranch bool) {
+	v.OneOf("branch_mode", string(mode), []string{
+		string(database.BranchModeDefault), string(database.BranchModeList), string(database.BranchModeAll),
+	})
+	if mode == database.BranchModeList && len(branches) == 0 {
+		v.Custom("branches", func() error {
+			return fmt.Errorf("at least one branch is required when branch_mode is list")
+		})
+	}
+	for _, branch := range branches {
+		v.Required("branches", branch).MaxLength("branches", branch, 255)
+	}
+	if singleBranch && mode != database.BranchModeDefault {
+		v.Custom("single_branch", func() error {
+			return fmt.Errorf("single_branch requires branch_mode default")
+		})
+	}
+}
+
+// validateBotPatterns checks the bot patterns of a repository
+func validateBotPatterns(v *validation.Validator, patterns []string) {
+	for _, pattern := range patterns {
+		v.Required("bot_patterns", pattern).MaxLength("bot_patterns", pattern, bots.MaxPatternLength)
+	}
 }
 
 // CreateRepository handles POST /api/v1/repositories
@@ -40,6 +118,16 @@ func (h *Handler) CreateRepository(w http.ResponseWriter, r *http.Request) {
 			MaxLength("default_branch", req.DefaultBranch, 255)
 	}
 
+	if req.BranchMode == "" {
+		req.BranchMode = string(database.BranchModeDefault)
+	}
+	validateBranchSelection(v, database.BranchMode(req.BranchMode), req.Branches, req.SingleBranch)
+
+	v.GreaterThanOrEqual("clone_depth", req.CloneDepth, 0)
+	cloneSince, err := parseCloneSince(req.CloneSince)
+	v.Custom("clone_since", func() error { return err })
+	validateBotPatterns(v, req.BotPatterns)
+
 	if err := v.Validate(); err != nil {
 		Error(w, err, http.StatusBadRequest)
 		return
@@ -60,6 +148,13 @@ func (h *Handler) CreateRepository(w http.ResponseWriter, r *http.Request) {
 	repo := &database.Repository{
 		URL:           req.URL,
 		DefaultBranch: req.DefaultBranch,
+		BranchMode:    database.BranchMode(req.BranchMode),
+		Branches:      req.Branches,
+		FirstParent:   req.FirstParent,
+		CloneDepth:    req.CloneDepth,
+		CloneSince:    cloneSince,
+		SingleBranch:  req.SingleBranch,
+		BotPatterns:   req.BotPatterns,
 		Status:        database.StatusPending,
 		UserID:        &user.ID,
 	}
@@ -112,7 +207,49 @@ func (h *Handler) UpdateRepository(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	repo.DefaultBranch = req.DefaultBranch
+	previous := *repo
+
+	if req.DefaultBranch != "" {
+		repo.DefaultBranch = req.DefaultBranch
+	}
+	if req.BranchMode != "" {
+		repo.BranchMode = database.BranchMode(req.BranchMode)
+	}
+	if req.Branches != nil {
+		repo.Branches = req.Branches
+	}
+	if req.FirstParent != nil {
+		repo.FirstParent = *req.FirstParent
+	}
+	if req.CloneDepth != nil {
+		repo.CloneDepth = *req.CloneDepth
+	}
+	if req.SingleBranch != nil {
+		repo.SingleBranch = *req.SingleBranch
+	}
+	if req.BotPatterns != nil {
+		repo.BotPatterns = req.BotPatterns
+	}
+
+	v = validation.New()
+	v.MaxLength("default_branch", repo.DefaultBranch, 255)
+	validateBranchSelection(v, repo.BranchMode, repo.Branches, repo.SingleBranch)
+	v.GreaterThanOrEqual("clone_depth", repo.CloneDepth, 0)
+	if req.CloneSince != nil {
+		cloneSince, err := parseCloneSince(*req.CloneSince)
+		v.Custom("clone_since", func() error { return err })
+		repo.CloneSince = cloneSince
+	}
+	validateBotPatterns(v, repo.BotPatterns)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	// The indexed history no longer matches the settings, so the next sync runs a full index
+	if historyChanged(&previous, repo) {
+		repo.LastIndexedCommit = nil
+	}
 
 	// Update repository
 	if err := h.db.UpdateRepository(ctx, repo); err != nil {
@@ -120,6 +257,14 @@ func (h *Handler) UpdateRepository(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Bot flags of the indexed contributors follow the patterns right away, without a re-index
+	if !slices.Equal(previous.BotPatterns, repo.BotPatterns) {
+		if _, err := h.db.FlagBotContributors(ctx, repo.ID, bots.NewDetector(repo.BotPatterns).IsBot); err != nil {
+			Error(w, err, http.StatusInternalServerError)
+			return
+		}
+	}
+
 	JSON(w, http.StatusOK, repo)
 }
 
@@ -190,9 +335,17 @@ func (h *Handler) GetRepositoryStatus(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Repositories that were never indexed have no progress yet
+	progress, err := h.db.GetIndexingProgress(ctx, id)
+	if err != nil && !validation.IsNotFound(err) {
+		Error(w, fmt.Errorf("failed to get indexing progress: %w", err), http.StatusInternalServerError)
+		return
+	}
+
 	JSON(w, http.StatusOK, map[string]interface{}{
 		"status":        status,
 		"repository_id": id,
+		"progress":      progress,
 	})
 }
 
@@ -283,6 +436,40 @@ func (h *Handler) IndexRepository(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// DeleteRepository handles DELETE /api/v1/repositories/{id}
+// The clone and indexed data are removed by the worker.
+func (h *Handler) DeleteRepository(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	if _, err := h.db.GetRepositoryForUser(ctx, id, user.ID); err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	if err := h.publisher.PublishDeleteJob(ctx, int(id)); err != nil {
+		Error(w, fmt.Errorf("failed to queue delete job: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusAccepted, map[string]interface{}{
+		"message":       "delete job queued successfully",
+		"repository_id": id,
+	})
+}
+
 // SyncRepository handles POST /api/v1/repositories/{id}/sync
 func (h *Handler) SyncRepository(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
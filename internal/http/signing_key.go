@@ -0,0 +1,182 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/signing"
+	"git-repository-visualizer/internal/validation"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// AddSigningKeyRequest represents the request body for trusting a signing key
+type AddSigningKeyRequest struct {
+	PublicKey string `json:"public_key"` // ASCII-armored PGP public key or SSH key in authorized_keys format
+}
+
+// ListSigningKeys handles GET /api/v1/repositories/{id}/signing-keys
+func (h *Handler) ListSigningKeys(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	if _, err := h.db.GetRepositoryForUser(ctx, id, user.ID); err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	keys, err := h.db.ListSigningKeys(ctx, id)
+	if err != nil {
+		parsedErr := validation.ParseDatabaseError(err)
+		Error(w, parsedErr, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"keys": keys,
+	})
+}
+
+// AddSigningKey handles POST /api/v1/repositories/{id}/signing-keys
+// Commits signed by the key count as verified once the repository is re-indexed, so the next
+// sync runs a full index.
+func (h *Handler) AddSigningKey(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	var req AddSigningKeyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		Error(w, fmt.Errorf("invalid request body"), http.StatusBadRequest)
+		return
+	}
+
+	var parsed *signing.Key
+	v := validation.New()
+	v.Required("public_key", req.PublicKey)
+	if req.PublicKey != "" {
+		v.Custom("public_key", func() error {
+			parsed, err = signing.ParseKey(req.PublicKey)
+			return err
+		})
+	}
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	repo, err := h.db.GetRepositoryForUser(ctx, id, user.ID)
+	if err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	key := &database.SigningKey{
+		RepositoryID: id,
+		Type:         parsed.Type,
+		PublicKey:    req.PublicKey,
+		Fingerprint:  parsed.Fingerprint,
+		Name:         parsed.Name,
+	}
+	if err := h.db.CreateSigningKey(ctx, key); err != nil {
+		parsedErr := validation.ParseDatabaseError(err)
+		Error(w, parsedErr, http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.reverifySignatures(r, repo); err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusCreated, key)
+}
+
+// DeleteSigningKey handles DELETE /api/v1/repositories/{id}/signing-keys/{keyID}
+func (h *Handler) DeleteSigningKey(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID"), http.StatusBadRequest)
+		return
+	}
+
+	keyID, err := strconv.ParseInt(chi.URLParam(r, "keyID"), 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid key ID"), http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	user := GetUserFromContext(ctx)
+	if user == nil {
+		Error(w, fmt.Errorf("unauthorized"), http.StatusUnauthorized)
+		return
+	}
+
+	// Verify repository exists and belongs to user
+	repo, err := h.db.GetRepositoryForUser(ctx, id, user.ID)
+	if err != nil {
+		Error(w, fmt.Errorf("repository not found"), http.StatusNotFound)
+		return
+	}
+
+	if err := h.db.DeleteSigningKey(ctx, id, keyID); err != nil {
+		if validation.IsNotFound(err) {
+			Error(w, fmt.Errorf("signing key not found"), http.StatusNotFound)
+			return
+		}
+		Error(w, fmt.Errorf("failed to delete signing key: %w", err), http.StatusInternalServerError)
+		return
+	}
+
+	if err := h.reverifySignatures(r, repo); err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"message":       "signing key deleted",
+		"repository_id": id,
+		"key_id":        keyID,
+	})
+}
+
+// reverifySignatures makes the next sync of a repository run a full index, so the signatures of
+// the indexed commits are checked against its changed keys
+func (h *Handler) reverifySignatures(r *http.Request, repo *database.Repository) error {
+	if repo.LastIndexedCommit == nil {
+		return nil
+	}
+	repo.LastIndexedCommit = nil
+	if err := h.db.UpdateRepository(r.Context(), repo); err != nil {
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+	return nil
+}
@@ -31,6 +31,7 @@ func (h *Handler) GetBusFactor(w http.ResponseWriter, r *http.Request) {
 		Threshold:       0.5,  // Default 50%
 		ActiveDays:      0,    // Default: all time
 		ExcludePatterns: true, // Default: exclude generated files
+		FollowRenames:   true, // Default: credit renamed files' history to their current path
 	}
 
 	// Parse threshold (0-1)
@@ -56,6 +57,27 @@ func (h *Handler) GetBusFactor(w http.ResponseWriter, r *http.Request) {
 		opts.ExcludePatterns = excludeStr != "false"
 	}
 
+	// Parse follow_renames (whether history under previous names counts)
+	if followStr := r.URL.Query().Get("follow_renames"); followStr != "" {
+		opts.FollowRenames = followStr != "false"
+	}
+
+	opts.Branch = r.URL.Query().Get("branch")
+	opts.IncludeCoAuthors = r.URL.Query().Get("include_coauthors") == "true"
+	opts.ExcludeBots = r.URL.Query().Get("exclude_bots") != "false"
+
+	// Parse ownership source (lines added over history, or surviving lines from blame)
+	opts.Source = stats.OwnershipSourceCommits
+	if sourceStr := r.URL.Query().Get("source"); sourceStr != "" {
+		v := validation.New()
+		v.OneOf("source", sourceStr, []string{stats.OwnershipSourceCommits, stats.OwnershipSourceBlame})
+		if err := v.Validate(); err != nil {
+			Error(w, err, http.StatusBadRequest)
+			return
+		}
+		opts.Source = sourceStr
+	}
+
 	ctx := r.Context()
 	result, err := stats.CalculateBusFactor(ctx, h.db.Pool(), repoID, opts)
 	if err != nil {
@@ -67,7 +89,7 @@ func (h *Handler) GetBusFactor(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, result)
 }
 
-// GetChurnStats returns the high churn files for a repository
+// GetChurnStats returns the high churn files, or Go functions with granularity=function, for a repository
 func (h *Handler) GetChurnStats(w http.ResponseWriter, r *http.Request) {
 	repoIDStr := chi.URLParam(r, "repoID")
 	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
@@ -76,8 +98,14 @@ func (h *Handler) GetChurnStats(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	granularity := r.URL.Query().Get("granularity")
+	if granularity == "" {
+		granularity = stats.ChurnGranularityFile
+	}
+
 	v := validation.New()
 	v.GreaterThan("repoID", int(repoID), 0)
+	v.OneOf("granularity", granularity, []string{stats.ChurnGranularityFile, stats.ChurnGranularityFunction})
 	if err := v.Validate(); err != nil {
 		Error(w, err, http.StatusBadRequest)
 		return
@@ -85,8 +113,11 @@ func (h *Handler) GetChurnStats(w http.ResponseWriter, r *http.Request) {
 
 	// Parse query parameters
 	opts := stats.ChurnOptions{
-		Limit: 10, // Default
-		Days:  0,  // Default: all time
+		Limit:            10,   // Default
+		Days:             0,    // Default: all time
+		FollowRenames:    true, // Default: merge history of renamed files
+		IncludeMerges:    true, // Default: merges only carry changes in first-parent mode
+		ExcludeNonSource: true, // Default: leave out vendored, generated and documentation files
 	}
 
 	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
@@ -101,7 +132,33 @@ func (h *Handler) GetChurnStats(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if followStr := r.URL.Query().Get("follow_renames"); followStr != "" {
+		opts.FollowRenames = followStr != "false"
+	}
+
+	opts.Branch = r.URL.Query().Get("branch")
+
+	if mergesStr := r.URL.Query().Get("include_merges"); mergesStr != "" {
+		opts.IncludeMerges = mergesStr != "false"
+	}
+
+	if excludeStr := r.URL.Query().Get("exclude"); excludeStr != "" {
+		opts.ExcludeNonSource = excludeStr != "false"
+	}
+
+	opts.ExcludeBots = r.URL.Query().Get("exclude_bots") != "false"
+
 	ctx := r.Context()
+	if granularity == stats.ChurnGranularityFunction {
+		functions, err := stats.GetHighChurnFunctions(ctx, h.db.Pool(), repoID, opts)
+		if err != nil {
+			Error(w, err, http.StatusInternalServerError)
+			return
+		}
+		JSON(w, http.StatusOK, functions)
+		return
+	}
+
 	result, err := stats.GetHighChurnFiles(ctx, h.db.Pool(), repoID, opts)
 	if err != nil {
 		Error(w, err, http.StatusInternalServerError)
@@ -111,6 +168,306 @@ func (h *Handler) GetChurnStats(w http.ResponseWriter, r *http.Request) {
 	JSON(w, http.StatusOK, result)
 }
 
+// GetOwnership returns the blame based "who wrote the current code" matrix for a repository
+func (h *Handler) GetOwnership(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	limit, offset := h.GetLimitOffset(r)
+	opts := stats.OwnershipOptions{
+		Limit:       limit,
+		Offset:      offset,
+		PathPrefix:  r.URL.Query().Get("path"),
+		ExcludeBots: r.URL.Query().Get("exclude_bots") != "false",
+	}
+
+	ctx := r.Context()
+	result, err := stats.GetOwnership(ctx, h.db.Pool(), repoID, opts)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// GetLanguages returns the code, comment and blank lines per language at HEAD
+func (h *Handler) GetLanguages(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	opts := stats.LanguageOptions{
+		ExcludeNonSource: r.URL.Query().Get("exclude") != "false", // Default: only source code
+	}
+
+	ctx := r.Context()
+	result, err := stats.GetLanguageDistribution(ctx, h.db.Pool(), repoID, opts)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// GetGrowth returns the sampled codebase size over time
+func (h *Handler) GetGrowth(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	opts := stats.GrowthOptions{
+		Language: r.URL.Query().Get("language"),
+	}
+
+	ctx := r.Context()
+	points, err := stats.GetGrowth(ctx, h.db.Pool(), repoID, opts)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"growth": points,
+	})
+}
+
+// GetCommitTypes returns the mix of commit types overall, over time, per contributor and per scope
+func (h *Handler) GetCommitTypes(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = stats.CommitTypeIntervalMonth
+	}
+
+	days := 0
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		if parsed, err := strconv.Atoi(daysStr); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	v.OneOf("interval", interval, []string{stats.CommitTypeIntervalWeek, stats.CommitTypeIntervalMonth})
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	limit, offset := h.GetLimitOffset(r)
+	opts := stats.CommitTypeOptions{
+		Interval:      interval,
+		Days:          days,
+		Branch:        r.URL.Query().Get("branch"),
+		IncludeMerges: r.URL.Query().Get("include_merges") == "true", // Default: the merged commits are already counted
+		Limit:         limit,
+		Offset:        offset,
+		ExcludeBots:   r.URL.Query().Get("exclude_bots") != "false",
+	}
+
+	ctx := r.Context()
+	result, err := stats.GetCommitTypes(ctx, h.db.Pool(), repoID, opts)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// GetSigning returns the share of signed and verified commits overall, over time and per contributor
+func (h *Handler) GetSigning(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	interval := r.URL.Query().Get("interval")
+	if interval == "" {
+		interval = stats.SigningIntervalMonth
+	}
+
+	days := 0
+	if daysStr := r.URL.Query().Get("days"); daysStr != "" {
+		if parsed, err := strconv.Atoi(daysStr); err == nil && parsed > 0 {
+			days = parsed
+		}
+	}
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	v.OneOf("interval", interval, []string{stats.SigningIntervalWeek, stats.SigningIntervalMonth})
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	limit, offset := h.GetLimitOffset(r)
+	opts := stats.SigningOptions{
+		Interval:      interval,
+		Days:          days,
+		Branch:        r.URL.Query().Get("branch"),
+		IncludeMerges: r.URL.Query().Get("include_merges") != "false",
+		Limit:         limit,
+		Offset:        offset,
+		ExcludeBots:   r.URL.Query().Get("exclude_bots") != "false",
+	}
+
+	ctx := r.Context()
+	result, err := stats.GetSigningCoverage(ctx, h.db.Pool(), repoID, opts)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, result)
+}
+
+// GetReleases returns the commits, contributors and line changes of every release, newest first
+func (h *Handler) GetReleases(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	limit, offset := h.GetLimitOffset(r)
+	opts := stats.ReleaseOptions{
+		Limit:         limit,
+		Offset:        offset,
+		IncludeMerges: r.URL.Query().Get("include_merges") != "false",
+		ExcludeBots:   r.URL.Query().Get("exclude_bots") != "false",
+	}
+
+	ctx := r.Context()
+	releases, err := stats.GetReleases(ctx, h.db.Pool(), repoID, opts)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"releases": releases,
+	})
+}
+
+// GetFileHistory returns the commits that touched a file, following it across renames
+func (h *Handler) GetFileHistory(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	path := r.URL.Query().Get("path")
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	v.Required("path", path)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	limit := 100
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	ctx := r.Context()
+	history, err := stats.GetFileHistory(ctx, h.db.Pool(), repoID, path, r.URL.Query().Get("branch"),
+		r.URL.Query().Get("exclude_bots") != "false", limit)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"path":    path,
+		"history": history,
+	})
+}
+
+// ListBranches returns the indexed branches of a repository, valid values for the branch parameter
+func (h *Handler) ListBranches(w http.ResponseWriter, r *http.Request) {
+	repoIDStr := chi.URLParam(r, "repoID")
+	repoID, err := strconv.ParseInt(repoIDStr, 10, 64)
+	if err != nil {
+		Error(w, fmt.Errorf("invalid repository ID: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	v := validation.New()
+	v.GreaterThan("repoID", int(repoID), 0)
+	if err := v.Validate(); err != nil {
+		Error(w, err, http.StatusBadRequest)
+		return
+	}
+
+	ctx := r.Context()
+	branches, err := h.db.GetBranchesByRepository(ctx, repoID)
+	if err != nil {
+		Error(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	JSON(w, http.StatusOK, map[string]interface{}{
+		"branches": branches,
+	})
+}
+
 // GetCommitActivity returns daily commit counts
 func (h *Handler) GetCommitActivity(w http.ResponseWriter, r *http.Request) {
     repoIDStr := chi.URLParam(r, "repoID")
@@ -134,8 +491,17 @@ func (h *Handler) GetCommitActivity(w http.ResponseWriter, r *http.Request) {
         }
     }
 
+    opts := stats.ActivityOptions{
+        Days:             days,
+        Branch:           r.URL.Query().Get("branch"),
+        IncludeMerges:    r.URL.Query().Get("include_merges") != "false",
+        Author:           r.URL.Query().Get("author"),
+        IncludeCoAuthors: r.URL.Query().Get("include_coauthors") == "true",
+        ExcludeBots:      r.URL.Query().Get("exclude_bots") != "false",
+    }
+
     ctx := r.Context()
-    activity, err := stats.GetCommitActivity(ctx, h.db.Pool(), repoID, days)
+    activity, err := stats.GetCommitActivity(ctx, h.db.Pool(), repoID, opts)
     if err != nil {
         Error(w, err, http.StatusInternalServerError)
         return
@@ -0,0 +1,188 @@
+package language
+
+import (
+	"path"
+	"strings"
+
+	"git-repository-visualizer/internal/config"
+)
+
+// Classification is what the detector knows about a file
+type Classification struct {
+	Language      string
+	Vendored      bool // Third-party code checked into the repository
+	Generated     bool // Produced by a tool (lock files, protobuf stubs, minified bundles)
+	Documentation bool // Prose rather than code
+}
+
+// Detector maps file paths to canonical language names.
+// Lookup order: exact file name, extension, then the shebang of the first line.
+type Detector struct {
+	extensions   map[string]string
+	filenames    map[string]string
+	interpreters map[string]string
+}
+
+// NewDetector creates a detector with the built-in tables extended (or overridden) by the configuration
+func NewDetector(cfg config.LanguageConfig) *Detector {
+	d := Default()
+	for ext, lang := range cfg.Extensions {
+		if !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		d.extensions[strings.ToLower(ext)] = lang
+	}
+	for name, lang := range cfg.Filenames {
+		d.filenames[name] = lang
+	}
+	return d
+}
+
+// Default creates a detector with only the built-in tables
+func Default() *Detector {
+	d := &Detector{
+		extensions:   make(map[string]string, len(extensions)),
+		filenames:    make(map[string]string, len(filenames)),
+		interpreters: interpreters,
+	}
+	for k, v := range extensions {
+		d.extensions[k] = v
+	}
+	for k, v := range filenames {
+		d.filenames[k] = v
+	}
+	return d
+}
+
+// Detect classifies the file at filePath. firstLine is the file's first line, used for
+// shebangs and generated-code headers; it may be empty.
+func (d *Detector) Detect(filePath, firstLine string) Classification {
+	lang := d.Language(filePath, firstLine)
+	return Classification{
+		Language:      lang,
+		Vendored:      isVendored(filePath),
+		Generated:     isGenerated(filePath, firstLine),
+		Documentation: isDocumentation(filePath, lang),
+	}
+}
+
+// Language returns the canonical language of the file, or PlainText
+func (d *Detector) Language(filePath, firstLine string) string {
+	base := path.Base(filePath)
+
+	if lang, ok := d.filenames[base]; ok {
+		return lang
+	}
+
+	// Dockerfile.dev, Makefile.common and the like
+	if stem, _, ok := strings.Cut(base, "."); ok && stem != "" {
+		if lang, ok := d.filenames[stem]; ok && (lang == "Dockerfile" || lang == "Makefile") {
+			return lang
+		}
+	}
+
+	// Dotfiles such as ".gitignore" are their own extension
+	if ext := path.Ext(base); ext != "" {
+		if lang, ok := d.extensions[strings.ToLower(ext)]; ok {
+			return lang
+		}
+	}
+
+	if lang := d.shebang(firstLine); lang != "" {
+		return lang
+	}
+	return PlainText
+}
+
+// shebang returns the language of a "#!" interpreter line, e.g. "#!/usr/bin/env python3"
+func (d *Detector) shebang(line string) string {
+	if !strings.HasPrefix(line, "#!") {
+		return ""
+	}
+	fields := strings.Fields(strings.TrimPrefix(line, "#!"))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := path.Base(fields[0])
+	if interpreter == "env" {
+		// Skip env flags such as "-S"
+		interpreter = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				interpreter = path.Base(f)
+				break
+			}
+		}
+	}
+
+	if lang, ok := d.interpreters[interpreter]; ok {
+		return lang
+	}
+	// Versioned interpreters, e.g. "python3.12" or "ruby2.7"
+	if lang, ok := d.interpreters[strings.TrimRight(interpreter, "0123456789.")]; ok {
+		return lang
+	}
+	return ""
+}
+
+// isVendored reports whether the path is inside a third-party directory
+func isVendored(filePath string) bool {
+	dirs := strings.Split(path.Dir(filePath), "/")
+	for _, dir := range dirs {
+		for _, vendored := range vendoredDirs {
+			if dir == vendored {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isGenerated reports whether the file was produced by a tool, by name or by header
+func isGenerated(filePath, firstLine string) bool {
+	base := path.Base(filePath)
+	for _, name := range generatedFilenames {
+		if base == name {
+			return true
+		}
+	}
+	for _, suffix := range generatedSuffixes {
+		if strings.HasSuffix(base, suffix) {
+			return true
+		}
+	}
+	for _, marker := range generatedMarkers {
+		if strings.Contains(firstLine, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// isDocumentation reports whether the file is prose: docs directories, README-like files and markup
+func isDocumentation(filePath, lang string) bool {
+	if documentationLanguages[lang] {
+		return true
+	}
+
+	// README, LICENSE.txt and the like, but not code such as history.go
+	if lang == PlainText {
+		stem, _, _ := strings.Cut(strings.ToLower(path.Base(filePath)), ".")
+		for _, name := range documentationNames {
+			if stem == name {
+				return true
+			}
+		}
+	}
+
+	first, _, nested := strings.Cut(filePath, "/")
+	if nested {
+		for _, dir := range documentationDirs {
+			if strings.EqualFold(first, dir) {
+				return true
+			}
+		}
+	}
+	return false
+}
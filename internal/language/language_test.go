@@ -0,0 +1,64 @@
+package language
+
+import (
+	"testing"
+
+	"git-repository-visualizer/internal/config"
+)
+
+func TestDetect(t *testing.T) {
+	d := Default()
+
+	tests := []struct {
+		path      string
+		firstLine string
+		expected  Classification
+	}{
+		{"main.go", "package main", Classification{Language: "Go"}},
+		{"web/src/App.tsx", "", Classification{Language: "TypeScript"}},
+		{"Makefile", "build:", Classification{Language: "Makefile"}},
+		{"deploy/Dockerfile.dev", "FROM golang", Classification{Language: "Dockerfile"}},
+		{"scripts/release", "#!/usr/bin/env python3", Classification{Language: "Python"}},
+		{"bin/setup", "#!/bin/bash -e", Classification{Language: "Shell"}},
+		{"internal/history.go", "package internal", Classification{Language: "Go"}},
+		{"README.md", "# Project", Classification{Language: "Markdown", Documentation: true}},
+		{"LICENSE", "MIT License", Classification{Language: PlainText, Documentation: true}},
+		{"docs/openapi.yaml", "openapi: 3.0.0", Classification{Language: "YAML", Documentation: true}},
+		{"vendor/github.com/pkg/errors/errors.go", "package errors", Classification{Language: "Go", Vendored: true}},
+		{"api/v1/service.pb.go", "// Code generated by protoc-gen-go. DO NOT EDIT.", Classification{Language: "Go", Generated: true}},
+		{"internal/mocks/store.go", "// Code generated by mockery. DO NOT EDIT.", Classification{Language: "Go", Generated: true}},
+		{"package-lock.json", "{", Classification{Language: "JSON", Generated: true}},
+		{"notes", "", Classification{Language: PlainText}},
+	}
+
+	for _, tt := range tests {
+		got := d.Detect(tt.path, tt.firstLine)
+		if got != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.path, tt.expected, got)
+		}
+	}
+}
+
+func TestNewDetectorWithConfig(t *testing.T) {
+	d := NewDetector(config.LanguageConfig{
+		Extensions: map[string]string{"tpl": "Smarty", ".TS": "TypeScript Declarations"},
+		Filenames:  map[string]string{"Justfile": "Just"},
+	})
+
+	tests := map[string]string{
+		"templates/page.tpl": "Smarty",
+		"src/index.ts":       "TypeScript Declarations",
+		"Justfile":           "Just",
+		"main.go":            "Go",
+	}
+	for path, expected := range tests {
+		if got := d.Language(path, ""); got != expected {
+			t.Errorf("%s: expected %s, got %s", path, expected, got)
+		}
+	}
+
+	// The built-in tables are not modified
+	if got := Default().Language("src/index.ts", ""); got != "TypeScript" {
+		t.Errorf("expected built-in TypeScript, got %s", got)
+	}
+}
@@ -0,0 +1,196 @@
+package language
+
+import "strings"
+
+// LineCounts splits a file's lines into code, comments and blank lines
+type LineCounts struct {
+	Code    int
+	Comment int
+	Blank   int
+}
+
+// Total returns the number of lines counted
+func (c LineCounts) Total() int {
+	return c.Code + c.Comment + c.Blank
+}
+
+// commentSyntax describes how a language writes comments
+type commentSyntax struct {
+	line       []string // Line comment markers, e.g. "//"
+	blockStart string   // Block comment opener, e.g. "/*" (empty = none)
+	blockEnd   string
+}
+
+var (
+	cStyle       = commentSyntax{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashStyle    = commentSyntax{line: []string{"#"}}
+	dashStyle    = commentSyntax{line: []string{"--"}}
+	semiStyle    = commentSyntax{line: []string{";"}}
+	xmlStyle     = commentSyntax{blockStart: "<!--", blockEnd: "-->"}
+	cssStyle     = commentSyntax{blockStart: "/*", blockEnd: "*/"}
+	percentStyle = commentSyntax{line: []string{"%"}}
+)
+
+// commentSyntaxes maps canonical language names to their comment syntax.
+// Languages missing here (JSON, CSV, Plain Text, ...) have no comments.
+var commentSyntaxes = map[string]commentSyntax{
+	"Go":               cStyle,
+	"C":                cStyle,
+	"C++":              cStyle,
+	"C#":               cStyle,
+	"Java":             cStyle,
+	"Kotlin":           cStyle,
+	"Scala":            cStyle,
+	"Groovy":           cStyle,
+	"Rust":             cStyle,
+	"Swift":            cStyle,
+	"Objective-C":      cStyle,
+	"Objective-C++":    cStyle,
+	"Dart":             cStyle,
+	"JavaScript":       cStyle,
+	"TypeScript":       cStyle,
+	"Vue":              cStyle,
+	"Svelte":           cStyle,
+	"PHP":              {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"},
+	"Protocol Buffers": cStyle,
+	"Thrift":           cStyle,
+	"Solidity":         cStyle,
+	"Zig":              {line: []string{"//"}},
+	"D":                cStyle,
+	"Verilog":          cStyle,
+	"CoffeeScript":     {line: []string{"#"}, blockStart: "###", blockEnd: "###"},
+	"Crystal":          hashStyle,
+	"CSS":              cssStyle,
+	"SCSS":             cStyle,
+	"Less":             cStyle,
+	"Sass":             cStyle,
+	"HCL":              {line: []string{"#", "//"}, blockStart: "/*", blockEnd: "*/"},
+	"Python":           {line: []string{"#"}, blockStart: `"""`, blockEnd: `"""`},
+	"Ruby":             {line: []string{"#"}, blockStart: "=begin", blockEnd: "=end"},
+	"Perl":             {line: []string{"#"}, blockStart: "=pod", blockEnd: "=cut"},
+	"Shell":            hashStyle,
+	"Fish":             hashStyle,
+	"PowerShell":       {line: []string{"#"}, blockStart: "<#", blockEnd: "#>"},
+	"R":                hashStyle,
+	"Julia":            {line: []string{"#"}, blockStart: "#=", blockEnd: "=#"},
+	"Elixir":           hashStyle,
+	"Nim":              hashStyle,
+	"Nix":              {line: []string{"#"}, blockStart: "/*", blockEnd: "*/"},
+	"Makefile":         hashStyle,
+	"Dockerfile":       hashStyle,
+	"CMake":            hashStyle,
+	"YAML":             hashStyle,
+	"TOML":             hashStyle,
+	"Starlark":         hashStyle,
+	"Dotenv":           hashStyle,
+	"Ignore List":      hashStyle,
+	"Git Attributes":   hashStyle,
+	"CODEOWNERS":       hashStyle,
+	"Procfile":         hashStyle,
+	"Tcl":              hashStyle,
+	"Awk":              hashStyle,
+	"INI":              {line: []string{";", "#"}},
+	"SQL":              {line: []string{"--"}, blockStart: "/*", blockEnd: "*/"},
+	"Lua":              {line: []string{"--"}, blockStart: "--[[", blockEnd: "]]"},
+	"Haskell":          {line: []string{"--"}, blockStart: "{-", blockEnd: "-}"},
+	"Elm":              {line: []string{"--"}, blockStart: "{-", blockEnd: "-}"},
+	"PureScript":       {line: []string{"--"}, blockStart: "{-", blockEnd: "-}"},
+	"Ada":              dashStyle,
+	"VHDL":             dashStyle,
+	"OCaml":            {blockStart: "(*", blockEnd: "*)"},
+	"F#":               {line: []string{"//"}, blockStart: "(*", blockEnd: "*)"},
+	"Reason":           cStyle,
+	"Pascal":           {line: []string{"//"}, blockStart: "{", blockEnd: "}"},
+	"Erlang":           percentStyle,
+	"TeX":              percentStyle,
+	"Clojure":          semiStyle,
+	"Common Lisp":      {line: []string{";"}, blockStart: "#|", blockEnd: "|#"},
+	"Emacs Lisp":       semiStyle,
+	"Scheme":           semiStyle,
+	"Racket":           semiStyle,
+	"Assembly":         {line: []string{";", "#"}},
+	"Batchfile":        {line: []string{"REM ", "rem ", "::"}},
+	"Vim Script":       {line: []string{`"`}},
+	"Fortran":          {line: []string{"!"}},
+	"HTML":             xmlStyle,
+	"XML":              xmlStyle,
+	"SVG":              xmlStyle,
+	"Markdown":         xmlStyle,
+	"Razor":            {blockStart: "@*", blockEnd: "*@"},
+	"Handlebars":       {blockStart: "{{!", blockEnd: "}}"},
+	"Go Template":      {blockStart: "{{/*", blockEnd: "*/}}"},
+	"ERB":              xmlStyle,
+	"Twig":             {blockStart: "{#", blockEnd: "#}"},
+	"GraphQL":          hashStyle,
+}
+
+// LineCounter classifies the lines of one file, fed in order.
+// Like cloc it works line by line and does not parse strings, so comment markers inside
+// string literals can be misclassified.
+type LineCounter struct {
+	syntax  commentSyntax
+	inBlock bool
+	counts  LineCounts
+}
+
+// NewLineCounter creates a counter using the comment syntax of the language
+func NewLineCounter(lang string) *LineCounter {
+	return &LineCounter{syntax: commentSyntaxes[lang]}
+}
+
+// Add classifies the next line of the file
+func (c *LineCounter) Add(line string) {
+	trimmed := strings.TrimSpace(line)
+	s := c.syntax
+
+	switch {
+	case trimmed == "":
+		c.counts.Blank++
+	case c.inBlock:
+		c.counts.Comment++
+		if strings.Contains(trimmed, s.blockEnd) {
+			c.inBlock = false
+		}
+	case s.blockStart != "" && strings.HasPrefix(trimmed, s.blockStart):
+		// Checked before line comments since some openers start with one, e.g. Lua's "--[["
+		c.counts.Comment++
+		rest := trimmed[len(s.blockStart):]
+		c.inBlock = !strings.Contains(rest, s.blockEnd)
+	case c.isLineComment(trimmed):
+		c.counts.Comment++
+	default:
+		c.counts.Code++
+		// Code followed by a block comment that continues on the next lines, e.g. "x := 1 /* note"
+		// (skipped when opener and closer are equal, as for Python docstrings, since strings are not parsed)
+		if s.blockStart != "" && s.blockStart != s.blockEnd {
+			if i := strings.LastIndex(trimmed, s.blockStart); i >= 0 && !insideQuotes(trimmed[:i]) {
+				c.inBlock = !strings.Contains(trimmed[i+len(s.blockStart):], s.blockEnd)
+			}
+		}
+	}
+}
+
+// Counts returns the classification of the lines added so far
+func (c *LineCounter) Counts() LineCounts {
+	return c.counts
+}
+
+func (c *LineCounter) isLineComment(trimmed string) bool {
+	for _, marker := range c.syntax.line {
+		if strings.HasPrefix(trimmed, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// insideQuotes reports whether the end of code falls inside a string literal, e.g. `glob := "src/*`,
+// by counting unescaped quote characters
+func insideQuotes(code string) bool {
+	for _, q := range []string{`"`, "'", "`"} {
+		if (strings.Count(code, q)-strings.Count(code, `\`+q))%2 == 1 {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package language
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLineCounter(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		source   string
+		expected LineCounts
+	}{
+		{
+			name:     "go",
+			language: "Go",
+			source: `// Package main does things
+package main
+
+/*
+ * Block comment
+ */
+func main() {
+	glob := "src/**/*.go"
+	x := 1 /* trailing
+	still a comment */
+	_ = glob // inline comments count as code
+}
+`,
+			expected: LineCounts{Code: 6, Comment: 5, Blank: 1},
+		},
+		{
+			name:     "python docstring",
+			language: "Python",
+			source: `#!/usr/bin/env python3
+"""Module docstring.
+
+More details.
+"""
+
+def f():
+    """One line docstring."""
+    return 1
+`,
+			expected: LineCounts{Code: 2, Comment: 5, Blank: 2},
+		},
+		{
+			name:     "lua block opener starting with a line marker",
+			language: "Lua",
+			source:   "--[[\nblock\n]]\nprint(1)\n",
+			expected: LineCounts{Code: 1, Comment: 3},
+		},
+		{
+			name:     "no comment syntax",
+			language: "JSON",
+			source:   "{\n  \"a\": \"// not a comment\"\n\n}\n",
+			expected: LineCounts{Code: 3, Blank: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		c := NewLineCounter(tt.language)
+		for _, line := range strings.Split(strings.TrimSuffix(tt.source, "\n"), "\n") {
+			c.Add(line)
+		}
+		if got := c.Counts(); got != tt.expected {
+			t.Errorf("%s: expected %+v, got %+v", tt.name, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package language
+
+// PlainText is reported for files no rule recognises
+const PlainText = "Plain Text"
+
+// extensions maps lower-case file extensions to canonical language names
+var extensions = map[string]string{
+	".go":           "Go",
+	".c":            "C",
+	".h":            "C",
+	".cc":           "C++",
+	".cpp":          "C++",
+	".cxx":          "C++",
+	".hh":           "C++",
+	".hpp":          "C++",
+	".hxx":          "C++",
+	".cs":           "C#",
+	".java":         "Java",
+	".kt":           "Kotlin",
+	".kts":          "Kotlin",
+	".scala":        "Scala",
+	".groovy":       "Groovy",
+	".gradle":       "Groovy",
+	".clj":          "Clojure",
+	".cljs":         "Clojure",
+	".rs":           "Rust",
+	".swift":        "Swift",
+	".m":            "Objective-C",
+	".mm":           "Objective-C++",
+	".dart":         "Dart",
+	".js":           "JavaScript",
+	".mjs":          "JavaScript",
+	".cjs":          "JavaScript",
+	".jsx":          "JavaScript",
+	".ts":           "TypeScript",
+	".mts":          "TypeScript",
+	".cts":          "TypeScript",
+	".tsx":          "TypeScript",
+	".vue":          "Vue",
+	".svelte":       "Svelte",
+	".py":           "Python",
+	".pyi":          "Python",
+	".rb":           "Ruby",
+	".php":          "PHP",
+	".pl":           "Perl",
+	".pm":           "Perl",
+	".lua":          "Lua",
+	".r":            "R",
+	".jl":           "Julia",
+	".ex":           "Elixir",
+	".exs":          "Elixir",
+	".erl":          "Erlang",
+	".hrl":          "Erlang",
+	".hs":           "Haskell",
+	".ml":           "OCaml",
+	".mli":          "OCaml",
+	".fs":           "F#",
+	".fsx":          "F#",
+	".elm":          "Elm",
+	".zig":          "Zig",
+	".nim":          "Nim",
+	".sh":           "Shell",
+	".bash":         "Shell",
+	".zsh":          "Shell",
+	".fish":         "Fish",
+	".ps1":          "PowerShell",
+	".bat":          "Batchfile",
+	".cmd":          "Batchfile",
+	".sql":          "SQL",
+	".html":         "HTML",
+	".htm":          "HTML",
+	".css":          "CSS",
+	".scss":         "SCSS",
+	".sass":         "Sass",
+	".less":         "Less",
+	".json":         "JSON",
+	".yaml":         "YAML",
+	".yml":          "YAML",
+	".toml":         "TOML",
+	".xml":          "XML",
+	".ini":          "INI",
+	".proto":        "Protocol Buffers",
+	".graphql":      "GraphQL",
+	".gql":          "GraphQL",
+	".tf":           "HCL",
+	".hcl":          "HCL",
+	".md":           "Markdown",
+	".markdown":     "Markdown",
+	".rst":          "reStructuredText",
+	".adoc":         "AsciiDoc",
+	".tex":          "TeX",
+	".txt":          PlainText,
+	".dockerfile":   "Dockerfile",
+	".mk":           "Makefile",
+	".cmake":        "CMake",
+	".nix":          "Nix",
+	".ipynb":        "Jupyter Notebook",
+	".sol":          "Solidity",
+	".vim":          "Vim Script",
+	".asm":          "Assembly",
+	".s":            "Assembly",
+	".csv":          "CSV",
+	".svg":          "SVG",
+	".gohtml":       "Go Template",
+	".tmpl":         "Go Template",
+	".handlebars":   "Handlebars",
+	".hbs":          "Handlebars",
+	".erb":          "ERB",
+	".twig":         "Twig",
+	".cshtml":       "Razor",
+	".razor":        "Razor",
+	".pas":          "Pascal",
+	".f90":          "Fortran",
+	".cob":          "COBOL",
+	".v":            "Verilog",
+	".vhd":          "VHDL",
+	".coffee":       "CoffeeScript",
+	".purs":         "PureScript",
+	".re":           "Reason",
+	".cr":           "Crystal",
+	".d":            "D",
+	".ada":          "Ada",
+	".lisp":         "Common Lisp",
+	".el":           "Emacs Lisp",
+	".scm":          "Scheme",
+	".rkt":          "Racket",
+	".bzl":          "Starlark",
+	".star":         "Starlark",
+	".thrift":       "Thrift",
+	".avsc":         "JSON",
+	".properties":   "INI",
+	".env":          "Dotenv",
+	".lock":         "Lockfile",
+	".gitignore":    "Ignore List",
+	".dockerignore": "Ignore List",
+}
+
+// filenames maps exact file names (without directories) to canonical language names
+var filenames = map[string]string{
+	"Makefile":       "Makefile",
+	"GNUmakefile":    "Makefile",
+	"makefile":       "Makefile",
+	"Dockerfile":     "Dockerfile",
+	"Containerfile":  "Dockerfile",
+	"CMakeLists.txt": "CMake",
+	"Rakefile":       "Ruby",
+	"Gemfile":        "Ruby",
+	"Podfile":        "Ruby",
+	"Vagrantfile":    "Ruby",
+	"Jenkinsfile":    "Groovy",
+	"BUILD":          "Starlark",
+	"BUILD.bazel":    "Starlark",
+	"WORKSPACE":      "Starlark",
+	"Tiltfile":       "Starlark",
+	"go.mod":         "Go Module",
+	"go.sum":         "Go Checksums",
+	"go.work":        "Go Module",
+	"Cargo.lock":     "Lockfile",
+	"Gemfile.lock":   "Lockfile",
+	"yarn.lock":      "Lockfile",
+	"Pipfile":        "TOML",
+	"Procfile":       "Procfile",
+	".bashrc":        "Shell",
+	".zshrc":         "Shell",
+	".profile":       "Shell",
+	".editorconfig":  "INI",
+	".gitattributes": "Git Attributes",
+	".gitmodules":    "INI",
+	"LICENSE":        PlainText,
+	"COPYING":        PlainText,
+	"AUTHORS":        PlainText,
+	"CODEOWNERS":     "CODEOWNERS",
+}
+
+// interpreters maps shebang interpreters to canonical language names
+var interpreters = map[string]string{
+	"sh":      "Shell",
+	"bash":    "Shell",
+	"zsh":     "Shell",
+	"dash":    "Shell",
+	"ksh":     "Shell",
+	"fish":    "Fish",
+	"python":  "Python",
+	"python2": "Python",
+	"python3": "Python",
+	"ruby":    "Ruby",
+	"perl":    "Perl",
+	"php":     "PHP",
+	"node":    "JavaScript",
+	"deno":    "TypeScript",
+	"ts-node": "TypeScript",
+	"lua":     "Lua",
+	"Rscript": "R",
+	"tclsh":   "Tcl",
+	"awk":     "Awk",
+	"gawk":    "Awk",
+	"pwsh":    "PowerShell",
+}
+
+// vendoredDirs are directories holding third-party code
+var vendoredDirs = []string{
+	"vendor",
+	"node_modules",
+	"bower_components",
+	"third_party",
+	"thirdparty",
+	"3rdparty",
+	"Godeps",
+	"Pods",
+	"Carthage",
+	".yarn",
+}
+
+// generatedSuffixes identify files produced by tools
+var generatedSuffixes = []string{
+	".pb.go",
+	".pb.gw.go",
+	"_pb2.py",
+	"_pb2_grpc.py",
+	".pb.cc",
+	".pb.h",
+	"_generated.go",
+	".gen.go",
+	"_gen.go",
+	".min.js",
+	".min.css",
+	".bundle.js",
+	".js.map",
+	".css.map",
+	".designer.cs",
+	".g.dart",
+	".freezed.dart",
+}
+
+// generatedFilenames are lock files and other tool output
+var generatedFilenames = []string{
+	"package-lock.json",
+	"yarn.lock",
+	"pnpm-lock.yaml",
+	"Gemfile.lock",
+	"Pipfile.lock",
+	"poetry.lock",
+	"Cargo.lock",
+	"composer.lock",
+	"go.sum",
+}
+
+// generatedMarkers appear in the header of generated files
+var generatedMarkers = []string{
+	"Code generated",
+	"DO NOT EDIT",
+	"@generated",
+	"<auto-generated",
+	"Autogenerated by",
+	"This file was automatically generated",
+}
+
+// documentationDirs hold documentation rather than code
+var documentationDirs = []string{
+	"docs",
+	"doc",
+	"documentation",
+	"man",
+}
+
+// documentationNames are well-known documentation file names without extension, matched case-insensitively
+var documentationNames = []string{
+	"readme",
+	"changelog",
+	"changes",
+	"history",
+	"contributing",
+	"code_of_conduct",
+	"license",
+	"licence",
+	"copying",
+	"authors",
+	"security",
+	"notice",
+}
+
+// documentationLanguages are prose formats
+var documentationLanguages = map[string]bool{
+	"Markdown":         true,
+	"reStructuredText": true,
+	"AsciiDoc":         true,
+	"TeX":              true,
+}
@@ -14,6 +14,7 @@ import (
 type IPublisher interface {
 	PublishIndexJob(ctx context.Context, repoID int) error
 	PublishUpdateJob(ctx context.Context, repoID int) error
+	PublishDeleteJob(ctx context.Context, repoID int) error
 	PublishDiscoverJob(ctx context.Context, userID int64, provider string) error
 	GetQueueLength(ctx context.Context) (int64, error)
 }
@@ -67,6 +68,25 @@ func (p *publisherImpl) PublishUpdateJob(ctx context.Context, repoID int) error
 	return nil
 }
 
+// PublishDeleteJob creates a job to remove a repository's clone and indexed data
+func (p *publisherImpl) PublishDeleteJob(ctx context.Context, repoID int) error {
+	job := &Job{
+		ID:           uuid.New().String(),
+		RepositoryID: int64(repoID),
+		Type:         JobTypeDelete,
+		Payload:      make(map[string]interface{}),
+		CreatedAt:    time.Now(),
+		Retries:      0,
+		MaxRetries:   3,
+	}
+
+	if err := p.queue.Push(job); err != nil {
+		return fmt.Errorf("failed to publish delete job: %w", err)
+	}
+
+	return nil
+}
+
 // PublishDiscoverJob creates a job to discover repositories for a user
 func (p *publisherImpl) PublishDiscoverJob(ctx context.Context, userID int64, provider string) error {
 	job := &Job{
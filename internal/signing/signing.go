@@ -0,0 +1,150 @@
+package signing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	"golang.org/x/crypto/ssh"
+)
+
+// Armor headers telling the kinds of commit signatures apart
+const (
+	pgpArmor = "-----BEGIN PGP SIGNATURE-----"
+	sshArmor = "-----BEGIN SSH SIGNATURE-----"
+)
+
+// Key is a parsed public key
+type Key struct {
+	Type        database.SignatureType // database.SignatureTypePGP or database.SignatureTypeSSH
+	Fingerprint string
+	Name        string // User ID of a PGP key, comment of an SSH key
+	pgp         *openpgp.Entity
+	ssh         ssh.PublicKey
+}
+
+// ParseKey parses a public key: an ASCII-armored PGP public key block holding a single key, or an
+// SSH key in authorized_keys format, e.g. "ssh-ed25519 AAAA... jane@example.com"
+func ParseKey(publicKey string) (*Key, error) {
+	publicKey = strings.TrimSpace(publicKey)
+
+	if strings.HasPrefix(publicKey, "-----BEGIN PGP PUBLIC KEY BLOCK-----") {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(publicKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid PGP public key: %w", err)
+		}
+		if len(entities) != 1 {
+			return nil, fmt.Errorf("expected one PGP public key, got %d", len(entities))
+		}
+		entity := entities[0]
+		key := &Key{
+			Type:        database.SignatureTypePGP,
+			Fingerprint: strings.ToUpper(hex.EncodeToString(entity.PrimaryKey.Fingerprint)),
+			pgp:         entity,
+		}
+		if identity := entity.PrimaryIdentity(); identity != nil {
+			key.Name = identity.Name
+		}
+		return key, nil
+	}
+
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey([]byte(publicKey))
+	if err != nil {
+		return nil, fmt.Errorf("must be an ASCII-armored PGP public key or an SSH public key")
+	}
+	return &Key{
+		Type:        database.SignatureTypeSSH,
+		Fingerprint: ssh.FingerprintSHA256(pub),
+		Name:        comment,
+		ssh:         pub,
+	}, nil
+}
+
+// Keyring holds the public keys trusted to sign the commits of a repository
+type Keyring struct {
+	pgp  openpgp.EntityList
+	ssh  []*Key
+	keys map[uint64]*Key // PGP keys by the ID of their primary key
+}
+
+// NewKeyring creates a keyring of the given public keys. Keys are validated when they are added
+// to a repository, so one that does not parse is only logged and left out.
+func NewKeyring(publicKeys []string) *Keyring {
+	k := &Keyring{keys: make(map[uint64]*Key)}
+	for _, publicKey := range publicKeys {
+		key, err := ParseKey(publicKey)
+		if err != nil {
+			log.Printf("Skipping signing key: %v", err)
+			continue
+		}
+		switch key.Type {
+		case database.SignatureTypePGP:
+			k.pgp = append(k.pgp, key.pgp)
+			k.keys[key.pgp.PrimaryKey.KeyId] = key
+		case database.SignatureTypeSSH:
+			k.ssh = append(k.ssh, key)
+		}
+	}
+	return k
+}
+
+// Fingerprints returns the fingerprints of the keys in the keyring, sorted
+func (k *Keyring) Fingerprints() []string {
+	if k == nil {
+		return nil
+	}
+	fingerprints := make([]string, 0, len(k.keys)+len(k.ssh))
+	for _, key := range k.keys {
+		fingerprints = append(fingerprints, key.Fingerprint)
+	}
+	for _, key := range k.ssh {
+		fingerprints = append(fingerprints, key.Fingerprint)
+	}
+	sort.Strings(fingerprints)
+	return fingerprints
+}
+
+// Verify checks a commit signature over payload, the commit encoded without its signature. It
+// returns the type of the signature, whether a trusted key made it and the fingerprint of that key.
+// Key expiry is judged at signedAt, so signatures made before a key expired stay verified. An
+// unsigned commit has no type. A nil keyring trusts no key.
+func (k *Keyring) Verify(signature string, payload []byte, signedAt time.Time) (database.SignatureType, database.SignatureStatus, string) {
+	signature = strings.TrimSpace(signature)
+	switch {
+	case signature == "":
+		return "", database.SignatureUnsigned, ""
+	case strings.HasPrefix(signature, pgpArmor):
+		if key := k.verifyPGP(signature, payload, signedAt); key != nil {
+			return database.SignatureTypePGP, database.SignatureVerified, key.Fingerprint
+		}
+		return database.SignatureTypePGP, database.SignatureUnverified, ""
+	case strings.HasPrefix(signature, sshArmor):
+		if key := k.verifySSH(signature, payload); key != nil {
+			return database.SignatureTypeSSH, database.SignatureVerified, key.Fingerprint
+		}
+		return database.SignatureTypeSSH, database.SignatureUnverified, ""
+	default:
+		return database.SignatureTypeOther, database.SignatureUnverified, ""
+	}
+}
+
+// verifyPGP returns the trusted key that made a valid PGP signature, nil if none did
+func (k *Keyring) verifyPGP(signature string, payload []byte, signedAt time.Time) *Key {
+	if k == nil || len(k.pgp) == 0 {
+		return nil
+	}
+	config := &packet.Config{Time: func() time.Time { return signedAt }}
+	signer, err := openpgp.CheckArmoredDetachedSignature(k.pgp, bytes.NewReader(payload), strings.NewReader(signature), config)
+	if err != nil || signer == nil {
+		return nil
+	}
+	return k.keys[signer.PrimaryKey.KeyId]
+}
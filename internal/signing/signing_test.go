@@ -0,0 +1,151 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"golang.org/x/crypto/ssh"
+)
+
+var payload = []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbdecb04\nauthor Test <test@example.com> 1700000000 +0000\n\nsigned\n")
+
+// pgpKey returns a new PGP entity and its armored public key
+func pgpKey(t *testing.T) (*openpgp.Entity, string) {
+	t.Helper()
+	entity, err := openpgp.NewEntity("Test", "", "test@example.com", nil)
+	if err != nil {
+		t.Fatalf("failed to generate PGP key: %v", err)
+	}
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	if err != nil {
+		t.Fatalf("failed to armor PGP key: %v", err)
+	}
+	if err := entity.Serialize(w); err != nil {
+		t.Fatalf("failed to serialize PGP key: %v", err)
+	}
+	w.Close()
+	return entity, buf.String()
+}
+
+// sshKey returns a new SSH signer and its public key in authorized_keys format
+func sshKey(t *testing.T) (ssh.Signer, string) {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate SSH key: %v", err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatalf("failed to create SSH signer: %v", err)
+	}
+	return signer, strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey()))) + " test@example.com"
+}
+
+// sshSign signs data like ssh-keygen -Y sign -n git
+func sshSign(t *testing.T, signer ssh.Signer, data []byte) string {
+	t.Helper()
+	hash := sha512.Sum512(data)
+	signed := append([]byte("SSHSIG"), ssh.Marshal(sshSignedData{
+		Namespace:     sshNamespace,
+		HashAlgorithm: "sha512",
+		Hash:          hash[:],
+	})...)
+	sig, err := signer.Sign(rand.Reader, signed)
+	if err != nil {
+		t.Fatalf("failed to sign: %v", err)
+	}
+	blob := ssh.Marshal(sshSignature{
+		Magic:         [6]byte{'S', 'S', 'H', 'S', 'I', 'G'},
+		Version:       1,
+		PublicKey:     signer.PublicKey().Marshal(),
+		Namespace:     sshNamespace,
+		HashAlgorithm: "sha512",
+		Signature:     ssh.Marshal(sig),
+	})
+	return sshArmor + "\n" + base64.StdEncoding.EncodeToString(blob) + "\n-----END SSH SIGNATURE-----\n"
+}
+
+func TestParseKey(t *testing.T) {
+	entity, pgpPublic := pgpKey(t)
+	key, err := ParseKey(pgpPublic)
+	if err != nil {
+		t.Fatalf("ParseKey(pgp) error = %v", err)
+	}
+	if key.Type != database.SignatureTypePGP || key.Name != "Test <test@example.com>" || len(key.Fingerprint) != 2*len(entity.PrimaryKey.Fingerprint) {
+		t.Errorf("ParseKey(pgp) = %+v", key)
+	}
+
+	_, sshPublic := sshKey(t)
+	key, err = ParseKey(sshPublic)
+	if err != nil {
+		t.Fatalf("ParseKey(ssh) error = %v", err)
+	}
+	if key.Type != database.SignatureTypeSSH || key.Name != "test@example.com" || !strings.HasPrefix(key.Fingerprint, "SHA256:") {
+		t.Errorf("ParseKey(ssh) = %+v", key)
+	}
+
+	if _, err := ParseKey("not a key"); err == nil {
+		t.Error("ParseKey() expected error for an invalid key")
+	}
+}
+
+func TestVerify(t *testing.T) {
+	trustedPGP, trustedPGPPublic := pgpKey(t)
+	untrustedPGP, _ := pgpKey(t)
+	trustedSSH, trustedSSHPublic := sshKey(t)
+	untrustedSSH, _ := sshKey(t)
+
+	pgpSign := func(entity *openpgp.Entity, data []byte) string {
+		var buf bytes.Buffer
+		if err := openpgp.ArmoredDetachSign(&buf, entity, bytes.NewReader(data), nil); err != nil {
+			t.Fatalf("failed to sign: %v", err)
+		}
+		return buf.String()
+	}
+
+	keyring := NewKeyring([]string{trustedPGPPublic, trustedSSHPublic, "not a key"})
+	if got := len(keyring.Fingerprints()); got != 2 {
+		t.Fatalf("keyring has %d keys, want 2", got)
+	}
+
+	tests := []struct {
+		name       string
+		keyring    *Keyring
+		signature  string
+		wantType   database.SignatureType
+		wantStatus database.SignatureStatus
+		wantKey    bool
+	}{
+		{"unsigned", keyring, "", "", database.SignatureUnsigned, false},
+		{"pgp trusted", keyring, pgpSign(trustedPGP, payload), database.SignatureTypePGP, database.SignatureVerified, true},
+		{"pgp untrusted", keyring, pgpSign(untrustedPGP, payload), database.SignatureTypePGP, database.SignatureUnverified, false},
+		{"pgp tampered", keyring, pgpSign(trustedPGP, []byte("other")), database.SignatureTypePGP, database.SignatureUnverified, false},
+		{"pgp without keyring", nil, pgpSign(trustedPGP, payload), database.SignatureTypePGP, database.SignatureUnverified, false},
+		{"ssh trusted", keyring, sshSign(t, trustedSSH, payload), database.SignatureTypeSSH, database.SignatureVerified, true},
+		{"ssh untrusted", keyring, sshSign(t, untrustedSSH, payload), database.SignatureTypeSSH, database.SignatureUnverified, false},
+		{"ssh tampered", keyring, sshSign(t, trustedSSH, []byte("other")), database.SignatureTypeSSH, database.SignatureUnverified, false},
+		{"ssh without keyring", nil, sshSign(t, trustedSSH, payload), database.SignatureTypeSSH, database.SignatureUnverified, false},
+		{"x509", keyring, "-----BEGIN SIGNED MESSAGE-----\n...\n-----END SIGNED MESSAGE-----", database.SignatureTypeOther, database.SignatureUnverified, false},
+	}
+
+	for _, tt := range tests {
+		sigType, status, fingerprint := tt.keyring.Verify(tt.signature, payload, time.Now())
+		if sigType != tt.wantType || status != tt.wantStatus {
+			t.Errorf("%s: Verify() = %q, %q, want %q, %q", tt.name, sigType, status, tt.wantType, tt.wantStatus)
+		}
+		if (fingerprint != "") != tt.wantKey {
+			t.Errorf("%s: Verify() key = %q, want key %t", tt.name, fingerprint, tt.wantKey)
+		}
+	}
+}
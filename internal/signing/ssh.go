@@ -0,0 +1,102 @@
+package signing
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// sshNamespace is the namespace git signs commits in (ssh-keygen -Y sign -n git)
+const sshNamespace = "git"
+
+// sshSignature is the blob of an armored SSH signature, see PROTOCOL.sshsig of OpenSSH
+type sshSignature struct {
+	Magic         [6]byte
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData is what an SSH signature signs, after the magic preamble
+type sshSignedData struct {
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+// verifySSH returns the trusted key that made a valid SSH signature, nil if none did
+func (k *Keyring) verifySSH(signature string, payload []byte) *Key {
+	if k == nil || len(k.ssh) == 0 {
+		return nil
+	}
+
+	sig, ok := parseSSHSignature(signature)
+	if !ok || string(sig.Magic[:]) != "SSHSIG" || sig.Version != 1 || sig.Namespace != sshNamespace {
+		return nil
+	}
+
+	var key *Key
+	for _, trusted := range k.ssh {
+		if bytes.Equal(trusted.ssh.Marshal(), sig.PublicKey) {
+			key = trusted
+			break
+		}
+	}
+	if key == nil {
+		return nil
+	}
+
+	var hash []byte
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h := sha256.Sum256(payload)
+		hash = h[:]
+	case "sha512":
+		h := sha512.Sum512(payload)
+		hash = h[:]
+	default:
+		return nil
+	}
+
+	var inner ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &inner); err != nil {
+		return nil
+	}
+	signed := append([]byte("SSHSIG"), ssh.Marshal(sshSignedData{
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          hash,
+	})...)
+	if err := key.ssh.Verify(signed, &inner); err != nil {
+		return nil
+	}
+	return key
+}
+
+// parseSSHSignature decodes an armored SSH signature
+func parseSSHSignature(armored string) (*sshSignature, bool) {
+	body := strings.TrimPrefix(strings.TrimSpace(armored), sshArmor)
+	body, _, ok := strings.Cut(body, "-----END SSH SIGNATURE-----")
+	if !ok {
+		return nil, false
+	}
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return nil, false
+	}
+
+	sig := &sshSignature{}
+	if err := ssh.Unmarshal(blob, sig); err != nil {
+		return nil, false
+	}
+	return sig, true
+}
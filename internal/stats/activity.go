@@ -15,25 +15,68 @@ type ActivityLevel struct {
 	Level int    `json:"level"` // 0-4 based on quantile
 }
 
+// ActivityOptions contains optional filters for commit activity
+type ActivityOptions struct {
+	Days             int    // Number of days back from today (0 = 1 year)
+	Branch           string // Only count commits reachable from this branch (empty = all indexed branches)
+	IncludeMerges    bool   // Whether merge commits are counted
+	Author           string // Only count commits by this author email or its aliases (empty = everyone)
+	IncludeCoAuthors bool   // With Author, also count commits that credit the author as a co-author
+	ExcludeBots      bool   // Leave out commits by authors flagged as bots
+}
+
 // GetCommitActivity returns the daily commit activity for a repository
-func GetCommitActivity(ctx context.Context, pool database.PgxIface, repositoryID int64, days int) ([]ActivityLevel, error) {
+func GetCommitActivity(ctx context.Context, pool database.PgxIface, repositoryID int64, opts ActivityOptions) ([]ActivityLevel, error) {
+	days := opts.Days
 	if days <= 0 {
 		days = 365 // Default to 1 year
 	}
 
 	startDate := time.Now().AddDate(0, 0, -days)
 
-	query := `
+	args := []interface{}{repositoryID, startDate}
+	var filters string
+	if opts.Branch != "" {
+		args = append(args, opts.Branch)
+		filters += branchCondition(len(args))
+	}
+	if !opts.IncludeMerges {
+		filters += " AND NOT c.is_merge"
+	}
+	if opts.ExcludeBots {
+		filters += database.NotBotCondition("c.author_email")
+	}
+	var identityJoin string
+	if opts.Author != "" {
+		args = append(args, opts.Author)
+		author := database.CanonicalEmailOf(fmt.Sprintf("$%d", len(args)))
+		identityJoin = database.IdentityJoin("ident", "c.author_email", "c.author_name")
+		if opts.IncludeCoAuthors {
+			var coAuthorBotFilter string
+			if opts.ExcludeBots {
+				coAuthorBotFilter = " AND NOT ca.is_bot"
+			}
+			filters += fmt.Sprintf(`
+		AND (ident.email = %s OR EXISTS (
+			SELECT 1 FROM commit_coauthors ca%s
+			WHERE ca.repository_id = c.repository_id AND ca.commit_hash = c.hash AND cident.email = %s%s
+		))`, author, database.IdentityJoin("cident", "ca.email", "ca.name"), author, coAuthorBotFilter)
+		} else {
+			filters += fmt.Sprintf(" AND ident.email = %s", author)
+		}
+	}
+
+	query := fmt.Sprintf(`
         SELECT 
-            TO_CHAR(committed_at, 'YYYY-MM-DD') as date,
+            TO_CHAR(c.committed_at, 'YYYY-MM-DD') as date,
             COUNT(*) as count
-        FROM commits
-        WHERE repository_id = $1 AND committed_at >= $2
+        FROM commits c%s
+        WHERE c.repository_id = $1 AND c.committed_at >= $2 %s
         GROUP BY date
         ORDER BY date ASC
-    `
+    `, identityJoin, filters)
 
-	rows, err := pool.Query(ctx, query, repositoryID, startDate)
+	rows, err := pool.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query commit activity: %w", err)
 	}
@@ -0,0 +1,13 @@
+package stats
+
+import "fmt"
+
+// branchCondition restricts the commits aliased c to those reachable from the branch bound to $argIndex.
+// Branch membership is recorded by the worker for every indexed branch.
+func branchCondition(argIndex int) string {
+	return fmt.Sprintf(`
+		AND EXISTS (
+			SELECT 1 FROM commit_branches cb
+			WHERE cb.repository_id = c.repository_id AND cb.commit_hash = c.hash AND cb.branch = $%d
+		)`, argIndex)
+}
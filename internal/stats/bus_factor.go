@@ -11,11 +11,22 @@ import (
 	"git-repository-visualizer/internal/database"
 )
 
+// Ownership sources for bus factor calculation
+const (
+	OwnershipSourceCommits = "commits" // Author with the most added lines over the history owns the file
+	OwnershipSourceBlame   = "blame"   // Author of the most surviving lines at HEAD owns the file
+)
+
 // BusFactorOptions contains optional filters for bus factor calculation
 type BusFactorOptions struct {
-	Threshold       float64 // Ownership threshold (e.g., 0.5 = 50%)
-	ActiveDays      int     // Only count contributors active in last N days (0 = all time)
-	ExcludePatterns bool    // Whether to exclude files matching exclusion patterns
+	Threshold        float64 // Ownership threshold (e.g., 0.5 = 50%)
+	ActiveDays       int     // Only count contributors active in last N days (0 = all time)
+	ExcludePatterns  bool    // Whether to exclude files matching exclusion patterns or classified as non-source
+	FollowRenames    bool    // Count contributions made under previous names towards the file's current path
+	Source           string  // OwnershipSourceCommits (default) or OwnershipSourceBlame
+	Branch           string  // Only count commits reachable from this branch (commits source only)
+	IncludeCoAuthors bool    // Credit Co-authored-by trailers like the commit's author (commits source only)
+	ExcludeBots      bool    // Leave out contributions of authors flagged as bots
 }
 
 // BusFactorResult holds the calculated bus factor and ownership data
@@ -43,19 +54,70 @@ func CalculateBusFactor(ctx context.Context, pool database.PgxIface, repositoryI
 	var args []interface{}
 	argIndex := 1
 
-	conditions = append(conditions, fmt.Sprintf("cf.repository_id = $%d", argIndex))
+	useBlame := opts.Source == OwnershipSourceBlame
+
+	// Authors are aggregated by canonical identity
+	pathExpr, authorExpr, authorNameExpr := "cf.file_path", "ident.email", "ident.name"
+	rawAuthorExpr := "c.author_email" // Bots are flagged per identity, before aliasing
+	identityJoin := database.IdentityJoin("ident", "c.author_email", "c.author_name")
+	var lineageCTE, lineageJoin, coAuthorJoin string
+	if opts.IncludeCoAuthors && !useBlame {
+		// Every credited person receives the commit's full contribution
+		coAuthorJoin = `
+			CROSS JOIN LATERAL (
+				SELECT c.author_email, c.author_name, false
+				UNION ALL
+				SELECT ca.email, ca.name, ca.is_bot FROM commit_coauthors ca
+				WHERE ca.repository_id = c.repository_id AND ca.commit_hash = c.hash
+			) cr(email, name, is_bot)`
+		identityJoin = database.IdentityJoin("ident", "cr.email", "cr.name")
+		rawAuthorExpr = "cr.email"
+	}
+	switch {
+	case useBlame:
+		pathExpr = "fo.file_path"
+		identityJoin = database.IdentityJoin("ident", "fo.author_email", "fo.author_name")
+		rawAuthorExpr = "fo.author_email"
+	case opts.FollowRenames:
+		pathExpr = currentPathExpr
+		lineageCTE = fileLineageCTE + ","
+		lineageJoin = currentPathJoin
+	}
+
+	if useBlame {
+		conditions = append(conditions, fmt.Sprintf("fo.repository_id = $%d", argIndex))
+	} else {
+		conditions = append(conditions, fmt.Sprintf("cf.repository_id = $%d", argIndex))
+	}
 	args = append(args, repositoryID)
 	argIndex++
 
+	// Branch filter (blame ownership is always taken from the default branch)
+	if opts.Branch != "" && !useBlame {
+		conditions[0] += branchCondition(argIndex)
+		args = append(args, opts.Branch)
+		argIndex++
+	}
+
 	// Active contributors filter
 	var activeContributorFilter string
 	if opts.ActiveDays > 0 {
 		cutoffDate := time.Now().AddDate(0, 0, -opts.ActiveDays)
+		activeAuthors := fmt.Sprintf(`
+				SELECT DISTINCT aident.email FROM commits ac%s
+				WHERE ac.repository_id = $1 AND ac.committed_at > $%d`,
+			database.IdentityJoin("aident", "ac.author_email", "ac.author_name"), argIndex)
+		if coAuthorJoin != "" {
+			activeAuthors += fmt.Sprintf(`
+				UNION
+				SELECT aident.email FROM commit_coauthors ca
+				JOIN commits ac ON ac.repository_id = ca.repository_id AND ac.hash = ca.commit_hash%s
+				WHERE ca.repository_id = $1 AND ac.committed_at > $%d`,
+				database.IdentityJoin("aident", "ca.email", "ca.name"), argIndex)
+		}
 		activeContributorFilter = fmt.Sprintf(`
-			AND c.author_email IN (
-				SELECT DISTINCT author_email FROM commits 
-				WHERE repository_id = $1 AND committed_at > $%d
-			)`, argIndex)
+			AND %s IN (%s
+			)`, authorExpr, activeAuthors)
 		args = append(args, cutoffDate)
 		argIndex++
 	}
@@ -67,26 +129,57 @@ func CalculateBusFactor(ctx context.Context, pool database.PgxIface, repositoryI
 		if len(patterns) > 0 {
 			var notLikes []string
 			for _, pattern := range patterns {
-				notLikes = append(notLikes, fmt.Sprintf("cf.file_path NOT LIKE $%d", argIndex))
+				notLikes = append(notLikes, fmt.Sprintf("%s NOT LIKE $%d", pathExpr, argIndex))
 				args = append(args, pattern)
 				argIndex++
 			}
 			exclusionFilter = " AND " + strings.Join(notLikes, " AND ")
 		}
+		exclusionFilter += nonSourceCondition(pathExpr)
+	}
+	if opts.ExcludeBots {
+		exclusionFilter += database.NotBotCondition(rawAuthorExpr)
+		if coAuthorJoin != "" {
+			exclusionFilter += " AND NOT cr.is_bot"
+		}
 	}
 
-	query := fmt.Sprintf(`
-		WITH file_contributions AS (
+	// Contributions per file and author: surviving lines at HEAD from blame,
+	// or lines added over the whole history
+	var fileContributions string
+	if useBlame {
+		fileContributions = fmt.Sprintf(`
+		file_contributions AS (
 			SELECT 
-				cf.file_path,
-				c.author_email,
-				c.author_name,
+				fo.file_path,
+				%s as author_email,
+				MAX(%s) as author_name,
+				SUM(fo.lines) as total_additions
+			FROM file_ownership fo%s
+			WHERE %s%s%s
+			GROUP BY fo.file_path, %s
+		)`, authorExpr, authorNameExpr, identityJoin, strings.Join(conditions, " AND "),
+			activeContributorFilter, exclusionFilter, authorExpr)
+	} else {
+		fileContributions = fmt.Sprintf(`
+		file_contributions AS (
+			SELECT 
+				%s as file_path,
+				%s as author_email,
+				MAX(%s) as author_name,
 				SUM(cf.additions) as total_additions
 			FROM commit_files cf
 			JOIN commits c ON c.hash = cf.commit_hash AND c.repository_id = cf.repository_id
+			%s%s%s
 			WHERE %s%s%s
-			GROUP BY cf.file_path, c.author_email, c.author_name
-		),
+			GROUP BY %s, %s
+		)`, pathExpr, authorExpr, authorNameExpr, coAuthorJoin, identityJoin, lineageJoin, strings.Join(conditions, " AND "),
+			activeContributorFilter, exclusionFilter, pathExpr, authorExpr)
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE %s
+		%s,
 		file_owners AS (
 			SELECT DISTINCT ON (file_path) 
 			file_path,
@@ -98,12 +191,12 @@ func CalculateBusFactor(ctx context.Context, pool database.PgxIface, repositoryI
 		)
 		SELECT 
 			author_email,
-			author_name,
+			MAX(author_name),
 			COUNT(*) as files_owned
 		FROM file_owners
-		GROUP BY author_email, author_name
+		GROUP BY author_email
 		ORDER BY files_owned DESC
-	`, strings.Join(conditions, " AND "), activeContributorFilter, exclusionFilter)
+	`, lineageCTE, fileContributions)
 
 	rows, err := pool.Query(ctx, query, args...)
 	if err != nil {
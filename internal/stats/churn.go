@@ -25,12 +25,21 @@ const (
 	CategoryFrequent = "frequent"
 	CategoryMassive  = "massive"
 	CategoryStable   = "stable"
+
+	// Granularities: whole files, or the Go functions and methods touched
+	ChurnGranularityFile     = "file"
+	ChurnGranularityFunction = "function"
 )
 
 // ChurnOptions contains optional filters for churn calculation
 type ChurnOptions struct {
-	Limit int // Top N files
-	Days  int // Only count commits in last N days (0 = all time)
+	Limit            int    // Top N files
+	Days             int    // Only count commits in last N days (0 = all time)
+	FollowRenames    bool   // Attribute changes made under previous names to the file's current path
+	Branch           string // Only count commits reachable from this branch (empty = all indexed branches)
+	IncludeMerges    bool   // Whether changes recorded on merge commits count (first-parent mode only)
+	ExcludeNonSource bool   // Leave out vendored, generated and documentation files
+	ExcludeBots      bool   // Leave out commits by authors flagged as bots
 }
 
 // FileChurn represents churn statistics for a single file
@@ -59,19 +68,46 @@ func GetHighChurnFiles(ctx context.Context, pool database.PgxIface, repositoryID
 		args = append(args, cutoffDate)
 	}
 
+	if opts.Branch != "" {
+		args = append(args, opts.Branch)
+		timeFilter += branchCondition(len(args))
+	}
+
+	if !opts.IncludeMerges {
+		timeFilter += " AND NOT c.is_merge"
+	}
+
+	pathExpr := "cf.file_path"
+	var lineageCTE, lineageJoin string
+	if opts.FollowRenames {
+		pathExpr = currentPathExpr
+		lineageCTE = "WITH RECURSIVE " + fileLineageCTE
+		lineageJoin = currentPathJoin
+	}
+
+	if opts.ExcludeNonSource {
+		timeFilter += nonSourceCondition(pathExpr)
+	}
+
+	if opts.ExcludeBots {
+		timeFilter += database.NotBotCondition("c.author_email")
+	}
+
 	query := fmt.Sprintf(`
+		%s
 		SELECT
-			cf.file_path,
+			%s as path,
 			COUNT(DISTINCT cf.commit_hash) as commit_count,
 			SUM(cf.additions + cf.deletions) as lines_changed,
 			MAX(c.committed_at) as last_modified
 		FROM commit_files cf
 		JOIN commits c ON cf.commit_hash = c.hash AND cf.repository_id = c.repository_id
+		%s
 		WHERE cf.repository_id = $1 %s
-		GROUP BY cf.file_path
+		GROUP BY %s
 		ORDER BY commit_count DESC, lines_changed DESC
 		LIMIT $%d
-	`, timeFilter, len(args)+1)
+	`, lineageCTE, pathExpr, lineageJoin, timeFilter, pathExpr, len(args)+1)
 	args = append(args, opts.Limit)
 
 	rows, err := pool.Query(ctx, query, args...)
@@ -112,6 +148,109 @@ func GetHighChurnFiles(ctx context.Context, pool database.PgxIface, repositoryID
 	return results, nil
 }
 
+// FunctionChurn represents churn statistics for a single Go function or method
+type FunctionChurn struct {
+	FilePath     string    `json:"file_path"`
+	Function     string    `json:"function"` // "Parse", "Server.Start" or "(*Server).Handle"
+	CommitCount  int       `json:"commit_count"`
+	LinesChanged int       `json:"lines_changed"`
+	ChurnScore   float64   `json:"churn_score"`
+	Category     string    `json:"category"` // "hotspot", "frequent", "massive", or "stable"
+	LastModified time.Time `json:"last_modified"`
+}
+
+// GetHighChurnFunctions calculates the churn for the Go functions and methods of a repository. A function
+// is identified by its file and name, so a function moved to another file starts over.
+func GetHighChurnFunctions(ctx context.Context, pool database.PgxIface, repositoryID int64, opts ChurnOptions) ([]FunctionChurn, error) {
+	var filters string
+	args := []interface{}{repositoryID}
+
+	if opts.Days > 0 {
+		args = append(args, time.Now().AddDate(0, 0, -opts.Days))
+		filters += fmt.Sprintf(" AND c.committed_at > $%d", len(args))
+	}
+
+	if opts.Branch != "" {
+		args = append(args, opts.Branch)
+		filters += branchCondition(len(args))
+	}
+
+	if !opts.IncludeMerges {
+		filters += " AND NOT c.is_merge"
+	}
+
+	// Rows are aliased cf like commit_files, so the rename lineage applies to them
+	pathExpr := "cf.file_path"
+	var lineageCTE, lineageJoin string
+	if opts.FollowRenames {
+		pathExpr = currentPathExpr
+		lineageCTE = "WITH RECURSIVE " + fileLineageCTE
+		lineageJoin = currentPathJoin
+	}
+
+	if opts.ExcludeNonSource {
+		filters += nonSourceCondition(pathExpr)
+	}
+
+	if opts.ExcludeBots {
+		filters += database.NotBotCondition("c.author_email")
+	}
+
+	query := fmt.Sprintf(`
+		%s
+		SELECT
+			%s as path,
+			cf.function_name,
+			COUNT(DISTINCT cf.commit_hash) as commit_count,
+			SUM(cf.additions + cf.deletions) as lines_changed,
+			MAX(c.committed_at) as last_modified
+		FROM commit_functions cf
+		JOIN commits c ON cf.commit_hash = c.hash AND cf.repository_id = c.repository_id
+		%s
+		WHERE cf.repository_id = $1%s
+		GROUP BY %s, cf.function_name
+		ORDER BY commit_count DESC, lines_changed DESC
+		LIMIT $%d
+	`, lineageCTE, pathExpr, lineageJoin, filters, pathExpr, len(args)+1)
+	args = append(args, opts.Limit)
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query function churn: %w", err)
+	}
+	defer rows.Close()
+
+	results := []FunctionChurn{}
+	var maxCommits, maxLines int
+
+	for rows.Next() {
+		var fc FunctionChurn
+		if err := rows.Scan(&fc.FilePath, &fc.Function, &fc.CommitCount, &fc.LinesChanged, &fc.LastModified); err != nil {
+			return nil, fmt.Errorf("failed to scan function churn row: %w", err)
+		}
+
+		if fc.CommitCount > maxCommits {
+			maxCommits = fc.CommitCount
+		}
+		if fc.LinesChanged > maxLines {
+			maxLines = fc.LinesChanged
+		}
+
+		results = append(results, fc)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for i := range results {
+		results[i].ChurnScore = calculateScore(results[i].CommitCount, results[i].LinesChanged, maxCommits, maxLines)
+		results[i].Category = categorizeFile(results[i].CommitCount, results[i].LinesChanged, maxCommits, maxLines)
+	}
+
+	return results, nil
+}
+
 // calculateScore returns a weighted score between 0 and 100
 func calculateScore(commits, lines, maxCommits, maxLines int) float64 {
 	if maxCommits == 0 || maxLines == 0 {
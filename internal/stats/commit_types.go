@@ -0,0 +1,250 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// Timeline buckets of the commit type mix
+const (
+	CommitTypeIntervalWeek  = "week"
+	CommitTypeIntervalMonth = "month"
+)
+
+// CommitTypeOptions contains optional filters for the commit type mix
+type CommitTypeOptions struct {
+	Interval      string // Timeline bucket: CommitTypeIntervalWeek or CommitTypeIntervalMonth (default)
+	Days          int    // Only count commits of the last N days (0 = whole history)
+	Branch        string // Only count commits reachable from this branch (empty = all indexed branches)
+	IncludeMerges bool   // Whether merge commits are counted
+	Limit         int    // Contributors and scopes per page
+	Offset        int    // Contributors and scopes to skip
+	ExcludeBots   bool   // Leave out commits by authors flagged as bots
+}
+
+// CommitTypeMix counts commits per type
+type CommitTypeMix struct {
+	Total  int                         `json:"total"`
+	Types  map[database.CommitType]int `json:"types"`   // Every type is present, zero when unused
+	FixPct float64                     `json:"fix_pct"` // Share of fixes in percent
+}
+
+// CommitTypePeriod is the commit type mix of a week or month
+type CommitTypePeriod struct {
+	Date string `json:"date"` // Start of the period
+	CommitTypeMix
+}
+
+// CommitTypeContributor is the commit type mix of a contributor (canonical identity)
+type CommitTypeContributor struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	CommitTypeMix
+}
+
+// CommitTypeScope is the commit type mix of an area named in commit subjects, e.g. "api" in "fix(api): ..."
+type CommitTypeScope struct {
+	Scope string `json:"scope"`
+	CommitTypeMix
+}
+
+// CommitTypesResult is the commit type mix of a repository overall, over time, per contributor and per scope
+type CommitTypesResult struct {
+	CommitTypeMix
+	Timeline     []CommitTypePeriod      `json:"timeline"`     // Oldest first
+	Contributors []CommitTypeContributor `json:"contributors"` // Most commits first
+	Scopes       []CommitTypeScope       `json:"scopes"`       // Most commits first; commits without a scope are left out
+}
+
+func newCommitTypeMix() CommitTypeMix {
+	types := make(map[database.CommitType]int, len(database.CommitTypes))
+	for _, t := range database.CommitTypes {
+		types[t] = 0
+	}
+	return CommitTypeMix{Types: types}
+}
+
+func (m *CommitTypeMix) add(t database.CommitType, count int) {
+	m.Types[t] += count
+	m.Total += count
+	m.FixPct = percentage(m.Types[database.CommitTypeFix], m.Total)
+}
+
+// GetCommitTypes returns the mix of commit types as classified by the worker
+func GetCommitTypes(ctx context.Context, pool database.PgxIface, repositoryID int64, opts CommitTypeOptions) (*CommitTypesResult, error) {
+	interval := opts.Interval
+	if interval != CommitTypeIntervalWeek {
+		interval = CommitTypeIntervalMonth
+	}
+
+	args := []interface{}{repositoryID}
+	var filters string
+	if opts.Days > 0 {
+		args = append(args, time.Now().AddDate(0, 0, -opts.Days))
+		filters += fmt.Sprintf(" AND c.committed_at >= $%d", len(args))
+	}
+	if opts.Branch != "" {
+		args = append(args, opts.Branch)
+		filters += branchCondition(len(args))
+	}
+	if !opts.IncludeMerges {
+		filters += " AND NOT c.is_merge"
+	}
+	if opts.ExcludeBots {
+		filters += database.NotBotCondition("c.author_email")
+	}
+
+	result := &CommitTypesResult{
+		CommitTypeMix: newCommitTypeMix(),
+		Timeline:      []CommitTypePeriod{},
+		Contributors:  []CommitTypeContributor{},
+		Scopes:        []CommitTypeScope{},
+	}
+
+	// 1. Timeline, which also gives the totals. Weeks start on Monday.
+	timelineQuery := fmt.Sprintf(`
+		SELECT TO_CHAR(DATE_TRUNC('%s', c.committed_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') as date, c.commit_type, COUNT(*)
+		FROM commits c
+		WHERE c.repository_id = $1%s
+		GROUP BY date, c.commit_type
+		ORDER BY date ASC
+	`, interval, filters)
+
+	rows, err := pool.Query(ctx, timelineQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commit type timeline: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var date string
+		var commitType database.CommitType
+		var count int
+		if err := rows.Scan(&date, &commitType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan commit type timeline: %w", err)
+		}
+
+		// Rows are grouped by period, so a new date starts a new period
+		if n := len(result.Timeline); n == 0 || result.Timeline[n-1].Date != date {
+			result.Timeline = append(result.Timeline, CommitTypePeriod{Date: date, CommitTypeMix: newCommitTypeMix()})
+		}
+		result.Timeline[len(result.Timeline)-1].add(commitType, count)
+		result.add(commitType, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// 2. Contributors by canonical identity
+	contributorsQuery := fmt.Sprintf(`
+		SELECT ident.email, MAX(ident.name), c.commit_type, COUNT(*)
+		FROM commits c%s
+		WHERE c.repository_id = $1%s
+		GROUP BY ident.email, c.commit_type
+	`, database.IdentityJoin("ident", "c.author_email", "c.author_name"), filters)
+
+	contributorRows, err := pool.Query(ctx, contributorsQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commit types per contributor: %w", err)
+	}
+	defer contributorRows.Close()
+
+	contributors := make(map[string]*CommitTypeContributor)
+	for contributorRows.Next() {
+		var email, name string
+		var commitType database.CommitType
+		var count int
+		if err := contributorRows.Scan(&email, &name, &commitType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan commit types per contributor: %w", err)
+		}
+		c, ok := contributors[email]
+		if !ok {
+			c = &CommitTypeContributor{Email: email, Name: name, CommitTypeMix: newCommitTypeMix()}
+			contributors[email] = c
+		}
+		c.add(commitType, count)
+	}
+
+	if err := contributorRows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for _, c := range contributors {
+		result.Contributors = append(result.Contributors, *c)
+	}
+	sort.Slice(result.Contributors, func(i, j int) bool {
+		a, b := result.Contributors[i], result.Contributors[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Email < b.Email
+	})
+	result.Contributors = page(result.Contributors, opts.Limit, opts.Offset)
+
+	// 3. Scopes
+	scopesQuery := fmt.Sprintf(`
+		SELECT c.commit_scope, c.commit_type, COUNT(*)
+		FROM commits c
+		WHERE c.repository_id = $1 AND c.commit_scope IS NOT NULL%s
+		GROUP BY c.commit_scope, c.commit_type
+	`, filters)
+
+	scopeRows, err := pool.Query(ctx, scopesQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query commit types per scope: %w", err)
+	}
+	defer scopeRows.Close()
+
+	scopes := make(map[string]*CommitTypeScope)
+	for scopeRows.Next() {
+		var scope string
+		var commitType database.CommitType
+		var count int
+		if err := scopeRows.Scan(&scope, &commitType, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan commit types per scope: %w", err)
+		}
+		s, ok := scopes[scope]
+		if !ok {
+			s = &CommitTypeScope{Scope: scope, CommitTypeMix: newCommitTypeMix()}
+			scopes[scope] = s
+		}
+		s.add(commitType, count)
+	}
+
+	if err := scopeRows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for _, s := range scopes {
+		result.Scopes = append(result.Scopes, *s)
+	}
+	sort.Slice(result.Scopes, func(i, j int) bool {
+		a, b := result.Scopes[i], result.Scopes[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Scope < b.Scope
+	})
+	result.Scopes = page(result.Scopes, opts.Limit, opts.Offset)
+
+	return result, nil
+}
+
+// page returns the items of a limit/offset page (limit <= 0 = all remaining items)
+func page[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return items[:0]
+	}
+	if offset > 0 {
+		items = items[offset:]
+	}
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,14 @@
+package stats
+
+import "fmt"
+
+// nonSourceCondition leaves out paths the worker classified as vendored, generated or documentation.
+// Classification comes from the file inventory at HEAD, so paths that no longer exist are kept.
+func nonSourceCondition(pathExpr string) string {
+	return fmt.Sprintf(`
+		AND NOT EXISTS (
+			SELECT 1 FROM files xf
+			WHERE xf.repository_id = $1 AND xf.path = %s
+			AND (xf.is_vendored OR xf.is_generated OR xf.is_documentation)
+		)`, pathExpr)
+}
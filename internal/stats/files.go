@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// DefaultLargestBinaries is the number of binaries listed in the inventory summary
+const DefaultLargestBinaries = 10
+
+// FileInventoryOptions contains optional settings for the file inventory summary
+type FileInventoryOptions struct {
+	LargestBinaries int // Number of largest binaries to list (0 = DefaultLargestBinaries)
+}
+
+// FileTypeStats aggregates the files at HEAD sharing an extension
+type FileTypeStats struct {
+	Extension   string `json:"extension"` // Lower-case, without the dot; empty for files without one
+	Files       int    `json:"files"`
+	BinaryFiles int    `json:"binary_files"`
+	Bytes       int64  `json:"bytes"`
+	Lines       int    `json:"lines"`
+}
+
+// LargeFile is a file at HEAD listed by size
+type LargeFile struct {
+	Path      string `json:"path"`
+	SizeBytes int64  `json:"size_bytes"`
+	BlobHash  string `json:"blob_hash"`
+}
+
+// FileInventoryResult summarises the weight of a repository at HEAD
+type FileInventoryResult struct {
+	TotalFiles      int             `json:"total_files"`
+	TotalBytes      int64           `json:"total_bytes"`
+	TotalLines      int             `json:"total_lines"`
+	BinaryFiles     int             `json:"binary_files"`
+	BinaryBytes     int64           `json:"binary_bytes"`
+	LargestBinaries []LargeFile     `json:"largest_binaries"`
+	Types           []FileTypeStats `json:"types"` // Ordered by bytes, largest first
+}
+
+// GetFileInventory returns the size totals, largest binaries and per-extension breakdown of the files at HEAD
+func GetFileInventory(ctx context.Context, pool database.PgxIface, repositoryID int64, opts FileInventoryOptions) (*FileInventoryResult, error) {
+	if opts.LargestBinaries <= 0 {
+		opts.LargestBinaries = DefaultLargestBinaries
+	}
+
+	result := &FileInventoryResult{
+		LargestBinaries: []LargeFile{},
+		Types:           []FileTypeStats{},
+	}
+
+	// 1. Breakdown by extension; the totals are its sum
+	typesQuery := `
+		SELECT
+			COALESCE(LOWER(SUBSTRING(path FROM '\.([^./]+)$')), '') as extension,
+			COUNT(*) as files,
+			COUNT(*) FILTER (WHERE is_binary) as binary_files,
+			COALESCE(SUM(size_bytes), 0) as bytes,
+			COALESCE(SUM(lines), 0) as lines
+		FROM files
+		WHERE repository_id = $1
+		GROUP BY extension
+		ORDER BY bytes DESC, extension ASC
+	`
+
+	rows, err := pool.Query(ctx, typesQuery, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file types: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t FileTypeStats
+		if err := rows.Scan(&t.Extension, &t.Files, &t.BinaryFiles, &t.Bytes, &t.Lines); err != nil {
+			return nil, fmt.Errorf("failed to scan file type: %w", err)
+		}
+		result.TotalFiles += t.Files
+		result.TotalBytes += t.Bytes
+		result.TotalLines += t.Lines
+		result.BinaryFiles += t.BinaryFiles
+		result.Types = append(result.Types, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// 2. Binary weight and the largest binaries
+	binaryQuery := `
+		SELECT path, size_bytes, blob_hash, SUM(size_bytes) OVER () as binary_bytes
+		FROM files
+		WHERE repository_id = $1 AND is_binary
+		ORDER BY size_bytes DESC, path ASC
+		LIMIT $2
+	`
+
+	binRows, err := pool.Query(ctx, binaryQuery, repositoryID, opts.LargestBinaries)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query largest binaries: %w", err)
+	}
+	defer binRows.Close()
+
+	for binRows.Next() {
+		var f LargeFile
+		if err := binRows.Scan(&f.Path, &f.SizeBytes, &f.BlobHash, &result.BinaryBytes); err != nil {
+			return nil, fmt.Errorf("failed to scan binary file: %w", err)
+		}
+		result.LargestBinaries = append(result.LargestBinaries, f)
+	}
+
+	if err := binRows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// GrowthOptions contains optional filters for the growth time series
+type GrowthOptions struct {
+	Language string // Only count this language (empty = all languages)
+}
+
+// GrowthLanguage is the size of one language at a sampled point in time
+type GrowthLanguage struct {
+	Language  string `json:"language"`
+	Files     int    `json:"files"`
+	Lines     int    `json:"lines"`
+	CodeLines int    `json:"code_lines"`
+}
+
+// GrowthPoint is the size of the codebase at the first commit of a week or month
+type GrowthPoint struct {
+	Date        string           `json:"date"` // Start of the sampled period
+	CommitHash  string           `json:"commit_hash"`
+	CommittedAt time.Time        `json:"committed_at"`
+	Files       int              `json:"files"`
+	Lines       int              `json:"lines"`
+	CodeLines   int              `json:"code_lines"`
+	Languages   []GrowthLanguage `json:"languages"` // Largest first
+}
+
+// GetGrowth returns the codebase size over time from the snapshots sampled by the worker, oldest first
+func GetGrowth(ctx context.Context, pool database.PgxIface, repositoryID int64, opts GrowthOptions) ([]GrowthPoint, error) {
+	args := []interface{}{repositoryID}
+	var filters string
+	if opts.Language != "" {
+		args = append(args, opts.Language)
+		filters = fmt.Sprintf(" AND language = $%d", len(args))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT TO_CHAR(period_start, 'YYYY-MM-DD') as date, commit_hash, committed_at, language, files, lines, code_lines
+		FROM snapshots
+		WHERE repository_id = $1%s
+		ORDER BY period_start ASC, code_lines DESC, language ASC
+	`, filters)
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query growth snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	points := []GrowthPoint{}
+	for rows.Next() {
+		var date, commitHash string
+		var committedAt time.Time
+		var l GrowthLanguage
+		if err := rows.Scan(&date, &commitHash, &committedAt, &l.Language, &l.Files, &l.Lines, &l.CodeLines); err != nil {
+			return nil, fmt.Errorf("failed to scan growth snapshot: %w", err)
+		}
+
+		// Rows are grouped by period, so a new date starts a new point
+		if len(points) == 0 || points[len(points)-1].Date != date {
+			points = append(points, GrowthPoint{
+				Date:        date,
+				CommitHash:  commitHash,
+				CommittedAt: committedAt,
+				Languages:   []GrowthLanguage{},
+			})
+		}
+		p := &points[len(points)-1]
+		p.Files += l.Files
+		p.Lines += l.Lines
+		p.CodeLines += l.CodeLines
+		p.Languages = append(p.Languages, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return points, nil
+}
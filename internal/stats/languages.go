@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// LanguageOptions contains optional filters for the language distribution
+type LanguageOptions struct {
+	ExcludeNonSource bool // Leave out vendored, generated and documentation files
+}
+
+// LanguageStats aggregates the text files at HEAD written in one language
+type LanguageStats struct {
+	Language     string  `json:"language"`
+	Files        int     `json:"files"`
+	CodeLines    int     `json:"code_lines"`
+	CommentLines int     `json:"comment_lines"`
+	BlankLines   int     `json:"blank_lines"`
+	Bytes        int64   `json:"bytes"`
+	CodePct      float64 `json:"code_pct"` // Share of the repository's code lines
+}
+
+// LanguageDistribution is the breakdown of a repository's code by language
+type LanguageDistribution struct {
+	TotalFiles        int             `json:"total_files"`
+	TotalCodeLines    int             `json:"total_code_lines"`
+	TotalCommentLines int             `json:"total_comment_lines"`
+	TotalBlankLines   int             `json:"total_blank_lines"`
+	Languages         []LanguageStats `json:"languages"` // Ordered by code lines, largest first
+}
+
+// GetLanguageDistribution aggregates the code, comment and blank lines of the text files at HEAD per language
+func GetLanguageDistribution(ctx context.Context, pool database.PgxIface, repositoryID int64, opts LanguageOptions) (*LanguageDistribution, error) {
+	var filters string
+	if opts.ExcludeNonSource {
+		filters = " AND NOT (is_vendored OR is_generated OR is_documentation)"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT
+			language,
+			COUNT(*) as files,
+			SUM(code_lines) as code_lines,
+			SUM(comment_lines) as comment_lines,
+			SUM(blank_lines) as blank_lines,
+			SUM(size_bytes) as bytes
+		FROM files
+		WHERE repository_id = $1 AND NOT is_binary%s
+		GROUP BY language
+		ORDER BY code_lines DESC, language ASC
+	`, filters)
+
+	rows, err := pool.Query(ctx, query, repositoryID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query language distribution: %w", err)
+	}
+	defer rows.Close()
+
+	result := &LanguageDistribution{
+		Languages: []LanguageStats{},
+	}
+
+	for rows.Next() {
+		var l LanguageStats
+		if err := rows.Scan(&l.Language, &l.Files, &l.CodeLines, &l.CommentLines, &l.BlankLines, &l.Bytes); err != nil {
+			return nil, fmt.Errorf("failed to scan language: %w", err)
+		}
+		result.TotalFiles += l.Files
+		result.TotalCodeLines += l.CodeLines
+		result.TotalCommentLines += l.CommentLines
+		result.TotalBlankLines += l.BlankLines
+		result.Languages = append(result.Languages, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for i := range result.Languages {
+		result.Languages[i].CodePct = percentage(result.Languages[i].CodeLines, result.TotalCodeLines)
+	}
+
+	return result, nil
+}
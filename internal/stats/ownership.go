@@ -0,0 +1,147 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// OwnershipOptions contains optional filters for the ownership matrix
+type OwnershipOptions struct {
+	Limit       int    // Files per page
+	Offset      int    // Files to skip
+	PathPrefix  string // Only files under this path (empty = all files)
+	ExcludeBots bool   // Leave out lines written by authors flagged as bots
+}
+
+// AuthorLines is an author's share of the surviving lines of a file or repository
+type AuthorLines struct {
+	Email string  `json:"email"`
+	Name  string  `json:"name"`
+	Lines int     `json:"lines"`
+	Pct   float64 `json:"pct"`
+}
+
+// FileOwnershipEntry lists who wrote the current lines of a file
+type FileOwnershipEntry struct {
+	FilePath   string        `json:"file_path"`
+	TotalLines int           `json:"total_lines"`
+	Authors    []AuthorLines `json:"authors"`
+}
+
+// OwnershipResult is the "who wrote the current code" matrix of a repository
+type OwnershipResult struct {
+	TotalLines int                  `json:"total_lines"`
+	Authors    []AuthorLines        `json:"authors"` // Repository-wide totals
+	Files      []FileOwnershipEntry `json:"files"`
+}
+
+// GetOwnership returns the blame based line ownership at HEAD by canonical identity. It is empty
+// unless the worker runs with blame ownership enabled.
+func GetOwnership(ctx context.Context, pool database.PgxIface, repositoryID int64, opts OwnershipOptions) (*OwnershipResult, error) {
+	pathPattern := escapeLike(opts.PathPrefix) + "%"
+
+	var botFilter string
+	if opts.ExcludeBots {
+		botFilter = database.NotBotCondition("fo.author_email")
+	}
+
+	result := &OwnershipResult{
+		Authors: []AuthorLines{},
+		Files:   []FileOwnershipEntry{},
+	}
+
+	// 1. Repository-wide totals
+	totalsQuery := `
+		SELECT ident.email, MAX(ident.name), SUM(fo.lines) as lines
+		FROM file_ownership fo` + database.IdentityJoin("ident", "fo.author_email", "fo.author_name") + `
+		WHERE fo.repository_id = $1 AND fo.file_path LIKE $2` + botFilter + `
+		GROUP BY ident.email
+		ORDER BY lines DESC
+	`
+
+	rows, err := pool.Query(ctx, totalsQuery, repositoryID, pathPattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query ownership totals: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a AuthorLines
+		if err := rows.Scan(&a.Email, &a.Name, &a.Lines); err != nil {
+			return nil, fmt.Errorf("failed to scan ownership totals: %w", err)
+		}
+		result.TotalLines += a.Lines
+		result.Authors = append(result.Authors, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for i := range result.Authors {
+		result.Authors[i].Pct = percentage(result.Authors[i].Lines, result.TotalLines)
+	}
+
+	// 2. Per-file breakdown for a page of files, largest first
+	filesQuery := `
+		WITH page AS (
+			SELECT fo.file_path, SUM(fo.lines) as total_lines
+			FROM file_ownership fo
+			WHERE fo.repository_id = $1 AND fo.file_path LIKE $2` + botFilter + `
+			GROUP BY fo.file_path
+			ORDER BY total_lines DESC, fo.file_path
+			LIMIT $3 OFFSET $4
+		)
+		SELECT p.file_path, p.total_lines, ident.email, MAX(ident.name), SUM(fo.lines) as lines
+		FROM page p
+		JOIN file_ownership fo ON fo.repository_id = $1 AND fo.file_path = p.file_path` + botFilter + database.IdentityJoin("ident", "fo.author_email", "fo.author_name") + `
+		GROUP BY p.file_path, p.total_lines, ident.email
+		ORDER BY p.total_lines DESC, p.file_path, lines DESC
+	`
+
+	fileRows, err := pool.Query(ctx, filesQuery, repositoryID, pathPattern, opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file ownership: %w", err)
+	}
+	defer fileRows.Close()
+
+	for fileRows.Next() {
+		var path string
+		var total int
+		var a AuthorLines
+		if err := fileRows.Scan(&path, &total, &a.Email, &a.Name, &a.Lines); err != nil {
+			return nil, fmt.Errorf("failed to scan file ownership: %w", err)
+		}
+		a.Pct = percentage(a.Lines, total)
+
+		// Rows are grouped by file, so a new path starts a new entry
+		if n := len(result.Files); n == 0 || result.Files[n-1].FilePath != path {
+			result.Files = append(result.Files, FileOwnershipEntry{FilePath: path, TotalLines: total})
+		}
+		entry := &result.Files[len(result.Files)-1]
+		entry.Authors = append(entry.Authors, a)
+	}
+
+	if err := fileRows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return result, nil
+}
+
+// percentage returns part/total in percent rounded to one decimal
+func percentage(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
+
+// escapeLike escapes the LIKE wildcards in a literal prefix
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
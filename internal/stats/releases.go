@@ -0,0 +1,128 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// ReleaseOptions contains optional filters for release statistics
+type ReleaseOptions struct {
+	Limit         int  // Releases per page
+	Offset        int  // Releases to skip
+	IncludeMerges bool // Whether merge commits are counted
+	ExcludeBots   bool // Leave out commits by authors flagged as bots
+}
+
+// ReleaseStats describes what a tag released since the previous one
+type ReleaseStats struct {
+	Tag               string    `json:"tag"`
+	CommitHash        string    `json:"commit_hash"`
+	IsAnnotated       bool      `json:"is_annotated"`
+	TaggedAt          time.Time `json:"tagged_at"`
+	PreviousTag       *string   `json:"previous_tag"` // nil for the first release
+	DaysSincePrevious *float64  `json:"days_since_previous"`
+	Commits           int       `json:"commits"`
+	Contributors      int       `json:"contributors"`
+	FilesChanged      int       `json:"files_changed"`
+	LinesAdded        int       `json:"lines_added"`
+	LinesRemoved      int       `json:"lines_removed"`
+}
+
+// GetReleases returns per-release statistics, newest first. A release covers the commits reachable
+// from its tag that no earlier tag contains, as recorded by the worker.
+func GetReleases(ctx context.Context, pool database.PgxIface, repositoryID int64, opts ReleaseOptions) ([]ReleaseStats, error) {
+	var commitFilter string
+	if !opts.IncludeMerges {
+		commitFilter = " AND NOT c.is_merge"
+	}
+	if opts.ExcludeBots {
+		commitFilter += database.NotBotCondition("c.author_email")
+	}
+
+	query := fmt.Sprintf(`
+		WITH commit_stats AS (
+			SELECT
+				rc.tag_name,
+				COUNT(*) as commits,
+				COUNT(DISTINCT ident.email) as contributors
+			FROM release_commits rc
+			JOIN commits c ON c.repository_id = rc.repository_id AND c.hash = rc.commit_hash%s
+			WHERE rc.repository_id = $1%s
+			GROUP BY rc.tag_name
+		),
+		file_stats AS (
+			SELECT
+				rc.tag_name,
+				COUNT(DISTINCT cf.file_path) as files_changed,
+				SUM(cf.additions) as lines_added,
+				SUM(cf.deletions) as lines_removed
+			FROM release_commits rc
+			JOIN commits c ON c.repository_id = rc.repository_id AND c.hash = rc.commit_hash
+			JOIN commit_files cf ON cf.repository_id = c.repository_id AND cf.commit_hash = c.hash
+			WHERE rc.repository_id = $1%s
+			GROUP BY rc.tag_name
+		)
+		SELECT
+			t.name,
+			t.commit_hash,
+			t.is_annotated,
+			t.tagged_at,
+			LAG(t.name) OVER releases as previous_tag,
+			LAG(t.tagged_at) OVER releases as previous_tagged_at,
+			COALESCE(cs.commits, 0),
+			COALESCE(cs.contributors, 0),
+			COALESCE(fs.files_changed, 0),
+			COALESCE(fs.lines_added, 0),
+			COALESCE(fs.lines_removed, 0)
+		FROM tags t
+		LEFT JOIN commit_stats cs ON cs.tag_name = t.name
+		LEFT JOIN file_stats fs ON fs.tag_name = t.name
+		WHERE t.repository_id = $1
+		WINDOW releases AS (ORDER BY t.tagged_at, t.name)
+		ORDER BY t.tagged_at DESC, t.name DESC
+		LIMIT $2 OFFSET $3
+	`, database.IdentityJoin("ident", "c.author_email", "c.author_name"), commitFilter, commitFilter)
+
+	rows, err := pool.Query(ctx, query, repositoryID, opts.Limit, opts.Offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query releases: %w", err)
+	}
+	defer rows.Close()
+
+	releases := []ReleaseStats{}
+	for rows.Next() {
+		var r ReleaseStats
+		var previousTaggedAt *time.Time
+		err := rows.Scan(
+			&r.Tag,
+			&r.CommitHash,
+			&r.IsAnnotated,
+			&r.TaggedAt,
+			&r.PreviousTag,
+			&previousTaggedAt,
+			&r.Commits,
+			&r.Contributors,
+			&r.FilesChanged,
+			&r.LinesAdded,
+			&r.LinesRemoved,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan release: %w", err)
+		}
+		if previousTaggedAt != nil {
+			days := math.Round(r.TaggedAt.Sub(*previousTaggedAt).Hours()/24*10) / 10
+			r.DaysSincePrevious = &days
+		}
+		releases = append(releases, r)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return releases, nil
+}
@@ -0,0 +1,120 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// fileLineageCTE resolves every renamed path of repository $1 to the path its content ended up at.
+// Each rename is followed through later renames of the new path (at most 32 hops to stop cycles).
+// It must be placed after WITH RECURSIVE.
+const fileLineageCTE = `
+	renames AS (
+		SELECT cf.old_path, cf.file_path, c.committed_at
+		FROM commit_files cf
+		JOIN commits c ON c.hash = cf.commit_hash AND c.repository_id = cf.repository_id
+		WHERE cf.repository_id = $1 AND cf.change_type = 'renamed'
+	),
+	lineage AS (
+		SELECT old_path, file_path AS current_path, committed_at AS renamed_at, committed_at AS last_renamed_at, 1 AS depth
+		FROM renames
+		UNION ALL
+		SELECT l.old_path, r.file_path, l.renamed_at, r.committed_at, l.depth + 1
+		FROM lineage l
+		JOIN renames r ON r.old_path = l.current_path AND r.committed_at >= l.last_renamed_at
+		WHERE l.depth < 32
+	),
+	file_lineage AS (
+		SELECT DISTINCT ON (old_path, renamed_at) old_path, current_path, renamed_at
+		FROM lineage
+		ORDER BY old_path, renamed_at, depth DESC
+	)`
+
+// currentPathJoin maps a commit_files row (cf joined with commits c) to the lineage of the first
+// rename of its path after the commit. A path reused after a rename starts a new lineage.
+const currentPathJoin = `
+	LEFT JOIN LATERAL (
+		SELECT lin.current_path
+		FROM file_lineage lin
+		WHERE lin.old_path = cf.file_path AND lin.renamed_at >= c.committed_at
+		ORDER BY lin.renamed_at
+		LIMIT 1
+	) fl ON true`
+
+// currentPathExpr is the path a commit_files row is attributed to when following renames
+const currentPathExpr = "COALESCE(fl.current_path, cf.file_path)"
+
+// FileHistoryEntry is a single commit in the history of a file
+type FileHistoryEntry struct {
+	CommitHash  string              `json:"commit_hash"`
+	AuthorName  string              `json:"author_name"` // Canonical identity (.mailmap and merged aliases)
+	AuthorEmail string              `json:"author_email"`
+	Message     string              `json:"message"`
+	CommittedAt time.Time           `json:"committed_at"`
+	FilePath    string              `json:"file_path"` // Path at the time of the commit
+	OldPath     *string             `json:"old_path,omitempty"`
+	ChangeType  database.ChangeType `json:"change_type"`
+	Additions   int                 `json:"additions"`
+	Deletions   int                 `json:"deletions"`
+}
+
+// GetFileHistory returns the commits that touched a file, newest first, including the
+// commits made under its previous names. path is the file's current path; a non-empty
+// branch only includes commits reachable from that branch.
+func GetFileHistory(ctx context.Context, pool database.PgxIface, repositoryID int64, path string, branch string, excludeBots bool, limit int) ([]FileHistoryEntry, error) {
+	args := []interface{}{repositoryID, path, limit}
+	var branchFilter string
+	if branch != "" {
+		args = append(args, branch)
+		branchFilter = branchCondition(len(args))
+	}
+	if excludeBots {
+		branchFilter += database.NotBotCondition("c.author_email")
+	}
+
+	query := fmt.Sprintf(`
+		WITH RECURSIVE %s
+		SELECT
+			c.hash,
+			ident.name,
+			ident.email,
+			c.message,
+			c.committed_at,
+			cf.file_path,
+			cf.old_path,
+			cf.change_type,
+			cf.additions,
+			cf.deletions
+		FROM commit_files cf
+		JOIN commits c ON c.hash = cf.commit_hash AND c.repository_id = cf.repository_id
+		%s%s
+		WHERE cf.repository_id = $1 AND %s = $2 %s
+		ORDER BY c.committed_at DESC
+		LIMIT $3
+	`, fileLineageCTE, currentPathJoin, database.IdentityJoin("ident", "c.author_email", "c.author_name"), currentPathExpr, branchFilter)
+
+	rows, err := pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query file history: %w", err)
+	}
+	defer rows.Close()
+
+	history := []FileHistoryEntry{}
+	for rows.Next() {
+		var e FileHistoryEntry
+		if err := rows.Scan(&e.CommitHash, &e.AuthorName, &e.AuthorEmail, &e.Message, &e.CommittedAt,
+			&e.FilePath, &e.OldPath, &e.ChangeType, &e.Additions, &e.Deletions); err != nil {
+			return nil, fmt.Errorf("failed to scan file history row: %w", err)
+		}
+		history = append(history, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,202 @@
+package stats
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// Timeline buckets of the signing coverage
+const (
+	SigningIntervalWeek  = "week"
+	SigningIntervalMonth = "month"
+)
+
+// SigningOptions contains optional filters for the signing coverage
+type SigningOptions struct {
+	Interval      string // Timeline bucket: SigningIntervalWeek or SigningIntervalMonth (default)
+	Days          int    // Only count commits of the last N days (0 = whole history)
+	Branch        string // Only count commits reachable from this branch (empty = all indexed branches)
+	IncludeMerges bool   // Whether merge commits are counted
+	Limit         int    // Contributors per page
+	Offset        int    // Contributors to skip
+	ExcludeBots   bool   // Leave out commits by authors flagged as bots
+}
+
+// SigningCoverage counts commits per signature status and type
+type SigningCoverage struct {
+	Total       int                            `json:"total"`
+	Signed      int                            `json:"signed"`     // Verified and unverified commits
+	Verified    int                            `json:"verified"`   // Signed by a key trusted for the repository
+	Unverified  int                            `json:"unverified"` // Signed by an unknown key, or the signature does not match
+	Unsigned    int                            `json:"unsigned"`
+	Types       map[database.SignatureType]int `json:"types"` // Signed commits per signature type; every type is present
+	SignedPct   float64                        `json:"signed_pct"`
+	VerifiedPct float64                        `json:"verified_pct"`
+}
+
+// SigningPeriod is the signing coverage of a week or month
+type SigningPeriod struct {
+	Date string `json:"date"` // Start of the period
+	SigningCoverage
+}
+
+// SigningContributor is the signing coverage of a contributor (canonical identity)
+type SigningContributor struct {
+	Email string `json:"email"`
+	Name  string `json:"name"`
+	SigningCoverage
+}
+
+// SigningResult is the signing coverage of a repository overall, over time and per contributor
+type SigningResult struct {
+	SigningCoverage
+	Timeline     []SigningPeriod      `json:"timeline"`     // Oldest first
+	Contributors []SigningContributor `json:"contributors"` // Most commits first
+}
+
+func newSigningCoverage() SigningCoverage {
+	return SigningCoverage{Types: map[database.SignatureType]int{
+		database.SignatureTypePGP:   0,
+		database.SignatureTypeSSH:   0,
+		database.SignatureTypeOther: 0,
+	}}
+}
+
+func (s *SigningCoverage) add(sigType *database.SignatureType, status database.SignatureStatus, count int) {
+	s.Total += count
+	switch status {
+	case database.SignatureVerified:
+		s.Verified += count
+	case database.SignatureUnverified:
+		s.Unverified += count
+	default:
+		s.Unsigned += count
+	}
+	if sigType != nil && status != database.SignatureUnsigned {
+		s.Types[*sigType] += count
+	}
+	s.Signed = s.Verified + s.Unverified
+	s.SignedPct = percentage(s.Signed, s.Total)
+	s.VerifiedPct = percentage(s.Verified, s.Total)
+}
+
+// GetSigningCoverage returns the share of signed commits as recorded by the worker. Whether a
+// signature is verified depends on the keys trusted when the commit was last indexed.
+func GetSigningCoverage(ctx context.Context, pool database.PgxIface, repositoryID int64, opts SigningOptions) (*SigningResult, error) {
+	interval := opts.Interval
+	if interval != SigningIntervalWeek {
+		interval = SigningIntervalMonth
+	}
+
+	args := []interface{}{repositoryID}
+	var filters string
+	if opts.Days > 0 {
+		args = append(args, time.Now().AddDate(0, 0, -opts.Days))
+		filters += fmt.Sprintf(" AND c.committed_at >= $%d", len(args))
+	}
+	if opts.Branch != "" {
+		args = append(args, opts.Branch)
+		filters += branchCondition(len(args))
+	}
+	if !opts.IncludeMerges {
+		filters += " AND NOT c.is_merge"
+	}
+	if opts.ExcludeBots {
+		filters += database.NotBotCondition("c.author_email")
+	}
+
+	result := &SigningResult{
+		SigningCoverage: newSigningCoverage(),
+		Timeline:        []SigningPeriod{},
+		Contributors:    []SigningContributor{},
+	}
+
+	// 1. Timeline, which also gives the totals. Weeks start on Monday.
+	timelineQuery := fmt.Sprintf(`
+		SELECT TO_CHAR(DATE_TRUNC('%s', c.committed_at AT TIME ZONE 'UTC'), 'YYYY-MM-DD') as date, c.signature_type, c.signature_status, COUNT(*)
+		FROM commits c
+		WHERE c.repository_id = $1%s
+		GROUP BY date, c.signature_type, c.signature_status
+		ORDER BY date ASC
+	`, interval, filters)
+
+	rows, err := pool.Query(ctx, timelineQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signing timeline: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var date string
+		var sigType *database.SignatureType
+		var status database.SignatureStatus
+		var count int
+		if err := rows.Scan(&date, &sigType, &status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan signing timeline: %w", err)
+		}
+
+		// Rows are grouped by period, so a new date starts a new period
+		if n := len(result.Timeline); n == 0 || result.Timeline[n-1].Date != date {
+			result.Timeline = append(result.Timeline, SigningPeriod{Date: date, SigningCoverage: newSigningCoverage()})
+		}
+		result.Timeline[len(result.Timeline)-1].add(sigType, status, count)
+		result.add(sigType, status, count)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	// 2. Contributors by canonical identity
+	contributorsQuery := fmt.Sprintf(`
+		SELECT ident.email, MAX(ident.name), c.signature_type, c.signature_status, COUNT(*)
+		FROM commits c%s
+		WHERE c.repository_id = $1%s
+		GROUP BY ident.email, c.signature_type, c.signature_status
+	`, database.IdentityJoin("ident", "c.author_email", "c.author_name"), filters)
+
+	contributorRows, err := pool.Query(ctx, contributorsQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query signing per contributor: %w", err)
+	}
+	defer contributorRows.Close()
+
+	contributors := make(map[string]*SigningContributor)
+	for contributorRows.Next() {
+		var email, name string
+		var sigType *database.SignatureType
+		var status database.SignatureStatus
+		var count int
+		if err := contributorRows.Scan(&email, &name, &sigType, &status, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan signing per contributor: %w", err)
+		}
+		c, ok := contributors[email]
+		if !ok {
+			c = &SigningContributor{Email: email, Name: name, SigningCoverage: newSigningCoverage()}
+			contributors[email] = c
+		}
+		c.add(sigType, status, count)
+	}
+
+	if err := contributorRows.Err(); err != nil {
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	for _, c := range contributors {
+		result.Contributors = append(result.Contributors, *c)
+	}
+	sort.Slice(result.Contributors, func(i, j int) bool {
+		a, b := result.Contributors[i], result.Contributors[j]
+		if a.Total != b.Total {
+			return a.Total > b.Total
+		}
+		return a.Email < b.Email
+	})
+	result.Contributors = page(result.Contributors, opts.Limit, opts.Offset)
+
+	return result, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// bundleDir holds the git bundles uploaded for repositories the workers cannot clone. Unlike the
+// clones next to it, a bundle is the only copy of its repository and is never evicted.
+const bundleDir = "bundles"
+
+// NewBundlePath returns an unused path under root for a new upload
+func NewBundlePath(root string) (string, error) {
+	name := make([]byte, 16)
+	if _, err := rand.Read(name); err != nil {
+		return "", fmt.Errorf("failed to name bundle: %w", err)
+	}
+	return filepath.Join(root, bundleDir, hex.EncodeToString(name)+".bundle"), nil
+}
+
+// IsBundlePath reports whether path is an upload stored under root
+func IsBundlePath(root, path string) bool {
+	return filepath.Dir(filepath.Clean(path)) == filepath.Join(root, bundleDir) && strings.HasSuffix(path, ".bundle")
+}
+
+// SaveBundle stores an upload at path. It is written aside first, so the previous upload at path
+// is only replaced once validate accepted the new one.
+func SaveBundle(path string, r io.Reader, validate func(io.Reader) error) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create bundle directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create bundle: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to read bundle: %w", err)
+	}
+	if err := validate(tmp); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write bundle: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to store bundle: %w", err)
+	}
+	return nil
+}
+
+// RemoveBundle deletes the upload a repository was imported from. Paths outside of the bundle
+// directory, i.e. repositories cloned from elsewhere, are left alone.
+func (m *Manager) RemoveBundle(path string) error {
+	return RemoveBundle(m.root, path)
+}
+
+// RemoveBundle deletes an upload stored under root
+func RemoveBundle(root, path string) error {
+	if !IsBundlePath(root, path) {
+		return nil
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove bundle: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSaveBundle(t *testing.T) {
+	root := t.TempDir()
+	path, err := NewBundlePath(root)
+	if err != nil {
+		t.Fatalf("NewBundlePath() error = %v", err)
+	}
+	if !IsBundlePath(root, path) {
+		t.Fatalf("IsBundlePath(%s) = false, want true", path)
+	}
+	if IsBundlePath(root, filepath.Join(root, "1")) || IsBundlePath(root, "/srv/git/repo.bundle") {
+		t.Error("IsBundlePath() = true for a path outside the bundle directory")
+	}
+
+	accept := func(io.Reader) error { return nil }
+	if err := SaveBundle(path, strings.NewReader("first"), accept); err != nil {
+		t.Fatalf("SaveBundle() error = %v", err)
+	}
+
+	// A rejected upload leaves the previous one in place
+	reject := func(r io.Reader) error {
+		data, _ := io.ReadAll(r)
+		return fmt.Errorf("rejected %q", data)
+	}
+	if err := SaveBundle(path, strings.NewReader("second"), reject); err == nil || err.Error() != `rejected "second"` {
+		t.Fatalf("SaveBundle() error = %v, want the validation error", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != "first" {
+		t.Errorf("bundle = %q, %v, want first", data, err)
+	}
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil || len(entries) != 1 {
+		t.Errorf("bundle directory has %d entries (%v), want only the bundle", len(entries), err)
+	}
+
+	if err := RemoveBundle(root, path); err != nil {
+		t.Fatalf("RemoveBundle() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("bundle still exists after RemoveBundle(): %v", err)
+	}
+}
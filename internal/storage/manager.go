@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"git-repository-visualizer/internal/database"
+)
+
+// Manager keeps track of the disk usage of the clones under the worker storage path.
+// When the total exceeds the quota the least recently used clones are removed; they are
+// cloned again by the next update of their repository.
+type Manager struct {
+	db    *database.DB
+	root  string
+	quota int64 // Bytes (0 = unlimited)
+	mu    sync.Mutex
+}
+
+// NewManager creates a storage manager for the clones under root. quota is in bytes, 0 disables eviction.
+func NewManager(db *database.DB, root string, quota int64) *Manager {
+	return &Manager{
+		db:    db,
+		root:  root,
+		quota: quota,
+	}
+}
+
+// Path returns where the clone of a repository is stored
+func (m *Manager) Path(repoID int64) string {
+	return filepath.Join(m.root, strconv.FormatInt(repoID, 10))
+}
+
+// Record measures the clone of a repository after an index or update, marks it as just used and
+// evicts other clones until the total is back under the quota
+func (m *Manager) Record(ctx context.Context, repoID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	path := m.Path(repoID)
+	size, err := dirSize(path)
+	if err != nil {
+		return fmt.Errorf("failed to measure clone: %w", err)
+	}
+
+	if err := m.db.UpsertCloneStorage(ctx, &database.CloneStorage{RepositoryID: repoID, Path: path, SizeBytes: size}); err != nil {
+		return err
+	}
+
+	return m.enforceQuota(ctx, repoID)
+}
+
+// Remove deletes the clone of a repository and stops tracking it
+func (m *Manager) Remove(ctx context.Context, repoID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.remove(ctx, repoID)
+}
+
+func (m *Manager) remove(ctx context.Context, repoID int64) error {
+	if err := os.RemoveAll(m.Path(repoID)); err != nil {
+		return fmt.Errorf("failed to remove clone: %w", err)
+	}
+	return m.db.DeleteCloneStorage(ctx, repoID)
+}
+
+// enforceQuota evicts the least recently used clones, other than keep, while the total exceeds the quota
+func (m *Manager) enforceQuota(ctx context.Context, keep int64) error {
+	if m.quota <= 0 {
+		return nil
+	}
+
+	total, err := m.db.GetTotalCloneStorage(ctx)
+	if err != nil {
+		return err
+	}
+	if total <= m.quota {
+		return nil
+	}
+
+	clones, err := m.db.ListEvictableClones(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range selectEvictions(clones, total, m.quota, keep) {
+		log.Printf("Evicting clone of repository %d (%d bytes, last used %s)", c.RepositoryID, c.SizeBytes, c.LastUsedAt)
+		if err := m.remove(ctx, c.RepositoryID); err != nil {
+			return fmt.Errorf("failed to evict clone of repository %d: %w", c.RepositoryID, err)
+		}
+	}
+	return nil
+}
+
+// selectEvictions picks clones from the least recently used ones, in order, until removing them
+// brings total under quota. keep is never picked. If the evictable clones do not free enough
+// space, all of them are picked.
+func selectEvictions(clones []*database.CloneStorage, total, quota, keep int64) []*database.CloneStorage {
+	var evict []*database.CloneStorage
+	for _, c := range clones {
+		if total <= quota {
+			break
+		}
+		if c.RepositoryID == keep {
+			continue
+		}
+		evict = append(evict, c)
+		total -= c.SizeBytes
+	}
+	return evict
+}
+
+// dirSize returns the total size of the regular files under path, 0 if it does not exist
+func dirSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	return size, err
+}
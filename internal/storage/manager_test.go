@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"git-repository-visualizer/internal/database"
+)
+
+func TestSelectEvictions(t *testing.T) {
+	now := time.Now()
+	clones := []*database.CloneStorage{
+		{RepositoryID: 1, SizeBytes: 300, LastUsedAt: now.Add(-3 * time.Hour)},
+		{RepositoryID: 2, SizeBytes: 200, LastUsedAt: now.Add(-2 * time.Hour)},
+		{RepositoryID: 3, SizeBytes: 500, LastUsedAt: now.Add(-time.Hour)},
+		{RepositoryID: 4, SizeBytes: 100, LastUsedAt: now},
+	}
+
+	tests := []struct {
+		name  string
+		total int64
+		quota int64
+		keep  int64
+		want  []int64
+	}{
+		{name: "under quota", total: 1100, quota: 2000, keep: 4, want: nil},
+		{name: "least recently used first", total: 1100, quota: 900, keep: 4, want: []int64{1}},
+		{name: "until under quota", total: 1100, quota: 700, keep: 4, want: []int64{1, 2}},
+		{name: "skips kept clone", total: 1100, quota: 700, keep: 1, want: []int64{2, 3}},
+		{name: "not enough to free", total: 1100, quota: 50, keep: 4, want: []int64{1, 2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []int64
+			for _, c := range selectEvictions(clones, tt.total, tt.quota, tt.keep) {
+				got = append(got, c.RepositoryID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("selectEvictions() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("selectEvictions() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDirSize(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "objects", "pack"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]int{
+		"HEAD":                        23,
+		"objects/pack/pack-1.pack":    1000,
+		"objects/pack/pack-1.idx":     200,
+		"objects/info/alternates.txt": 0,
+	}
+	for name, size := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	size, err := dirSize(root)
+	if err != nil {
+		t.Fatalf("dirSize() error = %v", err)
+	}
+	if size != 1223 {
+		t.Errorf("dirSize() = %d, want 1223", size)
+	}
+
+	size, err = dirSize(filepath.Join(root, "missing"))
+	if err != nil || size != 0 {
+		t.Errorf("dirSize(missing) = %d, %v, want 0, nil", size, err)
+	}
+}
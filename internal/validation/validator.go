@@ -97,12 +97,12 @@ func (v *Validator) GitURL(field, value string) *Validator {
 		return v
 	}
 
-	// Support HTTP(S) and SSH Git URLs
-	httpPattern := regexp.MustCompile(`^https?://[^/]+/.+\.git$|^https?://[^/]+/.+$`)
-	sshPattern := regexp.MustCompile(`^git@[^:]+:.+\.git$|^ssh://git@[^/]+/.+$`)
+	// Support HTTP(S), git:// and SSH Git URLs (both ssh:// and scp-like user@host:path)
+	httpPattern := regexp.MustCompile(`^(https?|git)://[^/]+/.+$`)
+	sshPattern := regexp.MustCompile(`^[\w.-]+@[^:/]+:.+$|^ssh://([^@/]+@)?[^/]+/.+$`)
 
 	if !httpPattern.MatchString(value) && !sshPattern.MatchString(value) {
-		v.errors.Add(field, fmt.Sprintf("%s must be a valid Git repository URL (HTTP(S) or SSH)", field))
+		v.errors.Add(field, fmt.Sprintf("%s must be a valid Git repository URL (HTTP(S), git or SSH)", field))
 	}
 	return v
 }
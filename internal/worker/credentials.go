@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/git"
+
+	"golang.org/x/oauth2"
+)
+
+// resolveCredentials collects the secrets needed to clone a repository: its deploy key for SSH URLs
+// and the owner's OAuth token for the repository's provider, refreshed if it has expired.
+func (h *JobHandler) resolveCredentials(ctx context.Context, repo *database.Repository) (*git.Credentials, error) {
+	creds := &git.Credentials{}
+
+	// 1. Deploy key
+	key, err := h.db.GetDeployKey(ctx, repo.ID)
+	if err == nil {
+		creds.DeployKey = []byte(key.PrivateKey)
+		if key.Passphrase != nil {
+			creds.DeployKeyPassphrase = *key.Passphrase
+		}
+	} else if !errors.Is(err, database.ErrNotFound) {
+		return nil, fmt.Errorf("failed to get deploy key: %w", err)
+	}
+
+	// 2. Owner's provider token
+	if repo.UserID == nil || repo.Provider == nil {
+		return creds, nil
+	}
+
+	identity, err := h.db.GetUserIdentity(ctx, *repo.UserID, *repo.Provider)
+	if err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return creds, nil
+		}
+		return nil, fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	if identity.AccessToken == nil {
+		return creds, nil
+	}
+
+	token := identityToken(identity)
+
+	p, err := h.authRegistry.Get(*repo.Provider)
+	if err != nil {
+		// Provider is not configured on this worker, so the token can't be refreshed; use it as stored
+		log.Printf("Cannot refresh %s token for repository %d: %v", *repo.Provider, repo.ID, err)
+		creds.Token = token.AccessToken
+		return creds, nil
+	}
+
+	fresh, err := p.TokenSource(ctx, token).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh %s token: %w", *repo.Provider, err)
+	}
+
+	// 3. Persist refreshed token so other jobs don't refresh it again
+	if fresh.AccessToken != token.AccessToken {
+		identity.AccessToken = &fresh.AccessToken
+		if fresh.RefreshToken != "" {
+			identity.RefreshToken = &fresh.RefreshToken
+		}
+		expiry := fresh.Expiry
+		identity.TokenExpiry = &expiry
+
+		if err := h.db.UpdateUserIdentityToken(ctx, identity); err != nil {
+			return nil, fmt.Errorf("failed to store refreshed token: %w", err)
+		}
+	}
+
+	creds.Token = fresh.AccessToken
+	return creds, nil
+}
+
+// identityToken reconstructs the OAuth token stored for an identity
+func identityToken(identity *database.UserIdentity) *oauth2.Token {
+	token := &oauth2.Token{
+		AccessToken: *identity.AccessToken,
+	}
+	if identity.RefreshToken != nil {
+		token.RefreshToken = *identity.RefreshToken
+	}
+	if identity.TokenExpiry != nil {
+		token.Expiry = *identity.TokenExpiry
+	}
+	return token
+}
@@ -2,32 +2,95 @@ package worker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"path/filepath"
+	"strings"
 	"time"
 
 	"git-repository-visualizer/internal/auth"
+	"git-repository-visualizer/internal/bots"
 	"git-repository-visualizer/internal/database"
 	"git-repository-visualizer/internal/git"
 	"git-repository-visualizer/internal/queue"
-
-	"golang.org/x/oauth2"
+	"git-repository-visualizer/internal/signing"
+	"git-repository-visualizer/internal/storage"
 )
 
 // JobHandler implements the queue.JobHandler interface
 type JobHandler struct {
 	db           *database.DB
-	storagePath  string
+	clones       *storage.Manager
 	authRegistry *auth.Registry
+	gitServices  *git.Registry
+	processOpts  git.ProcessOptions
 }
 
 // NewJobHandler creates a new job handler
-func NewJobHandler(db *database.DB, storagePath string, registry *auth.Registry) *JobHandler {
+func NewJobHandler(db *database.DB, clones *storage.Manager, registry *auth.Registry, services *git.Registry, opts git.ProcessOptions) *JobHandler {
 	return &JobHandler{
 		db:           db,
-		storagePath:  storagePath,
+		clones:       clones,
 		authRegistry: registry,
+		gitServices:  services,
+		processOpts:  opts,
+	}
+}
+
+// processOptions returns the worker's processing options with the repository's branch selection, history mode,
+// bot patterns and trusted signing keys. Progress is recorded in the database for the status endpoint.
+func (h *JobHandler) processOptions(ctx context.Context, repo *database.Repository) (git.ProcessOptions, error) {
+	keys, err := h.db.ListSigningKeys(ctx, repo.ID)
+	if err != nil {
+		return git.ProcessOptions{}, err
+	}
+	publicKeys := make([]string, len(keys))
+	for i, key := range keys {
+		publicKeys[i] = key.PublicKey
+	}
+
+	opts := h.processOpts
+	opts.Branches = git.BranchSelection{
+		Default:  repo.DefaultBranch,
+		Mode:     repo.BranchMode,
+		Branches: repo.Branches,
+	}
+	opts.FirstParent = repo.FirstParent
+	opts.Since = repo.CloneSince
+	opts.Bots = bots.NewDetector(repo.BotPatterns)
+	opts.Keyring = signing.NewKeyring(publicKeys)
+	opts.Progress = h.progressReporter(ctx, repo.ID)
+	return opts, nil
+}
+
+// progressReporter persists the progress of an index. Failures are logged and never stop the index.
+func (h *JobHandler) progressReporter(ctx context.Context, repoID int64) git.ProgressFunc {
+	started := time.Now()
+	return func(p git.Progress) {
+		progress := &database.IndexingProgress{
+			RepositoryID:     repoID,
+			Phase:            p.Phase,
+			Percent:          p.Percent,
+			CommitsProcessed: p.CommitsProcessed,
+			CommitsTotal:     p.CommitsTotal,
+			StartedAt:        started,
+		}
+		if p.ETA != nil {
+			seconds := int(p.ETA.Seconds())
+			progress.ETASeconds = &seconds
+		}
+		if err := h.db.UpsertIndexingProgress(ctx, progress); err != nil {
+			log.Printf("Failed to record progress of repository %d: %v", repoID, err)
+		}
+	}
+}
+
+// cloneOptions returns the repository's clone depth and branch settings
+func cloneOptions(repo *database.Repository) git.CloneOptions {
+	return git.CloneOptions{
+		Depth:        repo.CloneDepth,
+		SingleBranch: repo.SingleBranch,
+		Branch:       repo.DefaultBranch,
 	}
 }
 
@@ -68,10 +131,24 @@ func (h *JobHandler) handleIndexJob(ctx context.Context, job *queue.Job) error {
 	log.Printf("Indexing repository: %s (ID: %d)", repo.URL, repoID)
 
 	// Construct local path for cloning
-	localPath := filepath.Join(h.storagePath, fmt.Sprintf("%d", repoID))
+	localPath := h.clones.Path(repoID)
+
+	// Resolve credentials for private repositories
+	creds, err := h.resolveCredentials(ctx, repo)
+	if err != nil {
+		h.db.UpdateRepositoryStatus(ctx, repoID, database.StatusFailed)
+		return fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+
+	opts, err := h.processOptions(ctx, repo)
+	if err != nil {
+		h.db.UpdateRepositoryStatus(ctx, repoID, database.StatusFailed)
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
 
 	// Index the repository
-	if err := git.IndexRepository(ctx, h.db, repoID, repo.URL, localPath); err != nil {
+	result, err := git.IndexRepository(ctx, h.db, h.gitServices, repoID, repo.URL, localPath, creds, cloneOptions(repo), opts)
+	if err != nil {
 		h.db.UpdateRepositoryStatus(ctx, repoID, database.StatusFailed)
 		return fmt.Errorf("failed to index repository: %w", err)
 	}
@@ -79,6 +156,8 @@ func (h *JobHandler) handleIndexJob(ctx context.Context, job *queue.Job) error {
 	// Update repository status to completed
 	now := time.Now()
 	repo.LastIndexedAt = &now
+	repo.LastIndexedCommit = &result.HeadCommit
+	repo.HistoryTruncated = result.HistoryTruncated
 	repo.Status = database.StatusCompleted
 	repo.LocalPath = &localPath
 
@@ -87,6 +166,8 @@ func (h *JobHandler) handleIndexJob(ctx context.Context, job *queue.Job) error {
 		return fmt.Errorf("failed to update repository: %w", err)
 	}
 
+	h.recordClone(ctx, repoID)
+
 	log.Printf("Successfully indexed repository %d", repoID)
 	return nil
 }
@@ -107,15 +188,40 @@ func (h *JobHandler) handleUpdateJob(ctx context.Context, job *queue.Job) error
 		return fmt.Errorf("failed to get repository: %w", err)
 	}
 
+	// Without a previous index there is nothing to update incrementally
+	if repo.LocalPath == nil || repo.LastIndexedCommit == nil {
+		log.Printf("Repository %d has not been indexed yet, running full index", repoID)
+		return h.handleIndexJob(ctx, job)
+	}
+
 	log.Printf("Updating repository: %s (ID: %d)", repo.URL, repoID)
 
-	// TODO: Implement actual git pull and re-analysis logic
-	// For now, simulate processing with a delay
-	time.Sleep(2 * time.Second)
+	// Resolve credentials for private repositories
+	creds, err := h.resolveCredentials(ctx, repo)
+	if err != nil {
+		h.db.UpdateRepositoryStatus(ctx, repoID, database.StatusFailed)
+		return fmt.Errorf("failed to resolve credentials: %w", err)
+	}
+
+	opts, err := h.processOptions(ctx, repo)
+	if err != nil {
+		h.db.UpdateRepositoryStatus(ctx, repoID, database.StatusFailed)
+		return fmt.Errorf("failed to load signing keys: %w", err)
+	}
 
-	// Simulate successful update
+	// Fetch and index only the new commits
+	result, err := git.UpdateRepository(ctx, h.db, h.gitServices, repoID, repo.URL, *repo.LocalPath, *repo.LastIndexedCommit, creds, cloneOptions(repo), opts)
+	if err != nil {
+		h.db.UpdateRepositoryStatus(ctx, repoID, database.StatusFailed)
+		return fmt.Errorf("failed to update repository: %w", err)
+	}
+
+	// Update repository status to completed
 	now := time.Now()
 	repo.LastIndexedAt = &now
+	repo.LastIndexedCommit = &result.HeadCommit
+	// The new commits say nothing about how far back the already indexed history goes
+	repo.HistoryTruncated = repo.HistoryTruncated || result.HistoryTruncated
 	repo.Status = database.StatusCompleted
 
 	if err := h.db.UpdateRepository(ctx, repo); err != nil {
@@ -123,6 +229,8 @@ func (h *JobHandler) handleUpdateJob(ctx context.Context, job *queue.Job) error
 		return fmt.Errorf("failed to update repository: %w", err)
 	}
 
+	h.recordClone(ctx, repoID)
+
 	log.Printf("Successfully updated repository %d", repoID)
 	return nil
 }
@@ -132,14 +240,39 @@ func (h *JobHandler) handleDeleteJob(ctx context.Context, job *queue.Job) error
 	repoID := job.RepositoryID
 	log.Printf("Deleting repository data for ID: %d", repoID)
 
-	// TODO: Implement repository cleanup logic
-	// - Remove local git clone
-	// - Clean up database records
+	if err := h.clones.Remove(ctx, repoID); err != nil {
+		return fmt.Errorf("failed to remove clone: %w", err)
+	}
+
+	// A repository imported from an uploaded bundle has no other copy to keep it for
+	repo, err := h.db.GetRepository(ctx, repoID)
+	if err != nil && !errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("failed to get repository: %w", err)
+	}
+	if repo != nil {
+		if path, ok := strings.CutPrefix(repo.URL, "file://"); ok {
+			if err := h.clones.RemoveBundle(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := h.db.DeleteRepository(ctx, repoID); err != nil && !errors.Is(err, database.ErrNotFound) {
+		return fmt.Errorf("failed to delete repository: %w", err)
+	}
 
 	log.Printf("Successfully deleted repository %d", repoID)
 	return nil
 }
 
+// recordClone updates the disk usage of a repository's clone, evicting others when over quota.
+// Failures are logged and never fail the job, whose data is already stored.
+func (h *JobHandler) recordClone(ctx context.Context, repoID int64) {
+	if err := h.clones.Record(ctx, repoID); err != nil {
+		log.Printf("Failed to record clone storage of repository %d: %v", repoID, err)
+	}
+}
+
 // handleDiscoverJob fetches repositories from a provider and stores them in the DB
 func (h *JobHandler) handleDiscoverJob(ctx context.Context, job *queue.Job) error {
 	userIDRaw, ok := job.Payload["user_id"]
@@ -173,17 +306,7 @@ func (h *JobHandler) handleDiscoverJob(ctx context.Context, job *queue.Job) erro
 	}
 
 	// 3. Fetch repositories
-	token := &oauth2.Token{
-		AccessToken: *identity.AccessToken,
-	}
-	if identity.RefreshToken != nil {
-		token.RefreshToken = *identity.RefreshToken
-	}
-	if identity.TokenExpiry != nil {
-		token.Expiry = *identity.TokenExpiry
-	}
-
-	repos, err := p.FetchRepositories(ctx, token)
+	repos, err := p.FetchRepositories(ctx, identityToken(identity))
 	if err != nil {
 		return fmt.Errorf("failed to fetch repositories from %s: %w", providerStr, err)
 	}
@@ -2,13 +2,17 @@ package worker
 
 import (
 	"context"
+	"strings"
 	"testing"
 	"time"
 
 	"git-repository-visualizer/internal/auth"
 	"git-repository-visualizer/internal/database"
+	"git-repository-visualizer/internal/git"
 	"git-repository-visualizer/internal/queue"
+	"git-repository-visualizer/internal/storage"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/pashagolub/pgxmock/v3"
 	"golang.org/x/oauth2"
 )
@@ -28,6 +32,9 @@ func (m *mockProvider) FetchProfile(ctx context.Context, token *oauth2.Token) (*
 func (m *mockProvider) FetchRepositories(ctx context.Context, token *oauth2.Token) ([]auth.RemoteRepo, error) {
 	return m.repos, nil
 }
+func (m *mockProvider) TokenSource(ctx context.Context, token *oauth2.Token) oauth2.TokenSource {
+	return oauth2.StaticTokenSource(token)
+}
 
 func TestHandleDiscoverJob(t *testing.T) {
 	mockPool, err := pgxmock.NewPool()
@@ -46,7 +53,7 @@ func TestHandleDiscoverJob(t *testing.T) {
 	}
 	registry.Register(mp)
 
-	handler := NewJobHandler(db, "/tmp", registry)
+	handler := NewJobHandler(db, storage.NewManager(db, "/tmp", 0), registry, git.NewRegistry(), git.ProcessOptions{})
 	ctx := context.Background()
 
 	userID := int64(42)
@@ -71,8 +78,9 @@ func TestHandleDiscoverJob(t *testing.T) {
 		WillReturnError(database.ErrNotFound) // Using a constant if available or string
 
 	// 3. Create repo
+	name, description, provider := "test/repo1", "", "mock"
 	mockPool.ExpectQuery("INSERT INTO repositories").
-		WithArgs("url1", database.StatusDiscovered, "main", &userID, "test/repo1", "", false, "mock").
+		WithArgs("url1", database.StatusDiscovered, "main", &userID, &name, &description, false, &provider, database.BranchModeDefault, []string{}, false, 0, (*time.Time)(nil), false, []string{}).
 		WillReturnRows(pgxmock.NewRows([]string{"id", "created_at", "updated_at"}).
 			AddRow(int64(100), time.Now(), time.Now()))
 
@@ -85,3 +93,64 @@ func TestHandleDiscoverJob(t *testing.T) {
 		t.Errorf("unmet expectations: %v", err)
 	}
 }
+
+func TestHandleUpdateJobWithoutIndex(t *testing.T) {
+	localPath, lastCommit := "/tmp/repo", "abc123"
+	tests := []struct {
+		name       string
+		localPath  *string
+		lastCommit *string
+	}{
+		{"never cloned", nil, &lastCommit},
+		{"never indexed", &localPath, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockPool, err := pgxmock.NewPool()
+			if err != nil {
+				t.Fatalf("failed to create mock pool: %v", err)
+			}
+			defer mockPool.Close()
+
+			db := database.NewTestDB(mockPool)
+			// The empty git registry supports no URL, so the job fails before cloning
+			handler := NewJobHandler(db, storage.NewManager(db, t.TempDir(), 0), auth.NewRegistry(), git.NewRegistry(), git.ProcessOptions{})
+			repoID := int64(7)
+
+			expectRepository := func() {
+				mockPool.ExpectExec("UPDATE repositories").
+					WithArgs(database.StatusIndexing, repoID).
+					WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+				mockPool.ExpectQuery("SELECT id, url, local_path, default_branch, status, last_indexed_at, created_at, updated_at, user_id, name, description, is_private, provider").
+					WithArgs(repoID).
+					WillReturnRows(pgxmock.NewRows([]string{"id", "url", "local_path", "default_branch", "status", "last_indexed_at", "created_at", "updated_at", "user_id", "name", "description", "is_private", "provider", "last_indexed_commit", "branch_mode", "branches", "first_parent", "clone_depth", "clone_since", "single_branch", "history_truncated", "bot_patterns"}).
+						AddRow(repoID, "https://example.com/repo.git", tt.localPath, "main", database.StatusCompleted, nil, time.Now(), time.Now(), nil, nil, nil, false, nil, tt.lastCommit, database.BranchModeDefault, []string{}, false, 0, nil, false, false, []string{}))
+			}
+
+			// 1. The update job finds nothing to update from
+			expectRepository()
+
+			// 2. The full index starts over
+			expectRepository()
+			mockPool.ExpectQuery("FROM repository_deploy_keys").
+				WithArgs(repoID).
+				WillReturnError(pgx.ErrNoRows)
+			mockPool.ExpectQuery("FROM signing_keys").
+				WithArgs(repoID).
+				WillReturnRows(pgxmock.NewRows([]string{"id", "repository_id", "key_type", "public_key", "fingerprint", "name", "created_at"}))
+			mockPool.ExpectExec("UPDATE repositories").
+				WithArgs(database.StatusFailed, repoID).
+				WillReturnResult(pgxmock.NewResult("UPDATE", 1))
+
+			err = handler.HandleJob(context.Background(), &queue.Job{Type: queue.JobTypeUpdate, RepositoryID: repoID})
+			if err == nil || !strings.Contains(err.Error(), "failed to index repository") {
+				t.Errorf("expected the full index to fail, got %v", err)
+			}
+
+			if err := mockPool.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
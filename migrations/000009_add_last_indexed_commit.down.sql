@@ -0,0 +1,2 @@
+-- Remove last_indexed_commit from repositories
+ALTER TABLE repositories DROP COLUMN last_indexed_commit;
@@ -0,0 +1,3 @@
+-- Track the HEAD commit of the last completed index so updates can be incremental
+ALTER TABLE repositories
+ADD COLUMN last_indexed_commit TEXT;
@@ -0,0 +1,2 @@
+-- Drop deploy keys table
+DROP TABLE repository_deploy_keys;
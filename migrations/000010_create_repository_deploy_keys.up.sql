@@ -0,0 +1,11 @@
+-- Per-repository SSH deploy keys used to clone private repositories over SSH
+CREATE TABLE repository_deploy_keys (
+    repository_id INTEGER PRIMARY KEY REFERENCES repositories(id) ON DELETE CASCADE,
+    private_key TEXT NOT NULL,
+    passphrase TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+-- Trigger to update updated_at for deploy keys
+CREATE TRIGGER update_repository_deploy_keys_updated_at BEFORE
+UPDATE ON repository_deploy_keys FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,4 @@
+-- Remove rename/copy tracking from commit_files
+DROP INDEX IF EXISTS idx_commit_files_renames;
+ALTER TABLE commit_files DROP COLUMN old_path,
+    DROP COLUMN change_type;
@@ -0,0 +1,7 @@
+-- Record how each file changed so history can follow renames and copies
+ALTER TABLE commit_files
+ADD COLUMN change_type TEXT NOT NULL DEFAULT 'modified',
+    ADD COLUMN old_path TEXT;
+-- Lookup of renames by source path when resolving file lineage
+CREATE INDEX idx_commit_files_renames ON commit_files(repository_id, old_path)
+WHERE old_path IS NOT NULL;
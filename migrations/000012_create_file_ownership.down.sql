@@ -0,0 +1,2 @@
+-- Drop blame ownership table
+DROP TABLE IF EXISTS file_ownership;
@@ -0,0 +1,12 @@
+-- Surviving lines per author and file at HEAD, computed with git blame
+CREATE TABLE file_ownership (
+    id BIGSERIAL PRIMARY KEY,
+    repository_id BIGINT NOT NULL,
+    file_path TEXT NOT NULL,
+    author_email TEXT NOT NULL,
+    author_name TEXT NOT NULL,
+    lines INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(repository_id, file_path, author_email)
+);
+-- Index for ownership matrix and bus factor queries
+CREATE INDEX idx_file_ownership_repository_author ON file_ownership(repository_id, author_email);
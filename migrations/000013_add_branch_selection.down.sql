@@ -0,0 +1,4 @@
+-- Remove branch selection and commit branch membership
+DROP TABLE IF EXISTS commit_branches;
+ALTER TABLE repositories DROP COLUMN branches,
+    DROP COLUMN branch_mode;
@@ -0,0 +1,13 @@
+-- Which branches are indexed: the default branch, a list of branches, or all branches
+ALTER TABLE repositories
+ADD COLUMN branch_mode TEXT NOT NULL DEFAULT 'default',
+    ADD COLUMN branches TEXT [] NOT NULL DEFAULT '{}';
+-- Branches each indexed commit is reachable from
+CREATE TABLE commit_branches (
+    repository_id BIGINT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    branch TEXT NOT NULL,
+    PRIMARY KEY (repository_id, branch, commit_hash)
+);
+-- Index for filtering commits by branch in stats queries
+CREATE INDEX idx_commit_branches_commit ON commit_branches(repository_id, commit_hash);
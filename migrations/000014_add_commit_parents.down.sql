@@ -0,0 +1,4 @@
+-- Remove merge awareness
+ALTER TABLE repositories DROP COLUMN first_parent;
+ALTER TABLE commits DROP COLUMN is_merge,
+    DROP COLUMN parent_hashes;
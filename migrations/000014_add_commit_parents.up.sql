@@ -0,0 +1,7 @@
+-- Parent hashes of each commit so merges can be told apart from regular commits
+ALTER TABLE commits
+ADD COLUMN parent_hashes TEXT [] NOT NULL DEFAULT '{}',
+    ADD COLUMN is_merge BOOLEAN NOT NULL DEFAULT FALSE;
+-- Analyse only the first-parent (mainline) history of a repository
+ALTER TABLE repositories
+ADD COLUMN first_parent BOOLEAN NOT NULL DEFAULT FALSE;
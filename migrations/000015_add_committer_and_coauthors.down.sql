@@ -0,0 +1,5 @@
+-- Remove committer identity and co-authors
+DROP TABLE IF EXISTS commit_coauthors;
+ALTER TABLE commits DROP COLUMN committer_at,
+    DROP COLUMN committer_name,
+    DROP COLUMN committer_email;
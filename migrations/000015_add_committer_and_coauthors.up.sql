@@ -0,0 +1,16 @@
+-- Committer identity, which differs from the author for rebased, cherry-picked or applied patches
+ALTER TABLE commits
+ADD COLUMN committer_email TEXT NOT NULL DEFAULT '',
+    ADD COLUMN committer_name TEXT NOT NULL DEFAULT '',
+    ADD COLUMN committer_at TIMESTAMP WITH TIME ZONE;
+-- Co-authors credited through Co-authored-by trailers
+CREATE TABLE commit_coauthors (
+    id BIGSERIAL PRIMARY KEY,
+    repository_id BIGINT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    email TEXT NOT NULL,
+    name TEXT NOT NULL,
+    UNIQUE(repository_id, commit_hash, email)
+);
+-- Index for crediting co-authors per person
+CREATE INDEX idx_commit_coauthors_repository_email ON commit_coauthors(repository_id, email);
@@ -0,0 +1,4 @@
+-- Remove file classification
+ALTER TABLE files DROP COLUMN is_documentation,
+    DROP COLUMN is_generated,
+    DROP COLUMN is_vendored;
@@ -0,0 +1,5 @@
+-- Classification of files at HEAD so stats can leave out non-source files
+ALTER TABLE files
+ADD COLUMN is_vendored BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN is_generated BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN is_documentation BOOLEAN NOT NULL DEFAULT false;
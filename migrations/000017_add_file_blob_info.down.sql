@@ -0,0 +1,5 @@
+-- Remove blob details
+DROP INDEX IF EXISTS idx_files_repository_size;
+ALTER TABLE files DROP COLUMN blob_hash,
+    DROP COLUMN size_bytes,
+    DROP COLUMN is_binary;
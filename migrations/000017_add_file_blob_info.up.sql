@@ -0,0 +1,7 @@
+-- Blob details of files at HEAD
+ALTER TABLE files
+ADD COLUMN is_binary BOOLEAN NOT NULL DEFAULT false,
+    ADD COLUMN size_bytes BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN blob_hash TEXT NOT NULL DEFAULT '';
+-- Index for largest files queries
+CREATE INDEX idx_files_repository_size ON files(repository_id, size_bytes DESC);
@@ -0,0 +1,4 @@
+-- Remove line breakdown
+ALTER TABLE files DROP COLUMN blank_lines,
+    DROP COLUMN comment_lines,
+    DROP COLUMN code_lines;
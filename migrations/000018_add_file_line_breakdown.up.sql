@@ -0,0 +1,5 @@
+-- Code, comment and blank line counts of files at HEAD
+ALTER TABLE files
+ADD COLUMN code_lines INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN comment_lines INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN blank_lines INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Drop snapshots table
+DROP TABLE IF EXISTS snapshots;
@@ -0,0 +1,13 @@
+-- Sampled historical states of the default branch, per language, for codebase growth
+CREATE TABLE snapshots (
+    id BIGSERIAL PRIMARY KEY,
+    repository_id BIGINT NOT NULL,
+    period_start DATE NOT NULL,
+    commit_hash TEXT NOT NULL,
+    committed_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    language TEXT NOT NULL,
+    files INTEGER NOT NULL DEFAULT 0,
+    lines INTEGER NOT NULL DEFAULT 0,
+    code_lines INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(repository_id, period_start, language)
+);
@@ -0,0 +1,3 @@
+-- Drop tags tables
+DROP TABLE IF EXISTS release_commits;
+DROP TABLE IF EXISTS tags;
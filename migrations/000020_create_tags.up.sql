@@ -0,0 +1,20 @@
+-- Annotated and lightweight tags with the commit they point at
+CREATE TABLE tags (
+    id BIGSERIAL PRIMARY KEY,
+    repository_id BIGINT NOT NULL,
+    name TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    is_annotated BOOLEAN NOT NULL DEFAULT false,
+    message TEXT,
+    tagger_name TEXT,
+    tagger_email TEXT,
+    tagged_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    UNIQUE(repository_id, name)
+);
+-- Commits first released in each tag: reachable from it but from no earlier tag
+CREATE TABLE release_commits (
+    repository_id BIGINT NOT NULL,
+    tag_name TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    PRIMARY KEY (repository_id, tag_name, commit_hash)
+);
@@ -0,0 +1,5 @@
+-- Remove clone options
+ALTER TABLE repositories DROP COLUMN clone_depth,
+    DROP COLUMN clone_since,
+    DROP COLUMN single_branch,
+    DROP COLUMN history_truncated;
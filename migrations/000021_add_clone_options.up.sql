@@ -0,0 +1,8 @@
+-- Clone depth, history cutoff date and single-branch mode for very large repositories
+ALTER TABLE repositories
+ADD COLUMN clone_depth INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN clone_since TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN single_branch BOOLEAN NOT NULL DEFAULT FALSE;
+-- Whether the indexed history stops before the first commit because of those options
+ALTER TABLE repositories
+ADD COLUMN history_truncated BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Drop indexing progress
+DROP TABLE IF EXISTS indexing_progress;
@@ -0,0 +1,11 @@
+-- Position of the running (or last) index of each repository, for progress bars
+CREATE TABLE indexing_progress (
+    repository_id BIGINT PRIMARY KEY,
+    phase TEXT NOT NULL,
+    percent REAL NOT NULL DEFAULT 0,
+    commits_processed INTEGER NOT NULL DEFAULT 0,
+    commits_total INTEGER NOT NULL DEFAULT 0,
+    eta_seconds INTEGER,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
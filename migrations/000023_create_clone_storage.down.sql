@@ -0,0 +1,2 @@
+-- Drop clone storage tracking
+DROP TABLE IF EXISTS clone_storage;
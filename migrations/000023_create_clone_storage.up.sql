@@ -0,0 +1,10 @@
+-- Disk usage of each repository's clone under the worker storage path, for quota and LRU eviction
+CREATE TABLE clone_storage (
+    repository_id BIGINT PRIMARY KEY REFERENCES repositories(id) ON DELETE CASCADE,
+    path TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+-- Index for picking the least recently used clones
+CREATE INDEX idx_clone_storage_last_used_at ON clone_storage(last_used_at);
@@ -0,0 +1,2 @@
+-- Drop identity aliases
+DROP TABLE IF EXISTS identity_aliases;
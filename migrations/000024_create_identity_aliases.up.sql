@@ -0,0 +1,14 @@
+-- Identities resolved to a canonical one per repository, from .mailmap or merged through the API
+CREATE TABLE identity_aliases (
+    id BIGSERIAL PRIMARY KEY,
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    email TEXT NOT NULL,
+    commit_name TEXT NOT NULL DEFAULT '',
+    canonical_email TEXT,
+    canonical_name TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(repository_id, source, email, commit_name)
+);
+-- Index for resolving an identity in stats queries
+CREATE INDEX idx_identity_aliases_repository_email ON identity_aliases(repository_id, email);
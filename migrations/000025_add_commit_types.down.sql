@@ -0,0 +1,4 @@
+-- Drop commit classification
+DROP INDEX IF EXISTS idx_commits_repository_type;
+ALTER TABLE commits DROP COLUMN IF EXISTS commit_type,
+    DROP COLUMN IF EXISTS commit_scope;
@@ -0,0 +1,6 @@
+-- Intent of each commit (Conventional Commits prefix or keyword heuristics) and the area it names
+ALTER TABLE commits
+ADD COLUMN commit_type TEXT NOT NULL DEFAULT 'other',
+    ADD COLUMN commit_scope TEXT;
+-- Index for the commit type mix
+CREATE INDEX idx_commits_repository_type ON commits(repository_id, commit_type);
@@ -0,0 +1,4 @@
+-- Drop bot detection
+DROP INDEX IF EXISTS idx_contributors_repository_bots;
+ALTER TABLE repositories DROP COLUMN IF EXISTS bot_patterns;
+ALTER TABLE contributors DROP COLUMN IF EXISTS is_bot;
@@ -0,0 +1,14 @@
+-- Automated accounts (dependabot, renovate, CI) are flagged so stats can leave them out
+ALTER TABLE contributors
+ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT false;
+-- Email or name globs of a repository's own bot accounts, on top of the built-in heuristics
+ALTER TABLE repositories
+ADD COLUMN bot_patterns TEXT[] NOT NULL DEFAULT '{}';
+-- Flag the obvious bots of existing indexes; the next index applies the full heuristics
+UPDATE contributors
+SET is_bot = true
+WHERE name ILIKE '%[bot]'
+    OR email ILIKE '%[bot]@%';
+-- Index for the bot filter of stats queries
+CREATE INDEX idx_contributors_repository_bots ON contributors(repository_id, email)
+WHERE is_bot;
@@ -0,0 +1,21 @@
+-- Drop staged index data and restore the constraints
+DELETE FROM files WHERE repository_id < 0;
+DELETE FROM file_ownership WHERE repository_id < 0;
+DELETE FROM commit_coauthors WHERE repository_id < 0;
+DELETE FROM commit_branches WHERE repository_id < 0;
+DELETE FROM snapshots WHERE repository_id < 0;
+DELETE FROM tags WHERE repository_id < 0;
+DELETE FROM release_commits WHERE repository_id < 0;
+DELETE FROM commit_files WHERE repository_id < 0;
+-- Staged rows and rows of deleted repositories would violate the foreign keys
+DELETE FROM contributors
+WHERE repository_id NOT IN (SELECT id FROM repositories);
+DELETE FROM commits
+WHERE repository_id NOT IN (SELECT id FROM repositories);
+ALTER TABLE commit_files DROP CONSTRAINT IF EXISTS commit_files_repository_commit_path_key;
+ALTER TABLE commit_files
+ADD CONSTRAINT commit_files_commit_hash_file_path_key UNIQUE (commit_hash, file_path);
+ALTER TABLE commits
+ADD CONSTRAINT commits_repository_id_fkey FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE;
+ALTER TABLE contributors
+ADD CONSTRAINT contributors_repository_id_fkey FOREIGN KEY (repository_id) REFERENCES repositories(id) ON DELETE CASCADE;
@@ -0,0 +1,8 @@
+-- A full index writes its data under the negated repository ID and promotes it in one transaction,
+-- so the data tables cannot reference repositories. DeleteRepository removes their rows.
+ALTER TABLE contributors DROP CONSTRAINT IF EXISTS contributors_repository_id_fkey;
+ALTER TABLE commits DROP CONSTRAINT IF EXISTS commits_repository_id_fkey;
+-- Staged and live rows hold the same commits
+ALTER TABLE commit_files DROP CONSTRAINT IF EXISTS commit_files_commit_hash_file_path_key;
+ALTER TABLE commit_files
+ADD CONSTRAINT commit_files_repository_commit_path_key UNIQUE (repository_id, commit_hash, file_path);
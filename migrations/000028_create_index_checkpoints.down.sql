@@ -0,0 +1,2 @@
+-- Drop index checkpoints
+DROP TABLE IF EXISTS index_checkpoints;
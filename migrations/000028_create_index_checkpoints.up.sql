@@ -0,0 +1,9 @@
+-- How far the history phase of a repository's unfinished full index got, so a retried index resumes
+CREATE TABLE index_checkpoints (
+    repository_id BIGINT PRIMARY KEY REFERENCES repositories(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,
+    last_commit TEXT NOT NULL,
+    batches INTEGER NOT NULL DEFAULT 0,
+    commits_processed INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,5 @@
+-- Drop commit signatures
+DROP TABLE IF EXISTS signing_keys;
+ALTER TABLE commits DROP COLUMN IF EXISTS signature_type,
+    DROP COLUMN IF EXISTS signature_status,
+    DROP COLUMN IF EXISTS signature_key;
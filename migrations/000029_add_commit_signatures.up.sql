@@ -0,0 +1,16 @@
+-- Signature of each commit and whether a trusted key of the repository made it
+ALTER TABLE commits
+ADD COLUMN signature_type TEXT,
+    ADD COLUMN signature_status TEXT NOT NULL DEFAULT 'unsigned',
+    ADD COLUMN signature_key TEXT;
+-- Public keys trusted to sign the commits of a repository
+CREATE TABLE signing_keys (
+    id BIGSERIAL PRIMARY KEY,
+    repository_id BIGINT NOT NULL REFERENCES repositories(id) ON DELETE CASCADE,
+    key_type TEXT NOT NULL,
+    public_key TEXT NOT NULL,
+    fingerprint TEXT NOT NULL,
+    name TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE(repository_id, fingerprint)
+);
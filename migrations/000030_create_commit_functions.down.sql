@@ -0,0 +1,2 @@
+-- Drop function-level changes
+DROP TABLE IF EXISTS commit_functions;
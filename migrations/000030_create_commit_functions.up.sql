@@ -0,0 +1,13 @@
+-- Go functions and methods touched by each commit, for function-level churn. Rows are staged
+-- like commit_files, so they do not reference repositories.
+CREATE TABLE commit_functions (
+    id BIGSERIAL PRIMARY KEY,
+    repository_id BIGINT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    file_path TEXT NOT NULL,
+    function_name TEXT NOT NULL,
+    additions INTEGER NOT NULL DEFAULT 0,
+    deletions INTEGER NOT NULL DEFAULT 0,
+    UNIQUE(repository_id, commit_hash, file_path, function_name)
+);
+CREATE INDEX idx_commit_functions_repo_file ON commit_functions(repository_id, file_path);
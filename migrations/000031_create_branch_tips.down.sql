@@ -0,0 +1,2 @@
+-- Drop the indexed branch tips
+DROP TABLE IF EXISTS branch_tips;
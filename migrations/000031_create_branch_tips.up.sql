@@ -0,0 +1,8 @@
+-- Commit each indexed branch pointed at, so an update only adds the commits a branch gained.
+-- Rows are staged like commit_branches, so they do not reference repositories.
+CREATE TABLE branch_tips (
+    repository_id BIGINT NOT NULL,
+    branch TEXT NOT NULL,
+    commit_hash TEXT NOT NULL,
+    PRIMARY KEY (repository_id, branch)
+);
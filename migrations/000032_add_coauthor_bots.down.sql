@@ -0,0 +1,2 @@
+-- Drop the bot flag of co-authors
+ALTER TABLE commit_coauthors DROP COLUMN IF EXISTS is_bot;
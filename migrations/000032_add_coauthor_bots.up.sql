@@ -0,0 +1,8 @@
+-- Co-authors are flagged like contributors, so stats crediting them can leave bots out
+ALTER TABLE commit_coauthors
+ADD COLUMN is_bot BOOLEAN NOT NULL DEFAULT false;
+-- Flag the obvious bots of existing indexes; the next index applies the full heuristics
+UPDATE commit_coauthors
+SET is_bot = true
+WHERE name ILIKE '%[bot]'
+    OR email ILIKE '%[bot]@%';
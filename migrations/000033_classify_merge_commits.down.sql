@@ -0,0 +1,4 @@
+-- Merge commits go back to the chore type; the next index restores their keyword classification
+UPDATE commits
+SET commit_type = 'chore'
+WHERE commit_type = 'merge';
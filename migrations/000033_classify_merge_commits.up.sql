@@ -0,0 +1,6 @@
+-- Merge commits have their own type instead of one guessed from the merged branch's name
+UPDATE commits
+SET commit_type = 'merge',
+    commit_scope = NULL
+WHERE is_merge
+    OR message LIKE 'Merge %';